@@ -1,122 +1,401 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
+	"api_auto_test/pkg/client"
 	"api_auto_test/pkg/config"
 	"api_auto_test/pkg/executor"
+	"api_auto_test/pkg/logger"
 	"api_auto_test/pkg/report"
 )
 
 var (
-	configFile   = flag.String("config", "testdata/api_tests.yaml", "配置文件路径")
+	configFile   = flag.String("config", "testdata/api_tests.yaml", "配置文件路径，也可以是目录或通配符（如 configs/*.yaml）")
 	baseURL      = flag.String("url", "", "基础URL（覆盖配置文件）")
 	version      = flag.String("version", "", "API版本（覆盖配置文件）")
+	envName      = flag.String("env", "", "使用配置文件 environments 中的指定环境覆盖基础配置")
 	certFile     = flag.String("cert", "", "客户端证书文件路径")
 	keyFile      = flag.String("key", "", "客户端密钥文件路径")
 	caFile       = flag.String("ca", "", "CA证书文件路径")
-	outputFormat = flag.String("format", "console", "输出格式: console, json, html")
-	outputFile   = flag.String("output", "", "输出文件路径（用于json和html格式）")
+	proxy        = flag.String("proxy", "", "HTTP 代理地址（覆盖配置文件），env 表示读取环境变量")
+	insecure     = flag.Bool("insecure", false, "跳过服务器证书校验（默认关闭，仅用于测试环境）")
+	outputFormat = flag.String("format", "console", "输出格式: console, json, html, csv, junit, allure")
+	outputFile   = flag.String("output", "", "输出文件路径（用于json、html、csv和junit格式；allure格式时为输出目录）")
+	outputDir    = flag.String("output-dir", "allure-results", "输出目录路径（用于allure格式）")
 	concurrent   = flag.Bool("concurrent", false, "是否并发执行测试")
 	maxWorkers   = flag.Int("workers", 5, "并发执行时的最大工作线程数")
+	repeatCount  = flag.Int("repeat", 1, "重复执行整个测试套件的轮数，合并各轮结果并列出不稳定的测试")
+	soakTime     = flag.Duration("duration", 0, "在该时长内重复执行整个测试套件（如 5m），与 -repeat 同时使用时先达到任一限制即停止")
+	failFast     = flag.Bool("fail-fast", false, "出现首个失败后中止执行剩余测试")
+	dryRun       = flag.Bool("dry-run", false, "只解析变量并组装请求，不实际发送，在报告中显示组装的请求")
+	strictVars   = flag.Bool("strict-vars", false, "请求中引用了无法解析的变量时判定测试失败，而不是发送原样的 {{...}}")
+	updateGold   = flag.Bool("update-golden", false, "用实际响应体重写测试中 body_golden 指定的文件")
+	randSeed     = flag.Int64("seed", 0, "随机种子（覆盖配置文件），非 0 时 {{$random.*}} 每次运行生成相同的值，仅用于调试")
+	debugMode    = flag.Bool("debug", false, "输出实际发送的请求行、请求头和请求体（敏感信息已隐藏），等同于 -log-level debug")
+	logLevel     = flag.String("log-level", "info", "日志级别: debug, info, warn, error（日志输出到标准错误）")
 	testName     = flag.String("test", "", "只运行指定名称的测试")
+	tagExpr      = flag.String("tags", "", "按标签筛选测试，逗号分隔，! 前缀表示排除，如 smoke,!slow")
+	suiteName    = flag.String("suite", "", "只运行指定套件中的测试（包括其依赖）")
 	listTests    = flag.Bool("list", false, "列出所有测试名称")
+	showDisabled = flag.Bool("show-disabled", false, "列出测试时包含被禁用的测试")
+	watchMode    = flag.Bool("watch", false, "监听配置文件变化并自动重新运行测试")
+	baselineFile = flag.String("baseline", "", "与之前保存的 JSON 报告比较，显示新失败、新通过和明显变慢的测试")
+	webhookURL   = flag.String("webhook", "", "测试完成后将摘要 POST 到该地址")
+	webhookType  = flag.String("webhook-format", "", "通知格式: 为空时发送通用 JSON 摘要，slack 发送 Slack 消息")
+	graphFile    = flag.String("graph", "", "将测试依赖关系图导出为 Graphviz DOT 文件（不执行测试）")
+	validateOnly = flag.Bool("validate", false, "只检查配置（依赖、重复名称、循环依赖、验证器和 body_schema 类型），不执行测试")
+	saveVars     = flag.String("save-vars", "", "运行结束后将捕获的变量保存到该 JSON 文件，供后续运行通过 -load-vars 使用")
+	loadVars     = flag.String("load-vars", "", "从 JSON 文件预加载变量（如上一次运行 -save-vars 保存的文件）")
+	waitFor      = flag.String("wait-for", "", "执行测试之前轮询该地址，直到返回 -wait-status 状态码（用于等待刚启动的服务就绪）")
+	waitTimeout  = flag.Duration("wait-timeout", 60*time.Second, "-wait-for 等待的最长时间，超时后不执行测试并返回错误")
+	waitStatus   = flag.Int("wait-status", 200, "-wait-for 视为就绪的状态码")
 )
 
+// appLogger 命令行工具的日志记录器，由 -log-level 控制
+var appLogger = logger.Nop()
+
 func main() {
 	flag.Parse()
 
-	if err := run(); err != nil {
+	level, err := logger.ParseLevel(*logLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *debugMode {
+		level = logger.LevelDebug
+	}
+	appLogger = logger.New(os.Stderr, level)
+
+	if *watchMode {
+		if err := watchAndRun(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	failed, err := run()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+
+	// 如果有失败的测试，返回错误退出码
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// run 执行一次测试，返回是否有失败的测试
+// -config 为目录或通配符时依次执行每个配置文件，并生成按文件分组的合并报告
+func run() (bool, error) {
+	files, err := resolveConfigFiles(*configFile)
+	if err != nil {
+		return false, err
+	}
+	if *repeatCount < 1 {
+		return false, fmt.Errorf("-repeat must be at least 1, got %d", *repeatCount)
+	}
+	if *graphFile != "" && len(files) > 1 {
+		return false, fmt.Errorf("-graph requires a single config file, got %d", len(files))
+	}
+
+	var preloaded map[string]interface{}
+	if *loadVars != "" {
+		preloaded, err = loadVariablesFile(*loadVars)
+		if err != nil {
+			return false, err
+		}
+	}
+	captured := make(map[string]interface{})
+
+	reports := make([]*executor.TestReport, 0, len(files))
+	for _, file := range files {
+		if len(files) > 1 {
+			appLogger.Infof("Loading config %s", file)
+		}
+		testReport, err := runConfig(file, preloaded, captured)
+		if err != nil {
+			if len(files) > 1 {
+				return false, fmt.Errorf("%s: %w", file, err)
+			}
+			return false, err
+		}
+		if testReport != nil {
+			reports = append(reports, testReport)
+		}
+	}
+
+	// 列出测试、导出依赖图或只校验配置时不生成报告
+	if len(reports) == 0 {
+		return false, nil
+	}
+
+	if *saveVars != "" {
+		if err := saveVariablesFile(*saveVars, captured); err != nil {
+			return false, err
+		}
+		appLogger.Infof("Variables saved to: %s", *saveVars)
+	}
+
+	testReport := reports[0]
+	if len(reports) > 1 {
+		testReport = executor.MergeReports(getConfigFileName(*configFile), reports)
+	}
+
+	// 生成报告
+	if err := generateReport(testReport); err != nil {
+		return false, err
+	}
+
+	// 执行单个测试时不根据结果设置退出码
+	if *testName != "" {
+		return false, nil
+	}
+	return testReport.Failed(), nil
+}
+
+// resolveConfigFiles 解析 -config 参数：单个文件、目录（其中所有 *.yaml/*.yml/*.json）或通配符
+func resolveConfigFiles(pattern string) ([]string, error) {
+	if strings.ContainsAny(pattern, "*?[") {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config pattern '%s': %w", pattern, err)
+		}
+		files := filterConfigFiles(matches)
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no config files match '%s'", pattern)
+		}
+		return files, nil
+	}
+
+	info, err := os.Stat(pattern)
+	if err != nil || !info.IsDir() {
+		// 单个文件，读取错误由加载器报告
+		return []string{pattern}, nil
+	}
+
+	entries, err := os.ReadDir(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config directory: %w", err)
+	}
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			paths = append(paths, filepath.Join(pattern, entry.Name()))
+		}
+	}
+	files := filterConfigFiles(paths)
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no *.yaml, *.yml or *.json files in '%s'", pattern)
+	}
+	return files, nil
+}
+
+// filterConfigFiles 筛选 YAML 和 JSON 配置文件并按路径排序
+func filterConfigFiles(paths []string) []string {
+	files := make([]string, 0, len(paths))
+	for _, path := range paths {
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext == ".yaml" || ext == ".yml" || ext == ".json" {
+			files = append(files, path)
+		}
+	}
+	sort.Strings(files)
+	return files
 }
 
-func run() error {
+// runConfig 加载并执行单个配置文件，列出测试、导出依赖图或只校验配置时返回 nil 报告
+// preloaded 为 -load-vars 预加载的变量，执行结束后捕获的变量会写入 captured
+func runConfig(path string, preloaded, captured map[string]interface{}) (*executor.TestReport, error) {
 	// 加载配置
-	loader := config.NewLoader(*configFile)
+	loader := config.NewLoader(path)
 	cfg, err := loader.LoadWithVersion(*version)
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// 加载时已完成配置校验，只校验时不执行测试
+	if *validateOnly {
+		fmt.Printf("Config %s is valid (%d tests)\n", path, len(cfg.APIs))
+		return nil, nil
+	}
+
+	// 应用环境配置，命令行参数的优先级更高
+	if err := config.ApplyEnvironment(cfg, *envName); err != nil {
+		return nil, err
 	}
 
 	// 合并命令行参数
 	cfg = config.MergeConfig(cfg, *baseURL, *certFile, *keyFile, *caFile, *version)
+	if *proxy != "" {
+		cfg.Proxy = *proxy
+	}
+	if *randSeed != 0 {
+		cfg.RandSeed = *randSeed
+	}
+	if *insecure {
+		cfg.Certificate.InsecureSkipVerify = true
+	}
+	if cfg.Certificate.InsecureSkipVerify {
+		appLogger.Warnf("TLS certificate verification is disabled")
+	}
+
+	// 按套件筛选测试
+	if *suiteName != "" {
+		if err := config.SelectSuite(cfg, *suiteName); err != nil {
+			return nil, err
+		}
+	}
+
+	// 按标签筛选测试
+	if err := config.SelectTags(cfg, *tagExpr); err != nil {
+		return nil, err
+	}
 
 	// 创建执行器
 	exec, err := executor.NewExecutor(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create executor: %w", err)
+		return nil, fmt.Errorf("failed to create executor: %w", err)
 	}
+	exec.SetFailFast(*failFast)
+	exec.SetStrictVars(*strictVars)
+	exec.SetDryRun(*dryRun)
+	exec.SetUpdateGolden(*updateGold)
+	exec.SetLogger(appLogger)
+	exec.SetVariables(preloaded)
+	defer func() {
+		for name, value := range exec.CapturedVariables() {
+			captured[name] = value
+		}
+	}()
 
 	// 列出所有测试
 	if *listTests {
 		fmt.Println("Available tests:")
-		for i, name := range exec.GetTestNames() {
+		for i, name := range exec.GetTestNames(*showDisabled) {
 			fmt.Printf("  %d. %s\n", i+1, name)
 		}
-		return nil
+		return nil, nil
 	}
 
-	// 执行单个测试
-	if *testName != "" {
-		result, err := exec.ExecuteByName(*testName)
+	// 导出执行计划的依赖关系图
+	if *graphFile != "" {
+		file, err := os.Create(*graphFile)
 		if err != nil {
-			return fmt.Errorf("failed to execute test: %w", err)
-		}
-
-		// 创建单测试报告
-		testReport := &executor.TestReport{
-			TotalTests:     1,
-			Results:        []executor.TestResult{*result},
-			StartTime:      result.ExecutedAt,
-			EndTime:        result.ExecutedAt.Add(result.Duration),
-			Duration:       result.Duration,
-			Version:        cfg.Version,
-			BaseURL:        cfg.BaseURL,
-			ConfigFileName: getConfigFileName(*configFile),
-		}
-		if result.Passed {
-			testReport.PassedTests = 1
-		} else {
-			testReport.FailedTests = 1
+			return nil, fmt.Errorf("failed to create graph file: %w", err)
+		}
+		defer file.Close()
+
+		if err := exec.WriteDOT(file); err != nil {
+			return nil, err
 		}
+		appLogger.Infof("Dependency graph saved to: %s", *graphFile)
+		return nil, nil
+	}
 
-		return generateReport(testReport)
+	// 等待被测服务就绪，探测请求使用与测试相同的证书与代理配置
+	if *waitFor != "" {
+		if err := waitForService(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	// 执行单个测试，依赖的测试会先执行
+	if *testName != "" {
+		testReport, err := exec.ExecuteByName(*testName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute test: %w", err)
+		}
+		if testReport.Error != nil {
+			return nil, testReport.Error
+		}
+		testReport.ConfigFileName = configFileName(*configFile, path)
+		return testReport, nil
 	}
 
 	// 执行所有测试
-	var testReport *executor.TestReport
-	if *concurrent {
-		fmt.Printf("Running %d tests concurrently (max workers: %d)...\n", len(cfg.APIs), *maxWorkers)
-		testReport = exec.ExecuteConcurrent(*maxWorkers)
-	} else {
-		fmt.Printf("Running %d tests sequentially...\n", len(cfg.APIs))
-		testReport = exec.Execute()
+	testReport := executeSuite(exec, len(cfg.APIs))
+	if testReport.Error != nil {
+		return nil, testReport.Error
 	}
 
 	// 设置配置文件名称
-	testReport.ConfigFileName = getConfigFileName(*configFile)
+	testReport.ConfigFileName = configFileName(*configFile, path)
 
-	// 生成报告
-	if err := generateReport(testReport); err != nil {
-		return err
+	return testReport, nil
+}
+
+// waitForService 轮询 -wait-for 地址直到被测服务就绪
+func waitForService(cfg *config.TestConfig) error {
+	probe, err := client.NewHTTPClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
 	}
+	appLogger.Infof("Waiting for %s to become ready (timeout %s)...", *waitFor, *waitTimeout)
+	return probe.WaitForReady(*waitFor, *waitStatus, *waitTimeout)
+}
 
-	// 如果有失败的测试，返回错误退出码
-	if testReport.FailedTests > 0 {
-		os.Exit(1)
+// executeSuite 执行所有测试，指定了 -repeat 或 -duration 时重复执行并合并各轮的结果
+// 开启 -fail-fast 时出现失败的轮次结束后不再继续
+func executeSuite(exec *executor.Executor, count int) *executor.TestReport {
+	execute := func() *executor.TestReport {
+		if *concurrent {
+			appLogger.Infof("Running %d tests concurrently (max workers: %d)...", count, *maxWorkers)
+			return exec.ExecuteConcurrent(*maxWorkers)
+		}
+		appLogger.Infof("Running %d tests sequentially...", count)
+		return exec.Execute()
 	}
 
-	return nil
+	if *repeatCount <= 1 && *soakTime <= 0 {
+		return execute()
+	}
+
+	// 只指定 -duration 时不限制轮数
+	limitByCount := *repeatCount > 1 || *soakTime <= 0
+	deadline := time.Now().Add(*soakTime)
+	reports := make([]*executor.TestReport, 0)
+	for iteration := 1; ; iteration++ {
+		appLogger.Infof("Iteration %d", iteration)
+		testReport := execute()
+		if testReport.Error != nil {
+			return testReport
+		}
+		reports = append(reports, testReport)
+
+		if *failFast && testReport.Failed() {
+			break
+		}
+		if limitByCount && iteration >= *repeatCount {
+			break
+		}
+		if *soakTime > 0 && !time.Now().Before(deadline) {
+			break
+		}
+	}
+	return executor.MergeIterations(reports)
 }
 
 func generateReport(testReport *executor.TestReport) error {
 	reporter := report.NewReporter(testReport)
 
+	// 与基线报告比较
+	if *baselineFile != "" {
+		if err := reporter.CompareWithBaseline(*baselineFile); err != nil {
+			return err
+		}
+	}
+
 	switch *outputFormat {
 	case "console":
 		reporter.PrintConsole()
@@ -128,7 +407,7 @@ func generateReport(testReport *executor.TestReport) error {
 		if err := reporter.SaveJSON(filename); err != nil {
 			return fmt.Errorf("failed to save JSON report: %w", err)
 		}
-		fmt.Printf("JSON report saved to: %s\n", filename)
+		appLogger.Infof("JSON report saved to: %s", filename)
 	case "html":
 		filename := *outputFile
 		if filename == "" {
@@ -137,14 +416,102 @@ func generateReport(testReport *executor.TestReport) error {
 		if err := reporter.SaveHTML(filename); err != nil {
 			return fmt.Errorf("failed to save HTML report: %w", err)
 		}
-		fmt.Printf("HTML report saved to: %s\n", filename)
+		appLogger.Infof("HTML report saved to: %s", filename)
+	case "csv":
+		filename := *outputFile
+		if filename == "" {
+			filename = "test-report.csv"
+		}
+		if err := reporter.SaveCSV(filename); err != nil {
+			return fmt.Errorf("failed to save CSV report: %w", err)
+		}
+		appLogger.Infof("CSV report saved to: %s", filename)
+	case "junit":
+		filename := *outputFile
+		if filename == "" {
+			filename = "test-report.xml"
+		}
+		if err := reporter.SaveJUnit(filename); err != nil {
+			return fmt.Errorf("failed to save JUnit report: %w", err)
+		}
+		appLogger.Infof("JUnit report saved to: %s", filename)
+	case "allure":
+		// -output 同样可以指定 Allure 结果目录，优先于 -output-dir
+		dir := *outputDir
+		if *outputFile != "" {
+			dir = *outputFile
+		}
+		if err := reporter.SaveAllure(dir); err != nil {
+			return fmt.Errorf("failed to save Allure results: %w", err)
+		}
+		appLogger.Infof("Allure results saved to: %s", dir)
 	default:
 		return fmt.Errorf("unknown output format: %s", *outputFormat)
 	}
 
+	// 发送通知失败只给出警告，不影响退出码
+	if *webhookURL != "" {
+		if err := reporter.SendWebhook(*webhookURL, *webhookType); err != nil {
+			appLogger.Warnf("%v", err)
+		} else {
+			appLogger.Infof("Webhook notification sent to: %s", report.WebhookHost(*webhookURL))
+		}
+	}
+
 	return nil
 }
 
+// loadVariablesFile 读取 -save-vars 保存的变量文件
+func loadVariablesFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read variables file: %w", err)
+	}
+	var variables map[string]interface{}
+	if err := json.Unmarshal(data, &variables); err != nil {
+		return nil, fmt.Errorf("failed to parse variables file '%s': %w", path, err)
+	}
+	return variables, nil
+}
+
+// saveVariablesFile 将捕获的变量保存为 JSON 文件
+func saveVariablesFile(path string, variables map[string]interface{}) error {
+	data, err := json.MarshalIndent(variables, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode variables: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to save variables file: %w", err)
+	}
+	return nil
+}
+
+// configFileName 返回报告中标识配置文件的名称：相对于 -config 根目录（目录本身或通配符之前的目录）的路径，不含扩展名
+// 单个配置文件时即为文件名；不同目录下的同名文件（如 a/users.yaml 和 b/users.yaml）得到不同的名称，
+// 避免在合并的报告、不稳定测试和基线比较中混在一起
+func configFileName(pattern, path string) string {
+	rel, err := filepath.Rel(configRoot(pattern), path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return getConfigFileName(path)
+	}
+	return filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel)))
+}
+
+// configRoot 返回 -config 参数的根目录：目录本身、通配符之前的目录或单个文件所在的目录
+func configRoot(pattern string) string {
+	if strings.ContainsAny(pattern, "*?[") {
+		root := pattern
+		for strings.ContainsAny(root, "*?[") {
+			root = filepath.Dir(root)
+		}
+		return root
+	}
+	if info, err := os.Stat(pattern); err == nil && info.IsDir() {
+		return pattern
+	}
+	return filepath.Dir(pattern)
+}
+
 // getConfigFileName 从配置文件路径中提取文件名（不含扩展名）
 func getConfigFileName(configPath string) string {
 	// 获取文件名（不含路径）
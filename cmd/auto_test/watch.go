@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"api_auto_test/pkg/config"
+)
+
+const (
+	// watchPollInterval 检查文件变化的轮询间隔
+	watchPollInterval = 200 * time.Millisecond
+	// watchDebounce 文件停止变化后等待的时间，用于合并连续的多次保存
+	watchDebounce = 300 * time.Millisecond
+)
+
+// fileState 文件状态快照
+type fileState struct {
+	modTime time.Time
+	size    int64
+	exists  bool
+}
+
+// watchAndRun 运行测试后监听配置文件变化并自动重新运行，按 Ctrl-C 退出
+// 只在等待文件变化时处理退出信号，运行测试期间按 Ctrl-C 会直接终止进程
+func watchAndRun() error {
+	for {
+		clearConsole()
+		if _, err := run(); err != nil {
+			appLogger.Errorf("%v", err)
+		}
+
+		files := watchedFiles()
+		appLogger.Infof("Watching %s for changes (press Ctrl-C to exit)...", strings.Join(files, ", "))
+		if !waitForChange(files) {
+			appLogger.Infof("Watch mode stopped")
+			return nil
+		}
+	}
+}
+
+// watchedFiles 获取需要监听的文件列表：配置文件、include 引用的配置片段以及测试引用的
+// body_file、body_golden、json_schema 文件和 hosts_file
+func watchedFiles() []string {
+	configFiles, err := resolveConfigFiles(*configFile)
+	if err != nil {
+		return []string{*configFile}
+	}
+
+	files := make([]string, 0, len(configFiles))
+	seen := make(map[string]bool)
+	add := func(file string) {
+		if file != "" && !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+
+	for _, path := range configFiles {
+		add(path)
+		loader := config.NewLoader(path)
+		cfg, err := loader.Load()
+		for _, file := range loader.Files() {
+			add(file)
+		}
+		if err != nil {
+			continue
+		}
+		for _, tests := range [][]config.APITest{cfg.Setup, cfg.APIs, cfg.Teardown} {
+			for _, test := range tests {
+				add(test.Request.BodyFile)
+				add(test.Response.BodyGolden)
+				add(test.HostsFile)
+				if !strings.HasPrefix(strings.TrimSpace(test.Response.JSONSchema), "{") {
+					add(test.Response.JSONSchema)
+				}
+			}
+		}
+	}
+	return files
+}
+
+// waitForChange 等待任一文件发生变化（带防抖），收到退出信号时返回 false
+func waitForChange(files []string) bool {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	last := snapshotFiles(files)
+	var changedAt time.Time
+
+	for {
+		select {
+		case <-sigCh:
+			return false
+		case <-ticker.C:
+			current := snapshotFiles(files)
+			if !sameSnapshot(last, current) {
+				last = current
+				changedAt = time.Now()
+				continue
+			}
+			if !changedAt.IsZero() && time.Since(changedAt) >= watchDebounce {
+				return true
+			}
+		}
+	}
+}
+
+// snapshotFiles 记录文件的当前状态
+func snapshotFiles(files []string) map[string]fileState {
+	snapshot := make(map[string]fileState, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			snapshot[file] = fileState{}
+			continue
+		}
+		snapshot[file] = fileState{modTime: info.ModTime(), size: info.Size(), exists: true}
+	}
+	return snapshot
+}
+
+// sameSnapshot 比较两次快照是否一致
+func sameSnapshot(a, b map[string]fileState) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for file, state := range a {
+		other, ok := b[file]
+		if !ok || other.exists != state.exists || other.size != state.size || !other.modTime.Equal(state.modTime) {
+			return false
+		}
+	}
+	return true
+}
+
+// clearConsole 清空控制台
+func clearConsole() {
+	fmt.Print("\033[H\033[2J")
+}
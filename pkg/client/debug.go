@@ -0,0 +1,180 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"api_auto_test/pkg/config"
+	"api_auto_test/pkg/logger"
+)
+
+// redactedValue 调试日志中替换敏感值的占位符
+const redactedValue = "[REDACTED]"
+
+// defaultRedactedNames 默认在调试日志中隐藏的请求头和请求体字段（不区分大小写）
+var defaultRedactedNames = []string{
+	"authorization",
+	"proxy-authorization",
+	"cookie",
+	"x-api-key",
+	"password",
+	"token",
+	"secret",
+}
+
+// redactedSubstrings 名称中包含这些片段的请求头、查询参数和请求体字段同样隐藏（不区分大小写），
+// 如 X-Auth-Token、access_token、client_secret
+var redactedSubstrings = []string{"token", "secret", "password", "passwd"}
+
+// redactedSuffixes 名称以这些片段结尾时同样隐藏（不区分大小写），如 api_key、X-Access-Key
+var redactedSuffixes = []string{"key"}
+
+// newRedactedNames 合并默认和配置的敏感字段名，统一转为小写
+func newRedactedNames(names []string) map[string]bool {
+	redact := make(map[string]bool, len(defaultRedactedNames)+len(names))
+	for _, name := range defaultRedactedNames {
+		redact[name] = true
+	}
+	for _, name := range names {
+		redact[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	return redact
+}
+
+// redactedNames 配置中需要隐藏的字段名，auth_flow 携带 token 的请求头同样隐藏
+func redactedNames(cfg *config.TestConfig) []string {
+	if cfg.AuthFlow == nil || cfg.AuthFlow.Header == "" {
+		return cfg.Redact
+	}
+	return append(append([]string(nil), cfg.Redact...), cfg.AuthFlow.Header)
+}
+
+// isRedacted 判断请求头、查询参数或请求体字段是否需要隐藏：
+// 与默认或配置的名称完全相同，或包含 redactedSubstrings、以 redactedSuffixes 结尾
+func (c *HTTPClient) isRedacted(name string) bool {
+	name = strings.ToLower(name)
+	if c.redact[name] {
+		return true
+	}
+	for _, part := range redactedSubstrings {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	for _, suffix := range redactedSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetLogger 设置日志记录器，默认丢弃所有日志
+// Debug 级别会输出实际发送的请求行、请求头和请求体，敏感的请求头和请求体字段会被替换为 [REDACTED]
+func (c *HTTPClient) SetLogger(l logger.Logger) {
+	if l == nil {
+		l = logger.Nop()
+	}
+	c.logger = l
+}
+
+// logRequest 以 Debug 级别输出实际发送的请求，敏感信息已隐藏
+// 未开启 Debug 级别时直接返回，避免每个请求都复制和编码请求体
+func (c *HTTPClient) logRequest(req *http.Request, body interface{}, contentType string) {
+	if !logger.Enabled(c.logger, logger.LevelDebug) {
+		return
+	}
+	c.logger.Debugf("%s %s", req.Method, c.redactURL(req.URL))
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := strings.Join(req.Header[name], ", ")
+		if c.isRedacted(name) {
+			value = redactedValue
+		}
+		c.logger.Debugf("  %s: %s", name, value)
+	}
+
+	if body == nil {
+		return
+	}
+	bodyBytes, err := encodeRequestBody(c.redactValue(body), contentType)
+	if err != nil {
+		c.logger.Debugf("  Body: <%v>", err)
+		return
+	}
+	c.logger.Debugf("  Body: %s", string(bodyBytes))
+}
+
+// RedactRequest 复制请求配置并隐藏敏感的请求头和认证信息，用于在报告中记录实际发送的请求
+// 规则与调试日志一致，请求体和查询参数保持不变，以便后续测试通过 {{name.request...}} 引用
+func (c *HTTPClient) RedactRequest(reqConfig config.RequestConfig) config.RequestConfig {
+	if reqConfig.Headers != nil {
+		headers := make(map[string]string, len(reqConfig.Headers))
+		for name, value := range reqConfig.Headers {
+			if c.isRedacted(name) {
+				value = redactedValue
+			}
+			headers[name] = value
+		}
+		reqConfig.Headers = headers
+	}
+	if reqConfig.Auth != nil {
+		auth := *reqConfig.Auth
+		if auth.Password != "" {
+			auth.Password = redactedValue
+		}
+		if auth.Token != "" {
+			auth.Token = redactedValue
+		}
+		reqConfig.Auth = &auth
+	}
+	return reqConfig
+}
+
+// redactURL 隐藏 URL 中敏感的查询参数
+func (c *HTTPClient) redactURL(u *url.URL) string {
+	query := u.Query()
+	redacted := false
+	for key := range query {
+		if c.isRedacted(key) {
+			query.Set(key, redactedValue)
+			redacted = true
+		}
+	}
+	if !redacted {
+		return u.String()
+	}
+	copied := *u
+	copied.RawQuery = query.Encode()
+	return copied.String()
+}
+
+// redactValue 复制请求体并隐藏其中的敏感字段，支持嵌套对象和数组
+func (c *HTTPClient) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if c.isRedacted(key) {
+				redacted[key] = redactedValue
+				continue
+			}
+			redacted[key] = c.redactValue(item)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = c.redactValue(item)
+		}
+		return redacted
+	}
+	return value
+}
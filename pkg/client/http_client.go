@@ -2,12 +2,17 @@ package client
 
 import (
 	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/cookiejar"
 	"net/url"
 	"os"
 	"reflect"
@@ -15,37 +20,67 @@ import (
 	"time"
 
 	"api_auto_test/pkg/config"
+	"api_auto_test/pkg/logger"
 )
 
 // HTTPClient HTTP客户端
 type HTTPClient struct {
-	client      *http.Client
-	baseURL     string
-	headers     map[string]string
-	timeout     time.Duration
-	certificate *config.CertConfig
+	client          *http.Client
+	baseURL         string
+	headers         map[string]string
+	auth            *config.AuthConfig
+	timeout         time.Duration
+	maxResponseSize int64 // 响应体的最大字节数（解压后）
+	followRedirects bool
+	proxy           string // 代理描述，用于错误信息
+	certificate     *config.CertConfig
+	logger          logger.Logger
+	redact          map[string]bool // 调试日志中需要隐藏值的请求头和请求体字段（小写）
+	limiter         *rateLimiter    // 配置了 rate_limit 时所有请求共享的限流器
 }
 
+// defaultMaxResponseSize 未配置 max_response_size 时响应体的最大字节数
+const defaultMaxResponseSize = 10 << 20
+
+// proxyFromEnvironment 代理配置的特殊值，表示从环境变量读取代理
+const proxyFromEnvironment = "env"
+
 // Response HTTP响应封装
 type Response struct {
+	Method     string
+	URL        string // 实际请求的完整地址（包含基础URL和查询参数，敏感参数已隐藏）
 	StatusCode int
 	Headers    http.Header
 	Body       []byte
-	BodyJSON   map[string]interface{}
+	BodyJSON   map[string]interface{} // JSON 响应体，XML 响应体解析后的结构同样保存在这里
+	WireSize   int64                  // 实际传输的响应体字节数（解压前）
 	Duration   time.Duration
 }
 
 // NewHTTPClient 创建HTTP客户端
 func NewHTTPClient(cfg *config.TestConfig) (*HTTPClient, error) {
 	client := &HTTPClient{
-		baseURL: cfg.BaseURL,
-		headers: cfg.Headers,
-		timeout: cfg.Timeout,
+		baseURL:         cfg.BaseURL,
+		headers:         cfg.Headers,
+		auth:            cfg.Auth,
+		timeout:         cfg.Timeout,
+		maxResponseSize: cfg.MaxResponseSize,
+		// 默认跟随重定向，与标准库行为一致
+		followRedirects: cfg.FollowRedirects == nil || *cfg.FollowRedirects,
+		logger:          logger.Nop(),
+		redact:          newRedactedNames(redactedNames(cfg)),
+		limiter:         newRateLimiter(cfg.RateLimit),
 	}
 
 	if client.timeout == 0 {
 		client.timeout = 30 * time.Second
 	}
+	if client.maxResponseSize <= 0 {
+		client.maxResponseSize = defaultMaxResponseSize
+	}
+	if cfg.RateLimit < 0 {
+		return nil, fmt.Errorf("rate_limit must not be negative, got %v", cfg.RateLimit)
+	}
 
 	// 配置TLS证书
 	tlsConfig, err := client.loadTLSConfig(&cfg.Certificate)
@@ -54,21 +89,79 @@ func NewHTTPClient(cfg *config.TestConfig) (*HTTPClient, error) {
 	}
 
 	// 创建HTTP客户端
+	// 关闭自动解压，由 Do 自行解压以记录实际传输的字节数
 	transport := &http.Transport{
-		TLSClientConfig: tlsConfig,
+		TLSClientConfig:    tlsConfig,
+		DisableCompression: true,
+	}
+
+	// 配置代理
+	if err := client.configureProxy(transport, cfg.Proxy); err != nil {
+		return nil, err
 	}
 
+	// 超时由 Do 通过请求上下文控制，以支持单个测试覆盖
 	client.client = &http.Client{
-		Timeout:   client.timeout,
-		Transport: transport,
+		Transport:     transport,
+		CheckRedirect: checkRedirect,
+	}
+
+	// 启用 Cookie 时，响应设置的 Cookie 按域名和路径自动随后续请求发送
+	if cfg.UseCookies {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cookie jar: %w", err)
+		}
+		client.client.Jar = jar
 	}
 
 	return client, nil
 }
 
+// followRedirectsKey 请求上下文中记录是否跟随重定向的键
+type followRedirectsKey struct{}
+
+// maxRedirects 跟随重定向的最大次数，与标准库默认值一致
+const maxRedirects = 10
+
+// checkRedirect 根据请求上下文中的设置决定是否跟随重定向，不跟随时直接返回重定向响应
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if follow, ok := req.Context().Value(followRedirectsKey{}).(bool); ok && !follow {
+		return http.ErrUseLastResponse
+	}
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	return nil
+}
+
+// configureProxy 配置请求代理，为空时不使用代理
+func (c *HTTPClient) configureProxy(transport *http.Transport, proxy string) error {
+	switch proxy {
+	case "":
+		return nil
+	case proxyFromEnvironment:
+		transport.Proxy = http.ProxyFromEnvironment
+		c.proxy = "from environment"
+		return nil
+	}
+
+	proxyURL, err := url.Parse(proxy)
+	if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+		return fmt.Errorf("invalid proxy URL '%s'", proxy)
+	}
+	transport.Proxy = http.ProxyURL(proxyURL)
+	// 错误信息中隐藏代理密码
+	c.proxy = proxyURL.Redacted()
+	return nil
+}
+
 // loadTLSConfig 加载TLS配置
 func (c *HTTPClient) loadTLSConfig(certConfig *config.CertConfig) (*tls.Config, error) {
-	tlsConfig := &tls.Config{}
+	tlsConfig := &tls.Config{
+		// 跳过服务器证书校验，用于自签名证书的测试环境
+		InsecureSkipVerify: certConfig.InsecureSkipVerify,
+	}
 
 	// 如果没有配置证书，返回默认配置
 	if certConfig.CertFile == "" && certConfig.CAFile == "" {
@@ -103,81 +196,248 @@ func (c *HTTPClient) loadTLSConfig(certConfig *config.CertConfig) (*tls.Config,
 
 // Do 执行HTTP请求
 func (c *HTTPClient) Do(reqConfig config.RequestConfig) (*Response, error) {
-	startTime := time.Now()
-
-	// 验证请求体类型（如果配置了 body_schema）
-	if len(reqConfig.BodySchema) > 0 && reqConfig.Body != nil {
-		if err := validateBodySchema(reqConfig.Body, reqConfig.BodySchema); err != nil {
-			return nil, fmt.Errorf("body schema validation failed: %w", err)
-		}
+	// 限流等待的时间不计入请求耗时
+	if waited := c.limiter.wait(); waited > 0 {
+		c.logger.Debugf("rate limit: waited %s", waited)
 	}
+	startTime := time.Now()
 
-	// 构建完整URL
-	fullURL, err := c.buildURL(reqConfig.Path, reqConfig.Query)
+	req, err := c.newRequest(&reqConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build URL: %w", err)
+		return nil, err
 	}
+	method := req.Method
 
-	// 构建请求体
-	var bodyReader io.Reader
-	if reqConfig.Body != nil {
-		bodyBytes, err := json.Marshal(reqConfig.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
-		}
-		// 调试：打印实际发送的请求体
-		fmt.Printf("[DEBUG] Request Body: %s\n", string(bodyBytes))
-		bodyReader = bytes.NewReader(bodyBytes)
+	// 设置请求超时，单个测试的设置覆盖全局配置
+	timeout := c.timeout
+	if reqConfig.Timeout > 0 {
+		timeout = reqConfig.Timeout
 	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	defer cancel()
 
-	// 创建HTTP请求
-	req, err := http.NewRequest(strings.ToUpper(reqConfig.Method), fullURL, bodyReader)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	// 记录是否跟随重定向，单个测试的设置覆盖全局配置
+	followRedirects := c.followRedirects
+	if reqConfig.FollowRedirects != nil {
+		followRedirects = *reqConfig.FollowRedirects
 	}
+	req = req.WithContext(context.WithValue(ctx, followRedirectsKey{}, followRedirects))
 
-	// 设置Headers
-	c.setHeaders(req, reqConfig.Headers)
+	c.logRequest(req, reqConfig.Body, c.resolveContentType(reqConfig.Headers))
 
 	// 发送请求
 	resp, err := c.client.Do(req)
 	if err != nil {
+		c.logger.Errorf("%s %s failed: %v", method, c.redactURL(req.URL), err)
+		if c.proxy != "" {
+			return nil, fmt.Errorf("failed to send request via proxy %s: %w", c.proxy, err)
+		}
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// 读取响应体
-	respBody, err := io.ReadAll(resp.Body)
+	respBody, err := c.readLimited(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+	wireSize := int64(len(respBody))
 
-	// 解析JSON响应
+	// 解压 gzip 和 deflate 响应，包括手动设置 Accept-Encoding 的请求
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if (encoding == "gzip" || encoding == "deflate") && len(respBody) > 0 {
+		respBody, err = c.decompressBody(encoding, respBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress %s response body: %w", encoding, err)
+		}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+	}
+
+	// 解析JSON响应，XML 响应解析为同样可以通过字段路径访问的结构
 	var bodyJSON map[string]interface{}
 	if len(respBody) > 0 && resp.Header.Get("Content-Type") != "" &&
 		(strings.Contains(resp.Header.Get("Content-Type"), "application/json") ||
 			strings.Contains(resp.Header.Get("Content-Type"), "text/json")) {
-		_ = json.Unmarshal(respBody, &bodyJSON)
+		_ = json.Unmarshal(trimJSONPrefix(respBody), &bodyJSON)
+	} else if len(respBody) > 0 && IsXMLContentType(resp.Header.Get("Content-Type")) {
+		bodyJSON, _ = parseXMLBody(respBody)
 	}
 
 	duration := time.Since(startTime)
 
 	return &Response{
+		Method:     method,
+		URL:        c.redactURL(req.URL),
 		StatusCode: resp.StatusCode,
 		Headers:    resp.Header,
 		Body:       respBody,
 		BodyJSON:   bodyJSON,
+		WireSize:   wireSize,
 		Duration:   duration,
 	}, nil
 }
 
+// supportedMethods 支持的 HTTP 方法
+var supportedMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+}
+
+// newRequest 按请求配置组装 HTTP 请求（URL、请求头、认证和请求体）
+// 会将 reqConfig 的方法规范为大写（未指定时使用默认方法），HEAD 请求会清空 reqConfig 中的请求体，
+// GraphQL 请求会将 reqConfig 的请求体替换为包装后的内容
+func (c *HTTPClient) newRequest(reqConfig *config.RequestConfig) (*http.Request, error) {
+	method := strings.ToUpper(strings.TrimSpace(reqConfig.Method))
+
+	// GraphQL 请求包装为标准的 JSON 请求体，未指定方法时使用 POST
+	if reqConfig.GraphQL != nil {
+		if method == "" {
+			method = http.MethodPost
+		}
+		reqConfig.Body = graphQLPayload(reqConfig.GraphQL)
+	}
+
+	// 未指定方法时使用 GET，不支持的方法直接报错，避免发送意料之外的请求
+	if method == "" {
+		method = http.MethodGet
+	}
+	if !supportedMethods[method] {
+		return nil, fmt.Errorf("unsupported HTTP method '%s'", reqConfig.Method)
+	}
+	reqConfig.Method = method
+
+	// HEAD 请求不携带请求体
+	if method == http.MethodHead {
+		reqConfig.Body = nil
+	}
+
+	// 验证请求体类型（如果配置了 body_schema）
+	if len(reqConfig.BodySchema) > 0 && reqConfig.Body != nil {
+		if err := validateBodySchema(reqConfig.Body, reqConfig.BodySchema); err != nil {
+			return nil, fmt.Errorf("body schema validation failed: %w", err)
+		}
+	}
+
+	// 构建完整URL
+	baseURL := c.baseURL
+	if reqConfig.BaseURL != "" {
+		baseURL = reqConfig.BaseURL
+	}
+	fullURL, err := c.buildURL(baseURL, reqConfig.Path, reqConfig.Query, reqConfig.QueryParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build URL: %w", err)
+	}
+
+	// 构建请求体，按最终生效的 Content-Type 选择编码方式
+	var bodyReader io.Reader
+	if reqConfig.Body != nil {
+		bodyBytes, err := encodeRequestBody(reqConfig.Body, c.resolveContentType(reqConfig.Headers))
+		if err != nil {
+			return nil, err
+		}
+		bodyReader = bytes.NewReader(bodyBytes)
+	}
+
+	// 创建HTTP请求
+	req, err := http.NewRequest(method, fullURL, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// 设置Headers
+	c.setHeaders(req, reqConfig.Headers)
+
+	// 不带请求体的 OPTIONS 请求（如 CORS 预检）不发送全局 headers 中的 Content-Type，测试中显式设置的除外
+	if method == http.MethodOptions && bodyReader == nil && !hasHeader(reqConfig.Headers, "Content-Type") {
+		req.Header.Del("Content-Type")
+	}
+
+	// 设置认证信息
+	if err := c.applyAuth(req, reqConfig.Headers, reqConfig.Auth); err != nil {
+		return nil, err
+	}
+
+	// 未指定 Accept-Encoding 时主动请求 gzip 压缩，与标准库默认行为一致
+	if req.Header.Get("Accept-Encoding") == "" && req.Header.Get("Range") == "" && method != http.MethodHead {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	return req, nil
+}
+
+// decompressBody 按 Content-Encoding 解压响应体
+// deflate 按规范使用 zlib 格式，部分服务端直接发送原始 deflate 数据，zlib 头无效时按原始格式解压
+// 解压后的数据同样受 max_response_size 限制
+func (c *HTTPClient) decompressBody(encoding string, data []byte) ([]byte, error) {
+	var reader io.ReadCloser
+	var err error
+	switch encoding {
+	case "gzip":
+		reader, err = gzip.NewReader(bytes.NewReader(data))
+	case "deflate":
+		reader, err = zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			reader, err = flate.NewReader(bytes.NewReader(data)), nil
+		}
+	default:
+		return data, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return c.readLimited(reader)
+}
+
+// readLimited 读取全部数据，超过 max_response_size 时返回错误，避免异常的服务端耗尽内存
+func (c *HTTPClient) readLimited(reader io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, c.maxResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > c.maxResponseSize {
+		return nil, fmt.Errorf("response exceeded max size of %d bytes", c.maxResponseSize)
+	}
+	return data, nil
+}
+
+// graphQLPayload 将 GraphQL 查询和变量包装为 {"query": ..., "variables": ...}
+func graphQLPayload(gql *config.GraphQLRequest) map[string]interface{} {
+	variables := gql.Variables
+	if variables == nil {
+		variables = map[string]interface{}{}
+	}
+	payload := map[string]interface{}{
+		"query":     gql.Query,
+		"variables": variables,
+	}
+	if gql.OperationName != "" {
+		payload["operationName"] = gql.OperationName
+	}
+	return payload
+}
+
+// trimJSONPrefix 去除响应体开头的 UTF-8 BOM 和空白字符，避免 JSON 解析失败
+func trimJSONPrefix(body []byte) []byte {
+	body = bytes.TrimLeft(body, " \t\r\n")
+	body = bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))
+	return bytes.TrimLeft(body, " \t\r\n")
+}
+
 // buildURL 构建完整URL
-func (c *HTTPClient) buildURL(path string, query map[string]interface{}) (string, error) {
-	baseURL := strings.TrimRight(c.baseURL, "/")
+// query 中的参数按键名排序，params 中的参数按声明顺序追加在最后
+func (c *HTTPClient) buildURL(baseURL, path string, query map[string]interface{}, params []config.QueryParam) (string, error) {
+	baseURL = strings.TrimRight(baseURL, "/")
 	path = strings.TrimLeft(path, "/")
 	fullURL := fmt.Sprintf("%s/%s", baseURL, path)
 
-	if len(query) == 0 {
+	if len(query) == 0 && len(params) == 0 {
 		return fullURL, nil
 	}
 
@@ -189,9 +449,36 @@ func (c *HTTPClient) buildURL(path string, query map[string]interface{}) (string
 
 	q := u.Query()
 	for key, value := range query {
+		// 数组展开为同名的多个查询参数，如 id=1&id=2
+		if items, ok := value.([]interface{}); ok {
+			for _, item := range items {
+				q.Add(key, fmt.Sprintf("%v", item))
+			}
+			continue
+		}
 		q.Add(key, fmt.Sprintf("%v", value))
 	}
-	u.RawQuery = q.Encode()
+	if len(query) > 0 {
+		u.RawQuery = q.Encode()
+	}
+
+	// 有序参数直接拼接，url.Values.Encode 会按键名重新排序
+	if len(params) > 0 {
+		parts := make([]string, 0, len(params)+1)
+		if u.RawQuery != "" {
+			parts = append(parts, u.RawQuery)
+		}
+		for _, param := range params {
+			values := []interface{}{param.Value}
+			if items, ok := param.Value.([]interface{}); ok {
+				values = items
+			}
+			for _, value := range values {
+				parts = append(parts, url.QueryEscape(param.Name)+"="+url.QueryEscape(fmt.Sprintf("%v", value)))
+			}
+		}
+		u.RawQuery = strings.Join(parts, "&")
+	}
 
 	return u.String(), nil
 }
@@ -214,6 +501,104 @@ func (c *HTTPClient) setHeaders(req *http.Request, customHeaders map[string]stri
 	}
 }
 
+// hasHeader 判断请求头配置中是否包含指定名称（不区分大小写）
+func hasHeader(headers map[string]string, name string) bool {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyAuth 根据认证配置生成 Authorization 请求头
+// 优先级：单个测试的 auth > 单个测试 headers 中的 Authorization > 全局 auth > 全局 headers
+func (c *HTTPClient) applyAuth(req *http.Request, customHeaders map[string]string, reqAuth *config.AuthConfig) error {
+	auth := reqAuth
+	if auth == nil {
+		// 单个测试显式设置了 Authorization 时不使用全局认证
+		if hasHeader(customHeaders, "Authorization") {
+			return nil
+		}
+		auth = c.auth
+	}
+	if auth == nil {
+		return nil
+	}
+
+	switch strings.ToLower(auth.Type) {
+	case "basic":
+		req.SetBasicAuth(auth.Username, auth.Password)
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+auth.Token)
+	case "none":
+		req.Header.Del("Authorization")
+	default:
+		return fmt.Errorf("unsupported auth type: %s", auth.Type)
+	}
+	return nil
+}
+
+// resolveContentType 合并全局和自定义 Headers 后得到最终生效的 Content-Type
+func (c *HTTPClient) resolveContentType(customHeaders map[string]string) string {
+	header := http.Header{}
+	for key, value := range c.headers {
+		header.Set(key, value)
+	}
+	for key, value := range customHeaders {
+		header.Set(key, value)
+	}
+	return header.Get("Content-Type")
+}
+
+// encodeRequestBody 按 Content-Type 编码请求体：表单类型使用 URL 编码，XML 类型编码为 XML，其它类型使用 JSON 编码
+func encodeRequestBody(body interface{}, contentType string) ([]byte, error) {
+	if IsXMLContentType(contentType) {
+		return encodeXMLBody(body)
+	}
+
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	if mediaType != "application/x-www-form-urlencoded" {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		return bodyBytes, nil
+	}
+
+	// 字符串请求体视为已编码的表单内容
+	if str, ok := body.(string); ok {
+		return []byte(str), nil
+	}
+
+	fields, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("form request body must be a map, got %T", body)
+	}
+
+	form := url.Values{}
+	for key, value := range fields {
+		switch v := value.(type) {
+		case nil:
+			form.Set(key, "")
+		case []interface{}:
+			// 数组展开为同名的多个字段
+			for _, item := range v {
+				form.Add(key, fmt.Sprintf("%v", item))
+			}
+		case map[string]interface{}:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode form field '%s': %w", key, err)
+			}
+			form.Set(key, string(encoded))
+		default:
+			form.Set(key, fmt.Sprintf("%v", v))
+		}
+	}
+	return []byte(form.Encode()), nil
+}
+
 // validateBodySchema 验��请求体字段类型
 func validateBodySchema(body interface{}, schema map[string]string) error {
 	// 将 body 转换为 map[string]interface{}
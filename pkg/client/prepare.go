@@ -0,0 +1,46 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+
+	"api_auto_test/pkg/config"
+)
+
+// PreparedRequest 组装完成但未发送的请求，敏感的请求头、查询参数和请求体字段已替换为 [REDACTED]
+type PreparedRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// Prepare 按与 Do 相同的方式组装请求但不发送，用于 dry-run 检查变量替换后实际会发送的内容
+func (c *HTTPClient) Prepare(reqConfig config.RequestConfig) (*PreparedRequest, error) {
+	req, err := c.newRequest(&reqConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	prepared := &PreparedRequest{
+		Method:  req.Method,
+		URL:     c.redactURL(req.URL),
+		Headers: make(map[string]string, len(req.Header)),
+	}
+	for name, values := range req.Header {
+		value := strings.Join(values, ", ")
+		if c.isRedacted(name) {
+			value = redactedValue
+		}
+		prepared.Headers[http.CanonicalHeaderKey(name)] = value
+	}
+
+	if reqConfig.Body != nil {
+		bodyBytes, err := encodeRequestBody(c.redactValue(reqConfig.Body), c.resolveContentType(reqConfig.Headers))
+		if err != nil {
+			return nil, err
+		}
+		prepared.Body = string(bodyBytes)
+	}
+	return prepared, nil
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter 令牌桶限流器（容量为 1），所有并发请求共享，保证请求之间至少间隔 interval
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time // 下一个请求最早的发送时间
+}
+
+// newRateLimiter 按每秒请求数创建限流器，不大于 0 时返回 nil（不限流）
+func newRateLimiter(perSecond float64) *rateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// wait 阻塞到允许发送下一个请求，返回等待的时长
+func (l *rateLimiter) wait() time.Duration {
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+	return delay
+}
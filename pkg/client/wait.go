@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// waitInterval 等待服务就绪时两次检查之间的间隔
+const waitInterval = 500 * time.Millisecond
+
+// WaitForReady 轮询 url 直到返回 status 状态码，用于在刚启动的服务上执行测试之前等待其就绪
+// 使用与测试请求相同的 TLS 与代理配置，超过 timeout 仍未就绪时返回错误，并附带最后一次检查失败的原因
+func (c *HTTPClient) WaitForReady(url string, status int, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// GET 请求没有请求体，可以重复发送
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("invalid URL '%s': %w", url, err)
+	}
+
+	for {
+		err := c.checkReady(req, status)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s not ready after %s: %w", url, timeout, err)
+		case <-time.After(waitInterval):
+		}
+	}
+}
+
+// checkReady 发送一次请求，状态码与 status 一致时返回 nil
+func (c *HTTPClient) checkReady(req *http.Request, status int) error {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != status {
+		return fmt.Errorf("unexpected status code %d, want %d", resp.StatusCode, status)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package client
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// XML 请求体和响应体与 map 之间的转换约定：
+//   - 元素名为键，只有文本的元素为字符串值，包含属性或子元素的元素为 map
+//   - 同名的兄弟元素合并为数组
+//   - 属性使用 "@" 前缀的键，如 "@id"；同时包含文本和属性或子元素时，文本使用 "#text" 键
+//   - 元素名和属性名忽略命名空间前缀，如 soap:Envelope 对应 Envelope
+
+// xmlAttrPrefix 表示 XML 属性的键前缀
+const xmlAttrPrefix = "@"
+
+// xmlTextKey 同时包含文本和属性或子元素时文本内容的键
+const xmlTextKey = "#text"
+
+// IsXMLContentType 判断 Content-Type 是否为 XML（application/xml、text/xml 或 +xml 后缀）
+func IsXMLContentType(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	return mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml")
+}
+
+// encodeXMLBody 将请求体编码为 XML：字符串视为已编码的 XML（如模板），map 必须只有一个根元素
+func encodeXMLBody(body interface{}) ([]byte, error) {
+	if str, ok := body.(string); ok {
+		return []byte(str), nil
+	}
+
+	root, ok := body.(map[string]interface{})
+	if !ok || len(root) != 1 {
+		return nil, fmt.Errorf("xml request body must be a string or a map with a single root element, got %T", body)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	for name, value := range root {
+		if err := encodeXMLElement(encoder, name, value); err != nil {
+			return nil, fmt.Errorf("failed to marshal xml request body: %w", err)
+		}
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to marshal xml request body: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeXMLElement 编码单个元素，数组编码为多个同名元素，map 的键按名称排序以保证输出稳定
+func encodeXMLElement(encoder *xml.Encoder, name string, value interface{}) error {
+	if items, ok := value.([]interface{}); ok {
+		for _, item := range items {
+			if err := encodeXMLElement(encoder, name, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	fields, isMap := value.(map[string]interface{})
+	children := make([]string, 0, len(fields))
+	if isMap {
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if strings.HasPrefix(key, xmlAttrPrefix) {
+				start.Attr = append(start.Attr, xml.Attr{
+					Name:  xml.Name{Local: strings.TrimPrefix(key, xmlAttrPrefix)},
+					Value: xmlText(fields[key]),
+				})
+			} else if key != xmlTextKey {
+				children = append(children, key)
+			}
+		}
+	}
+
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	switch {
+	case !isMap:
+		if err := encoder.EncodeToken(xml.CharData(xmlText(value))); err != nil {
+			return err
+		}
+	case fields[xmlTextKey] != nil:
+		if err := encoder.EncodeToken(xml.CharData(xmlText(fields[xmlTextKey]))); err != nil {
+			return err
+		}
+	}
+	for _, child := range children {
+		if err := encodeXMLElement(encoder, child, fields[child]); err != nil {
+			return err
+		}
+	}
+	return encoder.EncodeToken(start.End())
+}
+
+// xmlText 将标量值转换为 XML 文本，nil 为空字符串
+func xmlText(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// parseXMLBody 将 XML 响应体解析为以根元素名为键的 map，便于通过字段路径断言元素文本，
+// 例如 Envelope.Body.GetUserResponse.Name
+func parseXMLBody(data []byte) (map[string]interface{}, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				return nil, fmt.Errorf("xml body has no root element")
+			}
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			value, err := decodeXMLElement(decoder, start)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{start.Name.Local: value}, nil
+		}
+	}
+}
+
+// decodeXMLElement 解析 start 开始的元素，只有文本时返回去除首尾空白的字符串，否则返回 map
+func decodeXMLElement(decoder *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	fields := make(map[string]interface{})
+	for _, attr := range start.Attr {
+		// 忽略命名空间声明
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		fields[xmlAttrPrefix+attr.Name.Local] = attr.Value
+	}
+
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			child, err := decodeXMLElement(decoder, t)
+			if err != nil {
+				return nil, err
+			}
+			name := t.Name.Local
+			switch existing := fields[name].(type) {
+			case nil:
+				fields[name] = child
+			case []interface{}:
+				fields[name] = append(existing, child)
+			default:
+				fields[name] = []interface{}{existing, child}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			content := strings.TrimSpace(text.String())
+			if len(fields) == 0 {
+				return content, nil
+			}
+			if content != "" {
+				fields[xmlTextKey] = content
+			}
+			return fields, nil
+		}
+	}
+}
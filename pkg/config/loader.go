@@ -1,8 +1,13 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -10,6 +15,7 @@ import (
 // Loader 配置加载器
 type Loader struct {
 	configPath string
+	files      []string // 最近一次加载读取的文件，包括 include 引用的配置片段
 }
 
 // NewLoader 创建配置加载器
@@ -19,19 +25,459 @@ func NewLoader(configPath string) *Loader {
 	}
 }
 
-// Load 加载配置文件
+// includeKey 引用共享配置片段的顶层字段
+const includeKey = "include"
+
+// appendedKeys 合并 include 时追加而不是覆盖的接口列表字段
+var appendedKeys = map[string]bool{"setup": true, "apis": true, "teardown": true}
+
+// relativePathFields 接口定义里相对于所在配置文件目录的文件路径字段，按所在的子对象分组，空字符串表示接口定义本身
+var relativePathFields = map[string][]string{
+	"":         {"hosts_file"},
+	"request":  {"body_file"},
+	"response": {"body_golden", "json_schema"},
+}
+
+// inlineJSONFields 既可以写文件路径也可以直接写 JSON 内容的字段，以 { 开头时视为内联内容，不做路径转换
+var inlineJSONFields = map[string]bool{"json_schema": true}
+
+// jsonDurationFields JSON 配置中接口定义里的时长字段，按所在的子对象分组
+var jsonDurationFields = map[string][]string{
+	"request":      {"timeout"},
+	"response":     {"warn_response_time"},
+	"retry_policy": {"interval", "max_interval"},
+}
+
+// Load 加载配置文件，.json 文件使用 encoding/json 解析，其余按 YAML 解析
 func (l *Loader) Load() (*TestConfig, error) {
-	data, err := os.ReadFile(l.configPath)
+	l.files = nil
+
+	var config TestConfig
+	if isJSONFile(l.configPath) {
+		document, err := l.loadDocument(l.configPath, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := decodeJSONConfig(document, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	} else {
+		// 直接解析 YAML 节点，错误信息中的行号与配置文件一致
+		node, err := l.loadYAMLDocument(l.configPath, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := node.Decode(&config); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	}
+
+	if err := l.validateSuites(&config); err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// Files 返回最近一次 Load 读取的文件：配置文件本身及 include 引用的配置片段（包括读取失败的文件）
+// 用于 -watch 监听配置的所有来源
+func (l *Loader) Files() []string {
+	return append([]string{}, l.files...)
+}
+
+// loadDocument 读取配置文件，并按顺序合并 include 引用的配置片段
+// include 中的相对路径相对于当前文件所在目录；后引用的文件覆盖先引用的文件，当前文件覆盖所有引用的文件
+// stack 记录正在加载的文件链，用于检测循环引用
+func (l *Loader) loadDocument(path string, stack []string) (map[string]interface{}, error) {
+	stack, err := pushInclude(path, stack)
+	if err != nil {
+		return nil, err
+	}
+	l.files = append(l.files, path)
+
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	var config TestConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	var document map[string]interface{}
+	if isJSONFile(path) {
+		err = json.Unmarshal(data, &document)
+	} else {
+		err = yaml.Unmarshal(data, &document)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
+	if document == nil {
+		document = make(map[string]interface{})
+	}
 
-	return &config, nil
+	resolveDocumentPaths(document, filepath.Dir(path))
+
+	includes, err := parseIncludes(document[includeKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid include in '%s': %w", path, err)
+	}
+	delete(document, includeKey)
+	if len(includes) == 0 {
+		return document, nil
+	}
+
+	merged := make(map[string]interface{})
+	for _, include := range includes {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+		fragment, err := l.loadDocument(include, stack)
+		if err != nil {
+			return nil, fmt.Errorf("failed to include '%s': %w", include, err)
+		}
+		mergeDocument(merged, fragment)
+	}
+	mergeDocument(merged, document)
+
+	return merged, nil
+}
+
+// loadYAMLDocument 与 loadDocument 相同，但以 YAML 节点的形式读取和合并配置，保留每个值在原文件中的位置
+// 没有 include 时直接返回文件的根节点；引用的 JSON 片段同样按 YAML 解析
+func (l *Loader) loadYAMLDocument(path string, stack []string) (*yaml.Node, error) {
+	stack, err := pushInclude(path, stack)
+	if err != nil {
+		return nil, err
+	}
+	l.files = append(l.files, path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	document := &root
+	if document.Kind == yaml.DocumentNode {
+		document = document.Content[0]
+	}
+	if document.Kind == 0 {
+		document = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+	if document.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("failed to parse config file: line %d: expected a mapping", document.Line)
+	}
+
+	resolveYAMLPaths(document, filepath.Dir(path))
+
+	var includes []string
+	for i := 0; i < len(document.Content); i += 2 {
+		if document.Content[i].Value != includeKey {
+			continue
+		}
+		var value interface{}
+		if err := document.Content[i+1].Decode(&value); err != nil {
+			return nil, fmt.Errorf("invalid include in '%s': %w", path, err)
+		}
+		if includes, err = parseIncludes(value); err != nil {
+			return nil, fmt.Errorf("invalid include in '%s': %w", path, err)
+		}
+		document.Content = append(document.Content[:i:i], document.Content[i+2:]...)
+		break
+	}
+	if len(includes) == 0 {
+		return document, nil
+	}
+
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, include := range includes {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+		fragment, err := l.loadYAMLDocument(include, stack)
+		if err != nil {
+			return nil, fmt.Errorf("failed to include '%s': %w", include, err)
+		}
+		mergeYAMLNode(merged, fragment)
+	}
+	mergeYAMLNode(merged, document)
+
+	return merged, nil
+}
+
+// pushInclude 将 path 加入正在加载的文件链，path 已在链中时返回循环引用错误
+func pushInclude(path string, stack []string) ([]string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	for i, loading := range stack {
+		if loading == absPath {
+			chain := append(append([]string{}, stack[i:]...), absPath)
+			return nil, fmt.Errorf("circular include: %s", strings.Join(chain, " -> "))
+		}
+	}
+	return append(stack, absPath), nil
+}
+
+// resolveDocumentPaths 将配置文档中接口的相对文件路径（relativePathFields）转换为相对于 dir 的路径
+// 在合并 include 之前处理，因此配置片段中的路径相对于片段所在的目录
+func resolveDocumentPaths(document map[string]interface{}, dir string) {
+	for key := range appendedKeys {
+		items, _ := document[key].([]interface{})
+		for _, item := range items {
+			api, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for section, fields := range relativePathFields {
+				values := api
+				if section != "" {
+					values, ok = api[section].(map[string]interface{})
+					if !ok {
+						continue
+					}
+				}
+				for _, field := range fields {
+					if path, ok := values[field].(string); ok {
+						values[field] = resolveFieldPath(dir, field, path)
+					}
+				}
+			}
+		}
+	}
+}
+
+// resolveYAMLPaths 与 resolveDocumentPaths 相同，作用于 YAML 节点
+func resolveYAMLPaths(document *yaml.Node, dir string) {
+	for key := range appendedKeys {
+		items := mappingValue(document, key)
+		if items == nil || items.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, api := range items.Content {
+			if api.Kind != yaml.MappingNode {
+				continue
+			}
+			for section, fields := range relativePathFields {
+				values := api
+				if section != "" {
+					values = mappingValue(api, section)
+					if values == nil || values.Kind != yaml.MappingNode {
+						continue
+					}
+				}
+				for _, field := range fields {
+					if path := mappingValue(values, field); path != nil && path.Kind == yaml.ScalarNode {
+						path.Value = resolveFieldPath(dir, field, path.Value)
+					}
+				}
+			}
+		}
+	}
+}
+
+// resolveFieldPath 转换 field 字段中的相对路径，内联 JSON 内容（inlineJSONFields）保持不变
+func resolveFieldPath(dir, field, path string) string {
+	if inlineJSONFields[field] && strings.HasPrefix(strings.TrimSpace(path), "{") {
+		return path
+	}
+	return resolveRelativePath(dir, path)
+}
+
+// resolveRelativePath 将相对路径转换为相对于 dir 的路径，空路径和绝对路径保持不变
+func resolveRelativePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// isJSONFile 判断配置文件是否为 JSON 格式
+func isJSONFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+// decodeJSONConfig 使用 encoding/json 将合并后的配置文档解析为 TestConfig
+// 时长字段可以写成 "30s" 这样的字符串，也可以写成纳秒数
+func decodeJSONConfig(document map[string]interface{}, config *TestConfig) error {
+	if err := normalizeJSONDurations(document); err != nil {
+		return err
+	}
+	data, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, config)
+}
+
+// normalizeJSONDurations 将配置中字符串形式的时长转换为纳秒数，以便 encoding/json 解析为 time.Duration
+func normalizeJSONDurations(document map[string]interface{}) error {
+	for _, field := range []string{"timeout", "think_time"} {
+		if err := normalizeDuration(document, field); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+	}
+	for key := range appendedKeys {
+		items, _ := document[key].([]interface{})
+		for _, item := range items {
+			api, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if err := normalizeDuration(api, "delay"); err != nil {
+				return fmt.Errorf("delay: %w", err)
+			}
+			for section, fields := range jsonDurationFields {
+				values, ok := api[section].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				for _, field := range fields {
+					if err := normalizeDuration(values, field); err != nil {
+						return fmt.Errorf("%s.%s: %w", section, field, err)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeDuration 将对象中指定字段的时长字符串转换为纳秒数
+func normalizeDuration(values map[string]interface{}, field string) error {
+	text, ok := values[field].(string)
+	if !ok {
+		return nil
+	}
+	duration, err := time.ParseDuration(text)
+	if err != nil {
+		return fmt.Errorf("invalid duration '%s'", text)
+	}
+	values[field] = int64(duration)
+	return nil
+}
+
+// parseIncludes 解析 include 字段，必须是文件路径列表
+func parseIncludes(value interface{}) ([]string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a list of file paths")
+	}
+	paths := make([]string, 0, len(items))
+	for _, item := range items {
+		path, ok := item.(string)
+		if !ok || path == "" {
+			return nil, fmt.Errorf("expected a list of file paths, got '%v'", item)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// mergeDocument 将 src 合并到 dst：接口列表追加，映射按键浅合并，其余值直接覆盖
+func mergeDocument(dst, src map[string]interface{}) {
+	for key, value := range src {
+		if appendedKeys[key] {
+			existing, _ := dst[key].([]interface{})
+			if items, ok := value.([]interface{}); ok {
+				dst[key] = append(existing, items...)
+				continue
+			}
+		}
+
+		existing, dstIsMap := dst[key].(map[string]interface{})
+		values, srcIsMap := value.(map[string]interface{})
+		if dstIsMap && srcIsMap {
+			combined := make(map[string]interface{}, len(existing)+len(values))
+			for k, v := range existing {
+				combined[k] = v
+			}
+			for k, v := range values {
+				combined[k] = v
+			}
+			dst[key] = combined
+			continue
+		}
+
+		dst[key] = value
+	}
+}
+
+// mergeYAMLNode 按 mergeDocument 的规则将映射节点 src 合并到 dst
+func mergeYAMLNode(dst, src *yaml.Node) {
+	for i := 0; i < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		existing := mappingValue(dst, key.Value)
+		if existing == nil {
+			if value.Kind == yaml.MappingNode || value.Kind == yaml.SequenceNode {
+				// 复制一层，避免后续合并修改 src 中的节点
+				copied := *value
+				copied.Content = append([]*yaml.Node{}, value.Content...)
+				value = &copied
+			}
+			dst.Content = append(dst.Content, key, value)
+			continue
+		}
+
+		switch {
+		case appendedKeys[key.Value] && value.Kind == yaml.SequenceNode && existing.Kind == yaml.SequenceNode:
+			existing.Content = append(existing.Content, value.Content...)
+		case value.Kind == yaml.MappingNode && existing.Kind == yaml.MappingNode:
+			for j := 0; j < len(value.Content); j += 2 {
+				setMappingValue(existing, value.Content[j], value.Content[j+1])
+			}
+		default:
+			setMappingValue(dst, key, value)
+		}
+	}
+}
+
+// mappingValue 返回映射节点中 key 对应的值节点，不存在时返回 nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue 设置映射节点中 key 对应的值，不存在时追加
+func setMappingValue(node, key, value *yaml.Node) {
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == key.Value {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, key, value)
+}
+
+// validateSuites 检查测试套件中引用的测试是否都存在
+func (l *Loader) validateSuites(config *TestConfig) error {
+	names := make(map[string]bool, len(config.APIs))
+	for _, api := range config.APIs {
+		names[api.Name] = true
+	}
+
+	for suite, tests := range config.Suites {
+		for _, name := range tests {
+			if !names[name] {
+				return fmt.Errorf("suite '%s' references unknown test '%s'", suite, name)
+			}
+		}
+	}
+
+	return nil
 }
 
 // LoadWithVersion 加载配置并过滤指定版本的API测试
@@ -80,6 +526,198 @@ func (l *Loader) isVersionMatch(api APITest, targetVersion string) bool {
 	return false
 }
 
+// SelectSuite 只保留指定套件中的测试及其依赖的测试
+// 测试按套件中声明的顺序排列，依赖的测试排在引用它的测试之前
+func SelectSuite(config *TestConfig, suite string) error {
+	tests, exists := config.Suites[suite]
+	if !exists {
+		return fmt.Errorf("unknown suite '%s'", suite)
+	}
+
+	apisByName := make(map[string]APITest, len(config.APIs))
+	for _, api := range config.APIs {
+		apisByName[api.Name] = api
+	}
+
+	selected := make([]APITest, 0, len(tests))
+	added := make(map[string]bool)
+
+	var add func(name string)
+	add = func(name string) {
+		api, exists := apisByName[name]
+		if !exists || added[name] {
+			return
+		}
+		added[name] = true
+		if api.DependsOn != "" {
+			add(api.DependsOn)
+		}
+		selected = append(selected, api)
+	}
+
+	for _, name := range tests {
+		add(name)
+	}
+	config.APIs = selected
+
+	return nil
+}
+
+// ApplyEnvironment 将指定环境的配置覆盖到基础配置上，环境名称为空时不做任何修改
+func ApplyEnvironment(config *TestConfig, name string) error {
+	if name == "" {
+		return nil
+	}
+	env, exists := config.Environments[name]
+	if !exists {
+		names := make([]string, 0, len(config.Environments))
+		for envName := range config.Environments {
+			names = append(names, envName)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown environment '%s' (available: %s)", name, strings.Join(names, ", "))
+	}
+
+	if env.BaseURL != "" {
+		config.BaseURL = env.BaseURL
+	}
+	if len(env.Headers) > 0 {
+		headers := make(map[string]string, len(config.Headers)+len(env.Headers))
+		for k, v := range config.Headers {
+			headers[k] = v
+		}
+		for k, v := range env.Headers {
+			headers[k] = v
+		}
+		config.Headers = headers
+	}
+	if env.Certificate.CertFile != "" {
+		config.Certificate.CertFile = env.Certificate.CertFile
+	}
+	if env.Certificate.KeyFile != "" {
+		config.Certificate.KeyFile = env.Certificate.KeyFile
+	}
+	if env.Certificate.CAFile != "" {
+		config.Certificate.CAFile = env.Certificate.CAFile
+	}
+	if env.Certificate.InsecureSkipVerify {
+		config.Certificate.InsecureSkipVerify = true
+	}
+	if len(env.Variables) > 0 {
+		variables := make(map[string]interface{}, len(config.Variables)+len(env.Variables))
+		for k, v := range config.Variables {
+			variables[k] = v
+		}
+		for k, v := range env.Variables {
+			variables[k] = v
+		}
+		config.Variables = variables
+	}
+
+	return nil
+}
+
+// SelectTags 按标签表达式筛选测试，表达式为空时保留所有测试
+// 表达式为逗号分隔的标签列表，如 "smoke,api"，带 ! 前缀的标签表示排除，如 "smoke,!slow"
+// 测试包含任一指定标签（未指定时视为全部匹配）且不包含任何排除标签时被选中；
+// 被选中测试依赖的测试也会保留，测试保持配置文件中的顺序；依赖的测试带有排除标签时返回错误
+func SelectTags(config *TestConfig, expr string) error {
+	include, exclude, err := parseTagExpr(expr)
+	if err != nil {
+		return err
+	}
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil
+	}
+
+	apisByName := make(map[string]APITest, len(config.APIs))
+	for _, api := range config.APIs {
+		apisByName[api.Name] = api
+	}
+
+	keep := make(map[string]bool)
+	var mark func(name, requiredBy string) error
+	mark = func(name, requiredBy string) error {
+		api, exists := apisByName[name]
+		if !exists || keep[name] {
+			return nil
+		}
+		// 显式排除的测试不会因为被依赖而重新加入
+		if tag := excludedTag(api.Tags, exclude); tag != "" && requiredBy != "" {
+			return fmt.Errorf("test '%s' excluded by '!%s' is required by '%s'", name, tag, requiredBy)
+		}
+		keep[name] = true
+		if api.DependsOn != "" {
+			return mark(api.DependsOn, name)
+		}
+		return nil
+	}
+
+	for _, api := range config.APIs {
+		if matchesTags(api.Tags, include, exclude) {
+			if err := mark(api.Name, ""); err != nil {
+				return err
+			}
+		}
+	}
+
+	selected := make([]APITest, 0, len(keep))
+	for _, api := range config.APIs {
+		if keep[api.Name] {
+			selected = append(selected, api)
+		}
+	}
+	config.APIs = selected
+
+	return nil
+}
+
+// parseTagExpr 解析标签表达式，返回需要包含和排除的标签
+func parseTagExpr(expr string) (map[string]bool, map[string]bool, error) {
+	include := make(map[string]bool)
+	exclude := make(map[string]bool)
+	for _, part := range strings.Split(expr, ",") {
+		tag := strings.TrimSpace(part)
+		if tag == "" {
+			continue
+		}
+		if strings.HasPrefix(tag, "!") {
+			name := strings.TrimSpace(tag[1:])
+			if name == "" {
+				return nil, nil, fmt.Errorf("invalid tag expression '%s': missing tag after '!'", expr)
+			}
+			exclude[name] = true
+			continue
+		}
+		include[tag] = true
+	}
+	return include, exclude, nil
+}
+
+// excludedTag 返回测试标签中第一个被排除的标签，没有时返回空字符串
+func excludedTag(tags []string, exclude map[string]bool) string {
+	for _, tag := range tags {
+		if exclude[tag] {
+			return tag
+		}
+	}
+	return ""
+}
+
+// matchesTags 判断测试的标签是否满足筛选条件
+func matchesTags(tags []string, include, exclude map[string]bool) bool {
+	matched := len(include) == 0
+	for _, tag := range tags {
+		if exclude[tag] {
+			return false
+		}
+		if include[tag] {
+			matched = true
+		}
+	}
+	return matched
+}
+
 // MergeConfig 合并运行时配置（支持命令行参数覆盖）
 func MergeConfig(base *TestConfig, baseURL, certFile, keyFile, caFile, version string) *TestConfig {
 	if baseURL != "" {
@@ -95,6 +95,91 @@ invalid: yaml: content:
 		})
 	})
 
+	Describe("Load JSON", func() {
+		BeforeEach(func() {
+			configFile = filepath.Join(tmpDir, "test-config.json")
+		})
+
+		Context("当 JSON 配置文件有效时", func() {
+			BeforeEach(func() {
+				configContent := `{
+  "base_url": "https://api.example.com",
+  "timeout": "30s",
+  "think_time": "200ms",
+  "headers": {"Content-Type": "application/json"},
+  "apis": [
+    {
+      "name": "test-api",
+      "delay": "1s",
+      "request": {"method": "POST", "path": "/test", "timeout": "5s", "body": {"id": 1}},
+      "response": {"status_code": 201},
+      "retry_policy": {"max_retries": 2, "interval": 1000000000}
+    }
+  ]
+}`
+				Expect(os.WriteFile(configFile, []byte(configContent), 0644)).To(Succeed())
+				loader = config.NewLoader(configFile)
+			})
+
+			It("应该解析为与 YAML 相同的配置结构", func() {
+				cfg, err := loader.Load()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.BaseURL).To(Equal("https://api.example.com"))
+				Expect(cfg.Timeout.String()).To(Equal("30s"))
+				Expect(cfg.ThinkTime.String()).To(Equal("200ms"))
+				Expect(cfg.APIs[0].Delay.String()).To(Equal("1s"))
+				Expect(cfg.Headers).To(HaveKeyWithValue("Content-Type", "application/json"))
+				Expect(cfg.APIs).To(HaveLen(1))
+				Expect(cfg.APIs[0].Request.Method).To(Equal("POST"))
+				Expect(cfg.APIs[0].Request.Timeout.String()).To(Equal("5s"))
+				Expect(cfg.APIs[0].Request.Body).To(Equal(map[string]interface{}{"id": 1.0}))
+				Expect(cfg.APIs[0].Response.StatusCode).To(Equal(201))
+				Expect(cfg.APIs[0].RetryPolicy.Interval.String()).To(Equal("1s"))
+			})
+		})
+
+		Context("当 JSON 格式无效时", func() {
+			BeforeEach(func() {
+				Expect(os.WriteFile(configFile, []byte(`{"base_url": `), 0644)).To(Succeed())
+				loader = config.NewLoader(configFile)
+			})
+
+			It("应该返回错误", func() {
+				_, err := loader.Load()
+				Expect(err).To(MatchError(ContainSubstring("failed to parse config file")))
+			})
+		})
+
+		Context("当时长格式无效时", func() {
+			BeforeEach(func() {
+				configContent := `{"apis": [{"name": "a", "request": {"timeout": "soon"}}]}`
+				Expect(os.WriteFile(configFile, []byte(configContent), 0644)).To(Succeed())
+				loader = config.NewLoader(configFile)
+			})
+
+			It("应该返回错误", func() {
+				_, err := loader.Load()
+				Expect(err).To(MatchError(ContainSubstring("invalid duration 'soon'")))
+			})
+		})
+
+		Context("当测试引用了相对路径的文件时", func() {
+			BeforeEach(func() {
+				configContent := `{"apis": [{"name": "a", "hosts_file": "hosts.txt", "request": {"method": "POST", "path": "/a", "body_file": "bodies/a.json"}, "response": {"status_code": 200, "json_schema": "schemas/a.json"}}]}`
+				Expect(os.WriteFile(configFile, []byte(configContent), 0644)).To(Succeed())
+				loader = config.NewLoader(configFile)
+			})
+
+			It("应该相对于配置文件的目录解析", func() {
+				cfg, err := loader.Load()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.APIs[0].Request.BodyFile).To(Equal(filepath.Join(tmpDir, "bodies", "a.json")))
+				Expect(cfg.APIs[0].HostsFile).To(Equal(filepath.Join(tmpDir, "hosts.txt")))
+				Expect(cfg.APIs[0].Response.JSONSchema).To(Equal(filepath.Join(tmpDir, "schemas", "a.json")))
+			})
+		})
+	})
+
 	Describe("LoadWithVersion", func() {
 		BeforeEach(func() {
 			configContent := `
@@ -159,6 +244,470 @@ apis:
 		})
 	})
 
+	Describe("Validate", func() {
+		It("有效的配置应该通过校验", func() {
+			cfg := &config.TestConfig{
+				Setup: []config.APITest{{Name: "login"}},
+				APIs: []config.APITest{
+					{Name: "create", DependsOn: "login", Request: config.RequestConfig{BodySchema: map[string]string{"age": "int"}}},
+					{Name: "get", DependsOn: "create", Response: config.ResponseExpectation{
+						Validators: []config.Validator{
+							{Type: "Equals", Field: "id"},
+							{Type: "count_where", Where: &config.Validator{Type: "eq"}},
+							{Type: "each", Value: []interface{}{map[string]interface{}{"type": "not_empty"}}},
+						},
+						HeaderValidators: []config.Validator{{Field: "Content-Type", Type: "contains"}},
+					}},
+				},
+			}
+			Expect(cfg.Validate()).To(Succeed())
+		})
+
+		It("应该列出发现的所有问题", func() {
+			cfg := &config.TestConfig{
+				APIs: []config.APITest{
+					{Name: "create"},
+					{Name: "create"},
+					{Name: "update", DependsOn: "createUesr"},
+					{Name: "a", DependsOn: "b"},
+					{Name: "b", DependsOn: "a"},
+					{Name: "check", Request: config.RequestConfig{BodySchema: map[string]string{"age": "integer"}}, Response: config.ResponseExpectation{
+						Validators: []config.Validator{
+							{Type: "equal_to"},
+							{Type: "count_where", Where: &config.Validator{Type: "matches"}},
+							{Type: "each", Value: []interface{}{map[string]interface{}{"type": "bogus"}}},
+						},
+						HeaderValidators: []config.Validator{{Field: "X-Id", Type: "gt"}},
+					}},
+				},
+			}
+			err := cfg.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(`invalid config:
+  - duplicate test name 'create'
+  - test 'update' depends on unknown test 'createUesr'
+  - circular dependency: a -> b -> a
+  - test 'check': unknown validator type 'equal_to'
+  - test 'check': unknown validator type 'matches'
+  - test 'check': unknown validator type 'bogus'
+  - test 'check': unsupported header validator type 'gt'
+  - test 'check': unsupported body_schema type 'integer' for field 'age'`))
+		})
+
+		It("auth_flow 应该引用存在的登录测试并指定 token 路径", func() {
+			cfg := &config.TestConfig{
+				Setup:    []config.APITest{{Name: "login"}},
+				AuthFlow: &config.AuthFlow{Login: "login", TokenPath: "data.token"},
+			}
+			Expect(cfg.Validate()).To(Succeed())
+
+			cfg.AuthFlow = &config.AuthFlow{Login: "signin"}
+			Expect(cfg.Validate()).To(MatchError(`invalid config:
+  - auth_flow.login refers to unknown test 'signin'
+  - auth_flow.token_path is required`))
+		})
+
+		It("加载配置时应该执行校验", func() {
+			configContent := `
+base_url: https://api.example.com
+apis:
+  - name: update
+    depends_on: createUesr
+    request:
+      method: PUT
+      path: /users/1
+`
+			Expect(os.WriteFile(configFile, []byte(configContent), 0644)).To(Succeed())
+
+			_, err := config.NewLoader(configFile).Load()
+			Expect(err).To(MatchError(ContainSubstring("test 'update' depends on unknown test 'createUesr'")))
+		})
+	})
+
+	Describe("Suites", func() {
+		Context("当套件引用了不存在的测试时", func() {
+			BeforeEach(func() {
+				configContent := `
+base_url: https://api.example.com
+suites:
+  smoke: [login, missing]
+apis:
+  - name: login
+    request:
+      method: POST
+      path: /login
+`
+				err := os.WriteFile(configFile, []byte(configContent), 0644)
+				Expect(err).NotTo(HaveOccurred())
+
+				loader = config.NewLoader(configFile)
+			})
+
+			It("应该在加载时返回错误", func() {
+				_, err := loader.Load()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("unknown test 'missing'"))
+			})
+		})
+
+		Context("当选择有效的套件时", func() {
+			var cfg *config.TestConfig
+
+			BeforeEach(func() {
+				configContent := `
+base_url: https://api.example.com
+suites:
+  smoke: [getProfile, health]
+apis:
+  - name: health
+    request:
+      method: GET
+      path: /health
+  - name: login
+    request:
+      method: POST
+      path: /login
+  - name: getProfile
+    depends_on: login
+    request:
+      method: GET
+      path: /profile
+  - name: listOrders
+    request:
+      method: GET
+      path: /orders
+`
+				err := os.WriteFile(configFile, []byte(configContent), 0644)
+				Expect(err).NotTo(HaveOccurred())
+
+				loader = config.NewLoader(configFile)
+				cfg, err = loader.Load()
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("应该只保留套件中的测试及其依赖，并按套件顺序排列", func() {
+				Expect(config.SelectSuite(cfg, "smoke")).To(Succeed())
+
+				names := []string{}
+				for _, api := range cfg.APIs {
+					names = append(names, api.Name)
+				}
+				Expect(names).To(Equal([]string{"login", "getProfile", "health"}))
+			})
+
+			It("未知的套件名称应该返回错误", func() {
+				err := config.SelectSuite(cfg, "nightly")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("unknown suite 'nightly'"))
+			})
+		})
+	})
+
+	Describe("SelectTags", func() {
+		var cfg *config.TestConfig
+
+		BeforeEach(func() {
+			cfg = &config.TestConfig{
+				APIs: []config.APITest{
+					{Name: "login", Tags: []string{"auth"}},
+					{Name: "health", Tags: []string{"smoke"}},
+					{Name: "getProfile", DependsOn: "login", Tags: []string{"smoke"}},
+					{Name: "export", Tags: []string{"smoke", "slow"}},
+					{Name: "listOrders"},
+				},
+			}
+		})
+
+		names := func() []string {
+			result := []string{}
+			for _, api := range cfg.APIs {
+				result = append(result, api.Name)
+			}
+			return result
+		}
+
+		It("应该保留带有指定标签的测试及其依赖，并保持配置顺序", func() {
+			Expect(config.SelectTags(cfg, "smoke")).To(Succeed())
+			Expect(names()).To(Equal([]string{"login", "health", "getProfile", "export"}))
+		})
+
+		It("应该排除带有 ! 前缀标签的测试", func() {
+			Expect(config.SelectTags(cfg, "smoke,!slow")).To(Succeed())
+			Expect(names()).To(Equal([]string{"login", "health", "getProfile"}))
+		})
+
+		It("只有排除标签时应该保留其余所有测试", func() {
+			Expect(config.SelectTags(cfg, "!slow")).To(Succeed())
+			Expect(names()).To(Equal([]string{"login", "health", "getProfile", "listOrders"}))
+		})
+
+		It("表达式为空时应该保留所有测试", func() {
+			Expect(config.SelectTags(cfg, "")).To(Succeed())
+			Expect(names()).To(HaveLen(5))
+		})
+
+		It("被选中的测试依赖带有排除标签的测试时应该返回错误", func() {
+			Expect(config.SelectTags(cfg, "smoke,!auth")).To(MatchError("test 'login' excluded by '!auth' is required by 'getProfile'"))
+		})
+
+		It("! 后缺少标签时应该返回错误", func() {
+			Expect(config.SelectTags(cfg, "smoke,!")).To(MatchError(ContainSubstring("missing tag")))
+		})
+	})
+
+	Describe("Include", func() {
+		write := func(name, content string) {
+			Expect(os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644)).To(Succeed())
+		}
+
+		Context("当引用了共享配置片段时", func() {
+			BeforeEach(func() {
+				write("common.yaml", `
+base_url: https://common.example.com
+timeout: 10s
+headers:
+  Content-Type: application/json
+  X-Env: common
+certificate:
+  ca_file: ca.pem
+apis:
+  - name: health
+`)
+				write("auth.yaml", `
+headers:
+  Authorization: Bearer token
+  X-Env: auth
+`)
+				write("test-config.yaml", `
+include:
+  - common.yaml
+  - auth.yaml
+base_url: https://api.example.com
+headers:
+  X-Env: local
+apis:
+  - name: list
+`)
+				loader = config.NewLoader(configFile)
+			})
+
+			It("应该合并引用的配置，后引用的文件和当前文件覆盖先前的值", func() {
+				cfg, err := loader.Load()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.BaseURL).To(Equal("https://api.example.com"))
+				Expect(cfg.Timeout.Seconds()).To(Equal(10.0))
+				Expect(cfg.Certificate.CAFile).To(Equal("ca.pem"))
+				Expect(cfg.Headers).To(Equal(map[string]string{
+					"Content-Type":  "application/json",
+					"Authorization": "Bearer token",
+					"X-Env":         "local",
+				}))
+			})
+
+			It("应该返回读取的配置文件和配置片段", func() {
+				_, err := loader.Load()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(loader.Files()).To(Equal([]string{
+					configFile,
+					filepath.Join(tmpDir, "common.yaml"),
+					filepath.Join(tmpDir, "auth.yaml"),
+				}))
+			})
+
+			It("应该按引用顺序追加接口列表", func() {
+				cfg, err := loader.Load()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.APIs).To(HaveLen(2))
+				Expect(cfg.APIs[0].Name).To(Equal("health"))
+				Expect(cfg.APIs[1].Name).To(Equal("list"))
+			})
+		})
+
+		Context("当测试引用了相对路径的文件时", func() {
+			It("应该相对于所在配置文件的目录解析 body_file 和 body_golden", func() {
+				Expect(os.Mkdir(filepath.Join(tmpDir, "shared"), 0755)).To(Succeed())
+				write("shared/common.yaml", `
+apis:
+  - name: create
+    request:
+      method: POST
+      url: /users
+      body_file: bodies/create.json
+    response:
+      status_code: 201
+`)
+				write("test-config.yaml", `
+include: [shared/common.yaml]
+apis:
+  - name: get
+    request:
+      method: GET
+      url: /users/1
+    response:
+      status_code: 200
+      body_golden: golden/user.json
+  - name: list
+    request:
+      method: GET
+      url: /users
+      body_file: /abs/list.json
+    response:
+      status_code: 200
+`)
+				cfg, err := config.NewLoader(configFile).Load()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.APIs).To(HaveLen(3))
+				Expect(cfg.APIs[0].Request.BodyFile).To(Equal(filepath.Join(tmpDir, "shared", "bodies", "create.json")))
+				Expect(cfg.APIs[1].Response.BodyGolden).To(Equal(filepath.Join(tmpDir, "golden", "user.json")))
+				Expect(cfg.APIs[2].Request.BodyFile).To(Equal("/abs/list.json"))
+			})
+
+			It("应该相对于所在配置文件的目录解析 hosts_file 和 json_schema 文件", func() {
+				Expect(os.Mkdir(filepath.Join(tmpDir, "shared"), 0755)).To(Succeed())
+				write("shared/common.yaml", `
+apis:
+  - name: health
+    hosts_file: hosts.txt
+    request:
+      method: GET
+      url: /health
+    response:
+      status_code: 200
+      json_schema: schemas/health.json
+`)
+				write("test-config.yaml", `
+include: [shared/common.yaml]
+apis:
+  - name: get
+    request:
+      method: GET
+      url: /users/1
+    response:
+      status_code: 200
+      json_schema: |
+        {"type": "object"}
+`)
+				cfg, err := config.NewLoader(configFile).Load()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.APIs).To(HaveLen(2))
+				Expect(cfg.APIs[0].HostsFile).To(Equal(filepath.Join(tmpDir, "shared", "hosts.txt")))
+				Expect(cfg.APIs[0].Response.JSONSchema).To(Equal(filepath.Join(tmpDir, "shared", "schemas", "health.json")))
+				Expect(cfg.APIs[1].Response.JSONSchema).To(Equal("{\"type\": \"object\"}\n"))
+			})
+		})
+
+		Context("当配置存在类型错误时", func() {
+			It("没有 include 时错误信息中的行号应该与配置文件一致", func() {
+				write("test-config.yaml", `base_url: https://api.example.com
+
+apis:
+  - name: list
+    request:
+      method: GET
+      url: /list
+    response:
+      status_code: ok
+`)
+				_, err := config.NewLoader(configFile).Load()
+				Expect(err).To(MatchError(ContainSubstring("line 9:")))
+			})
+
+			It("有 include 时错误信息中的行号应该是值在所在文件中的行号", func() {
+				write("common.yaml", `base_url: https://api.example.com
+apis:
+  - name: health
+    request:
+      method: GET
+      url: /health
+    response:
+      status_code: ok
+`)
+				write("test-config.yaml", `include: [common.yaml]
+headers:
+  X-Env: local
+`)
+				_, err := config.NewLoader(configFile).Load()
+				Expect(err).To(MatchError(ContainSubstring("line 8:")))
+			})
+		})
+
+		Context("当存在循环引用时", func() {
+			BeforeEach(func() {
+				write("a.yaml", "include: [b.yaml]\n")
+				write("b.yaml", "include: [test-config.yaml]\n")
+				write("test-config.yaml", "include: [a.yaml]\n")
+				loader = config.NewLoader(configFile)
+			})
+
+			It("应该返回错误", func() {
+				_, err := loader.Load()
+				Expect(err).To(MatchError(ContainSubstring("circular include")))
+				Expect(err.Error()).To(ContainSubstring("a.yaml -> "))
+			})
+		})
+
+		Context("当引用的文件不存在时", func() {
+			BeforeEach(func() {
+				write("test-config.yaml", "include: [missing.yaml]\n")
+				loader = config.NewLoader(configFile)
+			})
+
+			It("应该返回错误", func() {
+				_, err := loader.Load()
+				Expect(err).To(MatchError(ContainSubstring("failed to include")))
+			})
+		})
+	})
+
+	Describe("ApplyEnvironment", func() {
+		var cfg *config.TestConfig
+
+		BeforeEach(func() {
+			cfg = &config.TestConfig{
+				BaseURL:     "https://dev.example.com",
+				Headers:     map[string]string{"Content-Type": "application/json", "X-Env": "dev"},
+				Certificate: config.CertConfig{CAFile: "dev-ca.pem", CertFile: "client.pem"},
+				Variables:   map[string]interface{}{"userId": 1, "region": "cn"},
+				Environments: map[string]config.Environment{
+					"staging": {BaseURL: "https://staging.example.com"},
+					"prod": {
+						BaseURL:     "https://api.example.com",
+						Headers:     map[string]string{"X-Env": "prod"},
+						Certificate: config.CertConfig{CAFile: "prod-ca.pem"},
+						Variables:   map[string]interface{}{"userId": 42},
+					},
+				},
+			}
+		})
+
+		It("应该用选中环境的配置覆盖基础配置", func() {
+			Expect(config.ApplyEnvironment(cfg, "prod")).To(Succeed())
+			Expect(cfg.BaseURL).To(Equal("https://api.example.com"))
+			Expect(cfg.Headers).To(Equal(map[string]string{"Content-Type": "application/json", "X-Env": "prod"}))
+			Expect(cfg.Certificate.CAFile).To(Equal("prod-ca.pem"))
+			Expect(cfg.Certificate.CertFile).To(Equal("client.pem"))
+			Expect(cfg.Variables).To(Equal(map[string]interface{}{"userId": 42, "region": "cn"}))
+		})
+
+		It("环境中未设置的字段应该保持不变", func() {
+			Expect(config.ApplyEnvironment(cfg, "staging")).To(Succeed())
+			Expect(cfg.BaseURL).To(Equal("https://staging.example.com"))
+			Expect(cfg.Headers["X-Env"]).To(Equal("dev"))
+			Expect(cfg.Variables["userId"]).To(Equal(1))
+		})
+
+		It("环境名称为空时不应该修改配置", func() {
+			Expect(config.ApplyEnvironment(cfg, "")).To(Succeed())
+			Expect(cfg.BaseURL).To(Equal("https://dev.example.com"))
+		})
+
+		It("未知的环境名称应该返回错误", func() {
+			err := config.ApplyEnvironment(cfg, "qa")
+			Expect(err).To(MatchError(ContainSubstring("unknown environment 'qa'")))
+			Expect(err.Error()).To(ContainSubstring("prod, staging"))
+		})
+	})
+
 	Describe("MergeConfig", func() {
 		var baseConfig *config.TestConfig
 
@@ -6,65 +6,175 @@ import (
 
 // TestConfig 测试配置
 type TestConfig struct {
-	BaseURL     string            `yaml:"base_url"`
-	Version     string            `yaml:"version"`
-	Certificate CertConfig        `yaml:"certificate"`
-	Timeout     time.Duration     `yaml:"timeout"`
-	Headers     map[string]string `yaml:"headers"`
-	APIs        []APITest         `yaml:"apis"`
+	BaseURL         string                 `yaml:"base_url" json:"base_url"`
+	Version         string                 `yaml:"version" json:"version"`
+	Certificate     CertConfig             `yaml:"certificate" json:"certificate"`
+	Timeout         time.Duration          `yaml:"timeout" json:"timeout"`
+	ThinkTime       time.Duration          `yaml:"think_time" json:"think_time"`               // 顺序执行时每个请求之前的等待时间（第一个请求除外），不计入请求耗时
+	RateLimit       float64                `yaml:"rate_limit" json:"rate_limit"`               // 每秒最多发送的请求数（所有并发请求共享，包括重试），为 0 时不限制
+	MaxResponseSize int64                  `yaml:"max_response_size" json:"max_response_size"` // 响应体的最大字节数（解压后），超过时测试失败，默认 10MB
+	FollowRedirects *bool                  `yaml:"follow_redirects" json:"follow_redirects"`   // 是否自动跟随重定向，默认跟随
+	Proxy           string                 `yaml:"proxy" json:"proxy"`                         // HTTP 代理地址，env 表示读取 HTTP_PROXY/HTTPS_PROXY/NO_PROXY 环境变量
+	UseCookies      bool                   `yaml:"use_cookies" json:"use_cookies"`             // 是否启用 Cookie，响应设置的 Cookie 在同一次运行中自动随后续请求发送
+	Headers         map[string]string      `yaml:"headers" json:"headers"`
+	Redact          []string               `yaml:"redact" json:"redact"`               // 调试日志中需要隐藏值的请求头或请求体字段名（不区分大小写），追加到默认列表
+	Auth            *AuthConfig            `yaml:"auth" json:"auth"`                   // 全局认证配置，可被单个测试覆盖
+	AuthFlow        *AuthFlow              `yaml:"auth_flow" json:"auth_flow"`         // 登录认证流程，登录测试通过后自动为其它请求添加 token 请求头
+	SuccessField    *SuccessField          `yaml:"success_field" json:"success_field"` // 全局业务成功字段判定，可被单个测试覆盖
+	Suites          map[string][]string    `yaml:"suites" json:"suites"`               // 测试套件，套件名称到测试名称列表的映射
+	Variables       map[string]interface{} `yaml:"variables" json:"variables"`         // 全局变量，通过 {{name}} 引用，capture 捕获的同名变量会覆盖
+	RandSeed        int64                  `yaml:"rand_seed" json:"rand_seed"`         // 随机种子，非 0 时 {{$random.*}} 每次运行生成相同的值（仅用于调试，不可用于安全场景）
+	Environments    map[string]Environment `yaml:"environments" json:"environments"`   // 环境配置，通过 -env 选择后覆盖基础配置
+	Setup           []APITest              `yaml:"setup" json:"setup"`                 // 在所有测试之前按顺序执行的准备接口
+	APIs            []APITest              `yaml:"apis" json:"apis"`
+	Teardown        []APITest              `yaml:"teardown" json:"teardown"` // 在所有测试之后按顺序执行的清理接口，测试失败时也会执行
+}
+
+// Environment 环境配置，选中后覆盖基础配置中的对应字段
+// 未设置的字段保持基础配置不变，headers 和 variables 按键合并
+type Environment struct {
+	BaseURL     string                 `yaml:"base_url" json:"base_url"`
+	Headers     map[string]string      `yaml:"headers" json:"headers"`
+	Certificate CertConfig             `yaml:"certificate" json:"certificate"`
+	Variables   map[string]interface{} `yaml:"variables" json:"variables"`
+}
+
+// AuthConfig 认证配置，自动生成 Authorization 请求头
+type AuthConfig struct {
+	Type     string `yaml:"type" json:"type"` // basic, bearer, none（禁用全局认证）
+	Username string `yaml:"username" json:"username"`
+	Password string `yaml:"password" json:"password"`
+	Token    string `yaml:"token" json:"token"`
+}
+
+// AuthFlow 登录认证流程
+// 登录测试通过后从其响应中提取 token，之后发送的其它请求自动携带 header: prefix+token
+type AuthFlow struct {
+	Login     string `yaml:"login" json:"login"`           // 登录测试的名称，可以是 setup 或 apis 中的测试
+	TokenPath string `yaml:"token_path" json:"token_path"` // token 在登录响应中的字段路径，如 "data.token"
+	Header    string `yaml:"header" json:"header"`         // 携带 token 的请求头，默认 Authorization
+	Prefix    string `yaml:"prefix" json:"prefix"`         // token 前缀，如 "Bearer "，为空时直接使用 token
 }
 
 // CertConfig 证书配置
 type CertConfig struct {
-	CertFile string `yaml:"cert_file"`
-	KeyFile  string `yaml:"key_file"`
-	CAFile   string `yaml:"ca_file"`
+	CertFile           string `yaml:"cert_file" json:"cert_file"`
+	KeyFile            string `yaml:"key_file" json:"key_file"`
+	CAFile             string `yaml:"ca_file" json:"ca_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify" json:"insecure_skip_verify"` // 跳过服务器证书校验（默认关闭，仅用于测试环境）
 }
 
 // APITest 接口测试定义
 type APITest struct {
-	Name        string              `yaml:"name"`
-	Description string              `yaml:"description"`
-	Version     string              `yaml:"version"`    // 支持特定版本
-	Versions    []string            `yaml:"versions"`   // 支持多版本
-	Weight      int                 `yaml:"weight"`     // 权重，数字越大优先级越高，默认为0
-	DependsOn   string              `yaml:"depends_on"` // 依赖的接口名称，该接口会在依赖接口执行成功后才执行
-	Request     RequestConfig       `yaml:"request"`
-	Response    ResponseExpectation `yaml:"response"`
-	RetryPolicy RetryPolicy         `yaml:"retry_policy"`
+	Name        string                   `yaml:"name" json:"name"`
+	Description string                   `yaml:"description" json:"description"`
+	Version     string                   `yaml:"version" json:"version"`         // 支持特定版本
+	Versions    []string                 `yaml:"versions" json:"versions"`       // 支持多版本
+	Weight      int                      `yaml:"weight" json:"weight"`           // 权重，数字越大优先级越高，默认为0
+	DependsOn   string                   `yaml:"depends_on" json:"depends_on"`   // 依赖的接口名称，该接口会在依赖接口执行成功后才执行
+	When        string                   `yaml:"when" json:"when"`               // 执行条件，如 "{{创建.response.data.deletable}} == true"，不满足时跳过
+	Disabled    bool                     `yaml:"disabled" json:"disabled"`       // 是否禁用，禁用的接口会被标记为跳过且不会执行
+	Skip        bool                     `yaml:"skip" json:"skip"`               // 同 disabled
+	SkipReason  string                   `yaml:"skip_reason" json:"skip_reason"` // 禁用的原因，显示在报告的跳过原因中
+	Only        bool                     `yaml:"only" json:"only"`               // 聚焦测试，任一测试设置后只执行设置了 only 的测试及其依赖
+	Delay       time.Duration            `yaml:"delay" json:"delay"`             // 顺序执行时发送本测试的请求之前的等待时间，覆盖全局的 think_time
+	Tags        []string                 `yaml:"tags" json:"tags"`               // 标签，用于通过 -tags 筛选测试
+	Type        string                   `yaml:"type" json:"type"`               // 测试类型，为空时为普通测试，idempotency 表示幂等性测试
+	HostsFile   string                   `yaml:"hosts_file" json:"hosts_file"`   // 主机列表文件（相对于所在配置文件的目录），每行一个主机，测试会对每个主机各执行一次
+	Capture     map[string]string        `yaml:"capture" json:"capture"`         // 测试通过后捕获的命名变量，键为变量名，值为字段路径或 {{...}} 模板
+	Dataset     []map[string]interface{} `yaml:"dataset" json:"dataset"`         // 数据驱动测试，每行执行一次，通过 {{row.字段}} 引用行数据
+	Request     RequestConfig            `yaml:"request" json:"request"`
+	Response    ResponseExpectation      `yaml:"response" json:"response"`
+	RetryPolicy RetryPolicy              `yaml:"retry_policy" json:"retry_policy"`
 }
 
 // RequestConfig 请求配置
 type RequestConfig struct {
-	Method     string                 `yaml:"method"`
-	Path       string                 `yaml:"path"`
-	Headers    map[string]string      `yaml:"headers"`
-	Query      map[string]interface{} `yaml:"query"`
-	Body       interface{}            `yaml:"body"`
-	BodySchema map[string]string      `yaml:"body_schema"` // 请求体字段类型约束: int, string, bool, float, array, object
+	BaseURL         string                 `yaml:"base_url" json:"base_url"` // 覆盖全局的基础URL
+	Method          string                 `yaml:"method" json:"method"`
+	Path            string                 `yaml:"path" json:"path"`
+	Headers         map[string]string      `yaml:"headers" json:"headers"`
+	Auth            *AuthConfig            `yaml:"auth" json:"auth"`                         // 认证配置，覆盖全局认证
+	FollowRedirects *bool                  `yaml:"follow_redirects" json:"follow_redirects"` // 是否自动跟随重定向，覆盖全局配置
+	Timeout         time.Duration          `yaml:"timeout" json:"timeout"`                   // 请求超时时间，覆盖全局配置
+	Query           map[string]interface{} `yaml:"query" json:"query"`
+	QueryParams     []QueryParam           `yaml:"query_params" json:"query_params"` // 按声明顺序拼接的查询参数，不排序，适用于签名依赖参数顺序的接口
+	Body            interface{}            `yaml:"body" json:"body"`
+	BodyFile        string                 `yaml:"body_file" json:"body_file"`     // 从 JSON 文件加载请求体（相对于所在配置文件的目录），设置后覆盖 body
+	BodySchema      map[string]string      `yaml:"body_schema" json:"body_schema"` // 请求体字段类型约束: int, string, bool, float, array, object
+	GraphQL         *GraphQLRequest        `yaml:"graphql" json:"graphql"`         // GraphQL 请求，包装为 {"query": ..., "variables": ...} 发送，设置后覆盖 body
+}
+
+// GraphQLRequest GraphQL 请求的查询和变量
+type GraphQLRequest struct {
+	Query         string                 `yaml:"query" json:"query"`
+	Variables     map[string]interface{} `yaml:"variables" json:"variables"`
+	OperationName string                 `yaml:"operation_name" json:"operation_name"` // 查询中包含多个操作时指定要执行的操作
+}
+
+// QueryParam 有序查询参数，value 为数组时展开为同名的多个参数
+type QueryParam struct {
+	Name  string      `yaml:"name" json:"name"`
+	Value interface{} `yaml:"value" json:"value"`
 }
 
 // ResponseExpectation 响应预期
 type ResponseExpectation struct {
-	StatusCode   int                    `yaml:"status_code"`
-	Headers      map[string]string      `yaml:"headers"`
-	Body         map[string]interface{} `yaml:"body"`
-	BodyContains []string               `yaml:"body_contains"`
-	BodyExcludes []string               `yaml:"body_excludes"`
-	JSONSchema   string                 `yaml:"json_schema"`
-	Validators   []Validator            `yaml:"validators"`
+	StatusCode       int                       `yaml:"status_code" json:"status_code"`
+	StatusCodeIn     []int                     `yaml:"status_code_in" json:"status_code_in"`       // 允许的状态码列表，如 [200, 201]
+	StatusCodeRange  string                    `yaml:"status_code_range" json:"status_code_range"` // 允许的状态码范围，如 "200-299" 或 "2xx"
+	Headers          map[string]string         `yaml:"headers" json:"headers"`
+	HeaderValidators []Validator               `yaml:"header_validators" json:"header_validators"` // 响应头验证器，field 为响应头名称，支持 equals/contains/icontains/regex（任一值满足即通过）和 exists/not_exists
+	Body             map[string]interface{}    `yaml:"body" json:"body"`
+	BodyContains     []string                  `yaml:"body_contains" json:"body_contains"`
+	BodyExcludes     []string                  `yaml:"body_excludes" json:"body_excludes"`
+	BodyEquals       string                    `yaml:"body_equals" json:"body_equals"`   // 原始响应体需完全等于该文本，不要求 JSON
+	BodyRegex        string                    `yaml:"body_regex" json:"body_regex"`     // 原始响应体需匹配该正则表达式，不要求 JSON
+	BodyIsJSON       bool                      `yaml:"body_is_json" json:"body_is_json"` // 非空响应体必须是合法的 JSON，否则报告解析错误
+	JSONSchema       string                    `yaml:"json_schema" json:"json_schema"`   // 内联的 JSON Schema 或 Schema 文件路径（相对于所在配置文件的目录）
+	Validators       []Validator               `yaml:"validators" json:"validators"`
+	SuccessField     *SuccessField             `yaml:"success_field" json:"success_field"`           // 覆盖全局的业务成功字段判定，path 为空时禁用
+	BodyGolden       string                    `yaml:"body_golden" json:"body_golden"`               // golden 文件路径（相对于所在配置文件的目录），响应体需与文件中的 JSON 一致（ignore_fields 中的字段除外）
+	IgnoreFields     []string                  `yaml:"ignore_fields" json:"ignore_fields"`           // 比较响应体（幂等性测试、body_golden）时忽略的字段路径，如 "data.created_at"
+	ContentLength    *ContentLengthExpectation `yaml:"content_length" json:"content_length"`         // 响应体长度预期
+	DeprecatedFields []string                  `yaml:"deprecated_fields" json:"deprecated_fields"`   // 已废弃的字段路径，响应中仍存在时记录警告
+	WarnResponseTime time.Duration             `yaml:"warn_response_time" json:"warn_response_time"` // 响应时间告警阈值，超过时只记录警告，不判定失败
+}
+
+// ContentLengthExpectation 响应体长度预期（字节数）
+// 优先使用 Content-Length 响应头，缺失时使用实际响应体长度
+type ContentLengthExpectation struct {
+	Equals *int64 `yaml:"equals" json:"equals"` // 精确长度
+	Min    *int64 `yaml:"min" json:"min"`       // 最小长度（包含）
+	Max    *int64 `yaml:"max" json:"max"`       // 最大长度（包含）
+}
+
+// SuccessField 业务成功字段判定
+// 用于 HTTP 状态码始终为 200、通过响应体字段（如 code）表示业务结果的接口
+type SuccessField struct {
+	Path   string      `yaml:"path" json:"path"`     // JSON路径，如 "code"
+	Equals interface{} `yaml:"equals" json:"equals"` // 表示成功的值，如 0
 }
 
 // Validator 验证器配置
 type Validator struct {
-	Type   string      `yaml:"type"`   // equals, contains, regex, custom
-	Field  string      `yaml:"field"`  // JSON路径，如 "data.user.id"
-	Value  interface{} `yaml:"value"`  // 期望值
-	Expect interface{} `yaml:"expect"` // 期望值（别名）
+	Type       string      `yaml:"type" json:"type"`               // equals, contains, regex, custom
+	Field      string      `yaml:"field" json:"field"`             // JSON路径，如 "data.user.id"
+	Value      interface{} `yaml:"value" json:"value"`             // 期望值
+	Expect     interface{} `yaml:"expect" json:"expect"`           // 期望值（别名）
+	Where      *Validator  `yaml:"where" json:"where"`             // 子条件，用于 count_where 对数组元素进行筛选
+	Severity   string      `yaml:"severity" json:"severity"`       // 严重级别，为 warning 时验证不通过只记录警告，默认为 error
+	IgnoreCase bool        `yaml:"ignore_case" json:"ignore_case"` // contains、starts_with、ends_with 验证时忽略大小写
+	Trim       bool        `yaml:"trim" json:"trim"`               // contains、starts_with、ends_with 验证前去除字段值和期望值首尾的空白字符
+	Not        bool        `yaml:"not" json:"not"`                 // 取反验证结果，验证不通过时才算通过
 }
 
 // RetryPolicy 重试策略
 type RetryPolicy struct {
-	MaxRetries int           `yaml:"max_retries"`
-	Interval   time.Duration `yaml:"interval"`
+	MaxRetries        int           `yaml:"max_retries" json:"max_retries"`
+	Interval          time.Duration `yaml:"interval" json:"interval"`
+	BackoffMultiplier float64       `yaml:"backoff_multiplier" json:"backoff_multiplier"` // 退避倍数，第 n 次重试等待 Interval * multiplier^(n-1)，为 0 或 1 时固定间隔
+	MaxInterval       time.Duration `yaml:"max_interval" json:"max_interval"`             // 最大重试间隔，为 0 时不限制
+	Jitter            bool          `yaml:"jitter" json:"jitter"`                         // 是否对重试间隔进行 ±20% 的随机抖动
+	RetryOn           []int         `yaml:"retry_on" json:"retry_on"`                     // 允许重试的状态码，为空时验证失败即重试
 }
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// validatorTypes 支持的验证器类型（不区分大小写），需与 validator 包保持一致
+// validator 包依赖本包，无法反向引用；validator 包的测试会检查其支持的每种类型都在此列表中
+var validatorTypes = map[string]bool{
+	"equals": true, "equal": true, "eq": true,
+	"not_equals": true, "ne": true,
+	"contains": true, "icontains": true,
+	"starts_with": true, "ends_with": true,
+	"regex": true, "regexp": true,
+	"not_empty": true, "notempty": true,
+	"exists": true, "not_exists": true,
+	"type": true, "count_where": true, "each": true,
+	"gt": true, "gte": true, "lt": true, "lte": true, "range": true,
+	"length": true, "min_length": true, "max_length": true,
+	"in": true, "not_in": true,
+	"sha256": true, "md5": true, "compression_ratio": true,
+}
+
+// headerValidatorTypes 响应头验证器支持的类型，为空时按 equals 处理
+var headerValidatorTypes = map[string]bool{
+	"": true, "equals": true, "equal": true, "eq": true,
+	"contains": true, "icontains": true,
+	"regex": true, "regexp": true,
+	"exists": true, "not_exists": true,
+}
+
+// bodySchemaTypes body_schema 支持的字段类型
+var bodySchemaTypes = map[string]bool{
+	"int": true, "float": true, "float64": true, "string": true,
+	"bool": true, "boolean": true, "array": true, "slice": true,
+	"object": true, "map": true,
+}
+
+// Validate 在执行前检查配置中的常见错误：测试名称重复、依赖的测试不存在、循环依赖、
+// auth_flow 配置不完整、未知的验证器类型和 body_schema 类型，返回的错误列出发现的所有问题
+func (c *TestConfig) Validate() error {
+	problems := make([]string, 0)
+
+	// 准备接口的结果同样可以被依赖
+	names := make(map[string]bool, len(c.Setup)+len(c.APIs))
+	for _, hook := range c.Setup {
+		names[hook.Name] = true
+	}
+	seen := make(map[string]bool, len(c.APIs))
+	for _, api := range c.APIs {
+		if seen[api.Name] {
+			problems = append(problems, fmt.Sprintf("duplicate test name '%s'", api.Name))
+		}
+		seen[api.Name] = true
+		names[api.Name] = true
+	}
+
+	for _, api := range c.APIs {
+		if api.DependsOn != "" && !names[api.DependsOn] {
+			problems = append(problems, fmt.Sprintf("test '%s' depends on unknown test '%s'", api.Name, api.DependsOn))
+		}
+	}
+	problems = append(problems, dependencyCycles(c.APIs)...)
+
+	if c.AuthFlow != nil {
+		if c.AuthFlow.Login == "" {
+			problems = append(problems, "auth_flow.login is required")
+		} else if !names[c.AuthFlow.Login] {
+			problems = append(problems, fmt.Sprintf("auth_flow.login refers to unknown test '%s'", c.AuthFlow.Login))
+		}
+		if c.AuthFlow.TokenPath == "" {
+			problems = append(problems, "auth_flow.token_path is required")
+		}
+	}
+
+	groups := [][]APITest{c.Setup, c.APIs, c.Teardown}
+	for _, tests := range groups {
+		for _, test := range tests {
+			problems = append(problems, validateTest(test)...)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid config:\n  - %s", strings.Join(problems, "\n  - "))
+}
+
+// dependencyCycles 查找 depends_on 形成的循环，每个循环报告一次，如 "a -> b -> a"
+func dependencyCycles(apis []APITest) []string {
+	dependsOn := make(map[string]string, len(apis))
+	for _, api := range apis {
+		if _, ok := dependsOn[api.Name]; !ok {
+			dependsOn[api.Name] = api.DependsOn
+		}
+	}
+
+	problems := make([]string, 0)
+	reported := make(map[string]bool)
+	for _, api := range apis {
+		path := []string{api.Name}
+		index := map[string]int{api.Name: 0}
+		for name := dependsOn[api.Name]; name != ""; name = dependsOn[name] {
+			if start, ok := index[name]; ok {
+				cycle := path[start:]
+				if !reported[cycleKey(cycle)] {
+					reported[cycleKey(cycle)] = true
+					problems = append(problems, fmt.Sprintf("circular dependency: %s -> %s", strings.Join(cycle, " -> "), name))
+				}
+				break
+			}
+			if _, ok := dependsOn[name]; !ok {
+				break
+			}
+			index[name] = len(path)
+			path = append(path, name)
+		}
+	}
+	return problems
+}
+
+// cycleKey 生成与起点无关的循环标识，避免同一个循环被重复报告
+func cycleKey(cycle []string) string {
+	sorted := append([]string(nil), cycle...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, "\x00")
+}
+
+// validateTest 检查单个测试的验证器类型和 body_schema 类型
+func validateTest(test APITest) []string {
+	problems := make([]string, 0)
+	for _, validator := range test.Response.Validators {
+		problems = append(problems, validateValidatorType(test.Name, validator)...)
+	}
+	for _, validator := range test.Response.HeaderValidators {
+		if !headerValidatorTypes[strings.ToLower(validator.Type)] {
+			problems = append(problems, fmt.Sprintf("test '%s': unsupported header validator type '%s'", test.Name, validator.Type))
+		}
+	}
+
+	fields := make([]string, 0, len(test.Request.BodySchema))
+	for field := range test.Request.BodySchema {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		if fieldType := test.Request.BodySchema[field]; !bodySchemaTypes[fieldType] {
+			problems = append(problems, fmt.Sprintf("test '%s': unsupported body_schema type '%s' for field '%s'", test.Name, fieldType, field))
+		}
+	}
+	return problems
+}
+
+// validateValidatorType 检查验证器类型，包括 count_where 的 where 条件和 each 的子验证器
+func validateValidatorType(testName string, validator Validator) []string {
+	problems := make([]string, 0)
+	validatorType := strings.ToLower(validator.Type)
+	if !validatorTypes[validatorType] {
+		problems = append(problems, fmt.Sprintf("test '%s': unknown validator type '%s'", testName, validator.Type))
+	}
+	if validator.Where != nil {
+		problems = append(problems, validateValidatorType(testName, *validator.Where)...)
+	}
+
+	// each 的子验证器在运行时从 value 解析，这里按同样的方式解析后递归检查
+	if validatorType == "each" {
+		expected := validator.Value
+		if expected == nil {
+			expected = validator.Expect
+		}
+		var rules []Validator
+		if data, err := json.Marshal(expected); err == nil && json.Unmarshal(data, &rules) == nil {
+			for _, rule := range rules {
+				problems = append(problems, validateValidatorType(testName, rule)...)
+			}
+		}
+	}
+	return problems
+}
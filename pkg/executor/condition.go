@@ -0,0 +1,166 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"api_auto_test/pkg/config"
+)
+
+// conditionOperators when 表达式支持的比较运算符
+var conditionOperators = []string{"==", "!="}
+
+// checkCondition 计算测试的 when 条件，需要执行时返回 nil
+// 条件不满足时返回跳过的结果，表达式无效时返回失败的结果
+func (e *Executor) checkCondition(apiTest config.APITest) *TestResult {
+	if strings.TrimSpace(apiTest.When) == "" {
+		return nil
+	}
+
+	met, err := e.evaluateCondition(apiTest.When)
+	if err != nil {
+		return &TestResult{
+			Name:        apiTest.Name,
+			Description: apiTest.Description,
+			Version:     apiTest.Version,
+			Request:     apiTest.Request,
+			ExecutedAt:  time.Now(),
+			Error:       fmt.Errorf("invalid when condition '%s': %w", apiTest.When, err),
+		}
+	}
+	if !met {
+		result := e.newSkippedResult(apiTest, fmt.Sprintf("condition not met: %s", apiTest.When))
+		return &result
+	}
+	return nil
+}
+
+// evaluateCondition 计算 when 表达式
+// 支持 "左值 == 右值"、"左值 != 右值"，以及单独的变量引用（按真值判断）
+// 两侧可以是变量引用（如 {{创建.response.data.deletable}}）或字面量，变量不存在时视为 null
+func (e *Executor) evaluateCondition(expr string) (bool, error) {
+	if idx, op := findConditionOperator(expr); idx >= 0 {
+		left, err := e.conditionOperand(expr[:idx])
+		if err != nil {
+			return false, err
+		}
+		right, err := e.conditionOperand(expr[idx+len(op):])
+		if err != nil {
+			return false, err
+		}
+
+		equal := conditionValuesEqual(left, right)
+		if op == "!=" {
+			return !equal, nil
+		}
+		return equal, nil
+	}
+
+	value, err := e.conditionOperand(expr)
+	if err != nil {
+		return false, err
+	}
+	return isTruthy(value), nil
+}
+
+// findConditionOperator 返回表达式中第一个比较运算符的位置和运算符，没有时返回 -1
+// 引号括起的字面量（如 'a==b'）和变量引用 {{...}} 中的字符不作为运算符
+func findConditionOperator(expr string) (int, string) {
+	var quote byte
+	inVariable := false
+	for i := 0; i < len(expr); i++ {
+		switch {
+		case quote != 0:
+			if expr[i] == quote {
+				quote = 0
+			}
+			continue
+		case inVariable:
+			if strings.HasPrefix(expr[i:], "}}") {
+				inVariable = false
+				i++
+			}
+			continue
+		case expr[i] == '\'' || expr[i] == '"':
+			quote = expr[i]
+			continue
+		case strings.HasPrefix(expr[i:], "{{"):
+			inVariable = true
+			i++
+			continue
+		}
+		for _, op := range conditionOperators {
+			if strings.HasPrefix(expr[i:], op) {
+				return i, op
+			}
+		}
+	}
+	return -1, ""
+}
+
+// conditionOperand 解析条件表达式一侧的值
+func (e *Executor) conditionOperand(operand string) (interface{}, error) {
+	operand = strings.TrimSpace(operand)
+	if operand == "" {
+		return nil, fmt.Errorf("missing operand")
+	}
+
+	// 整个操作数是单个变量引用时保持原始类型
+	matches := varPattern.FindAllStringSubmatch(operand, -1)
+	if len(matches) == 1 && operand == matches[0][0] {
+		value, _ := e.resolveVariable(matches[0][1], nil)
+		return value, nil
+	}
+	if len(matches) > 0 {
+		return e.replaceInString(operand, nil), nil
+	}
+
+	if operand == "null" {
+		return nil, nil
+	}
+	return e.parseLiteral(operand), nil
+}
+
+// conditionValuesEqual 比较条件两侧的值，数值按大小比较，其他类型按字符串形式比较
+func conditionValuesEqual(left, right interface{}) bool {
+	if left == nil || right == nil {
+		return left == nil && right == nil
+	}
+
+	leftNumber, leftOK := toFloat(left)
+	rightNumber, rightOK := toFloat(right)
+	if leftOK && rightOK {
+		return leftNumber == rightNumber
+	}
+	return fmt.Sprintf("%v", left) == fmt.Sprintf("%v", right)
+}
+
+// toFloat 将 JSON/YAML 解析出的数值类型转为 float64
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// isTruthy 判断单独的变量引用是否为真：null、false、0 和空字符串为假
+func isTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != "" && v != "false"
+	}
+	if number, ok := toFloat(value); ok {
+		return number != 0
+	}
+	return true
+}
@@ -5,16 +5,21 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math"
 	"math/big"
+	mathrand "math/rand"
+	"os"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"api_auto_test/pkg/client"
 	"api_auto_test/pkg/config"
+	"api_auto_test/pkg/logger"
 	"api_auto_test/pkg/validator"
 )
 
@@ -26,6 +31,7 @@ type TestResult struct {
 	Passed      bool
 	Skipped     bool   // 是否被跳过
 	SkipReason  string // 跳过原因
+	Aborted     bool   // 是否因 fail-fast 中止而被跳过
 	Duration    time.Duration
 	StatusCode  int
 	Request     config.RequestConfig
@@ -33,7 +39,27 @@ type TestResult struct {
 	Validation  *validator.ValidationResult
 	Error       error
 	RetryCount  int
+	Attempts    []int // 每次尝试的状态码，请求发送失败时为 0
 	ExecutedAt  time.Time
+	HostResults []HostResult            // 按主机展开执行时每个主机的结果
+	Prepared    *client.PreparedRequest // dry-run 模式下组装的请求（未发送）
+	ConfigFile  string                  // 合并多个配置文件的报告时，结果所属的配置文件名称
+	Iteration   int                     // 重复执行时结果所属的轮次（从 1 开始），未重复执行时为 0
+}
+
+// Flaky 测试经过重试后才通过，说明结果不稳定
+func (r TestResult) Flaky() bool {
+	return r.Passed && !r.Skipped && r.RetryCount > 0
+}
+
+// HostResult 单个主机的执行结果
+type HostResult struct {
+	Host       string
+	Passed     bool
+	StatusCode int
+	Duration   time.Duration
+	Validation *validator.ValidationResult
+	Error      error
 }
 
 // TestReport 测试报告
@@ -41,22 +67,105 @@ type TestReport struct {
 	TotalTests     int
 	PassedTests    int
 	FailedTests    int
-	SkippedTests   int // 跳过的测试数量
+	SkippedTests   int // 跳过的测试数量（包括因 fail-fast 中止的测试）
+	AbortedTests   int // 因 fail-fast 中止的测试数量
+	FailedHooks    int // 执行失败的 setup/teardown 接口数量，不计入 TotalTests，但同样使报告判定为失败
 	Duration       time.Duration
 	Results        []TestResult
 	StartTime      time.Time
 	EndTime        time.Time
 	Version        string
 	BaseURL        string
-	ConfigFileName string // 配置文件名称（不含路径）
+	ConfigFileName string      // 配置文件名称（不含路径）
+	Iterations     int         // 重复执行的轮数，未重复执行时为 0
+	FlakyTests     []FlakyTest // 重复执行时部分轮次通过、部分轮次失败的测试
+	// setup/teardown 接口的结果单独记录，不计入上面的测试数量统计
+	SetupResults    []TestResult
+	TeardownResults []TestResult
+	// 执行任何测试之前发生的错误（如循环依赖），此时不会执行任何测试
+	Error error
+	// 顺序执行时在请求之间等待（think_time 和 delay）的总时长，不计入各测试的耗时
+	ThinkTime time.Duration
+}
+
+// disabledSkipReason 被禁用接口的跳过原因
+const disabledSkipReason = "disabled"
+
+// notFocusedSkipReason 存在 only 测试时未被聚焦的测试的跳过原因
+const notFocusedSkipReason = "not focused"
+
+// focusedTests 返回设置了 only 的测试及其依赖链上的所有测试，没有测试设置 only 时返回 nil
+func focusedTests(apis []config.APITest) map[string]bool {
+	dependsOn := make(map[string]string, len(apis))
+	for _, api := range apis {
+		dependsOn[api.Name] = api.DependsOn
+	}
+
+	var focused map[string]bool
+	for _, api := range apis {
+		if !api.Only {
+			continue
+		}
+		if focused == nil {
+			focused = make(map[string]bool)
+		}
+		// 依赖关系已确认无循环，沿依赖链标记直到没有依赖或已标记过
+		for name := api.Name; name != "" && !focused[name]; name = dependsOn[name] {
+			focused[name] = true
+		}
+	}
+	return focused
+}
+
+// isDisabled 判断测试是否通过 disabled 或 skip 被禁用
+func isDisabled(apiTest config.APITest) bool {
+	return apiTest.Disabled || apiTest.Skip
+}
+
+// disabledReason 被禁用接口的跳过原因，设置了 skip_reason 时为 "disabled: 原因"
+func disabledReason(apiTest config.APITest) string {
+	if apiTest.SkipReason == "" {
+		return disabledSkipReason
+	}
+	return disabledSkipReason + ": " + apiTest.SkipReason
+}
+
+// isDisabledResult 判断测试结果是否因接口被禁用而跳过
+func isDisabledResult(result *TestResult) bool {
+	return result.Skipped && (result.SkipReason == disabledSkipReason || strings.HasPrefix(result.SkipReason, disabledSkipReason+": "))
 }
 
+// dryRunSkipReason dry-run 模式下只组装请求不发送的测试的跳过原因
+const dryRunSkipReason = "dry-run"
+
+// failFastSkipReason 开启 fail-fast 后，首个失败之后的测试的跳过原因
+const failFastSkipReason = "aborted due to fail-fast"
+
+// testTypeIdempotency 幂等性测试类型：发送两次相同请求并比较响应体
+const testTypeIdempotency = "idempotency"
+
 // Executor 测试执行器
 type Executor struct {
-	client  *client.HTTPClient
-	config  *config.TestConfig
-	results map[string]*TestResult // 存储已执行的测试结果，用于依赖查询
-	mu      sync.RWMutex           // 保护 results 的并发访问
+	client       *client.HTTPClient
+	config       *config.TestConfig
+	results      map[string]*TestResult // 存储已执行的测试结果，用于依赖查询
+	variables    map[string]interface{} // 全局变量和通过 capture 捕获的命名变量
+	captured     map[string]interface{} // 通过 capture 捕获或 SetVariables 导入的变量，用于跨进程传递
+	failFast     bool                   // 出现首个失败后中止执行剩余测试
+	strictVars   bool                   // 引用无法解析的变量时判定测试失败，而不是发送原样的占位符
+	dryRun       bool                   // 只组装请求不发送
+	updateGolden bool                   // 用实际响应体重写 body_golden 文件
+	sequential   bool                   // 正在通过 Execute 顺序执行，此时才会在请求之间等待
+	sentRequest  bool                   // 本次执行中是否已经发送过请求，第一个请求之前不等待 think_time
+	thinkTime    time.Duration          // 本次执行中在请求之间等待的总时长
+	authToken    string                 // auth_flow 登录测试通过后提取的 token
+	headers      map[string]string      // 全局 headers 中包含变量的请求头，每次请求前替换
+	focused      map[string]bool        // 存在 only 测试时需要执行的测试（聚焦的测试及其依赖），为 nil 时执行所有测试
+	logger       logger.Logger          // 日志记录器，默认丢弃所有日志
+	random       *mathrand.Rand         // 配置了 rand_seed 时使用的确定性随机源，为 nil 时使用 crypto/rand
+	randomMu     sync.Mutex             // 保护 random 的并发访问
+	goldenMu     sync.Mutex             // 串行化 -update-golden 对 golden 文件的写入，多个测试可能引用同一文件
+	mu           sync.RWMutex           // 保护 results 和 variables 的并发访问
 }
 
 // NewExecutor 创建测试执行器
@@ -66,15 +175,85 @@ func NewExecutor(cfg *config.TestConfig) (*Executor, error) {
 		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
 	}
 
-	return &Executor{
-		client:  httpClient,
-		config:  cfg,
-		results: make(map[string]*TestResult),
-	}, nil
+	// 全局变量作为初始值，可被 capture 捕获的同名变量覆盖
+	variables := make(map[string]interface{}, len(cfg.Variables))
+	for name, value := range cfg.Variables {
+		variables[name] = value
+	}
+
+	executor := &Executor{
+		client:    httpClient,
+		config:    cfg,
+		results:   make(map[string]*TestResult),
+		variables: variables,
+		headers:   templatedHeaders(cfg),
+		logger:    logger.Nop(),
+	}
+	// 指定随机种子时 {{$random.*}} 每次运行生成相同的值，仅用于调试
+	if cfg.RandSeed != 0 {
+		executor.random = mathrand.New(mathrand.NewSource(cfg.RandSeed))
+	}
+	return executor, nil
+}
+
+// templatedHeaders 返回全局 headers 中包含 {{...}} 的请求头，其余请求头原样由 HTTP 客户端设置
+// 配置了全局 auth 时 Authorization 仍由 auth 生成，不参与替换
+func templatedHeaders(cfg *config.TestConfig) map[string]string {
+	headers := make(map[string]string)
+	for name, value := range cfg.Headers {
+		if !varPattern.MatchString(value) {
+			continue
+		}
+		if cfg.Auth != nil && strings.EqualFold(name, "Authorization") {
+			continue
+		}
+		headers[name] = value
+	}
+	return headers
+}
+
+// SetFailFast 设置是否在出现首个失败（不包括跳过）后中止执行剩余测试
+// 剩余测试会被标记为跳过，原因为 "aborted due to fail-fast"，teardown 仍会执行
+func (e *Executor) SetFailFast(enabled bool) {
+	e.failFast = enabled
+}
+
+// SetStrictVars 设置是否在请求中残留无法解析的变量（如依赖的测试未执行或字段不存在）时判定测试失败
+// 默认关闭，此时占位符原样发送；未设置的环境变量无论是否开启都会判定失败
+func (e *Executor) SetStrictVars(enabled bool) {
+	e.strictVars = enabled
+}
+
+// SetDryRun 设置是否只解析变量并组装请求而不发送
+// 组装的请求记录在 TestResult.Prepared 中，测试标记为跳过，原因为 "dry-run"
+// 依赖 dry-run 测试的测试照常组装，但引用依赖响应的变量无法解析
+func (e *Executor) SetDryRun(enabled bool) {
+	e.dryRun = enabled
+}
+
+// SetUpdateGolden 设置是否用实际响应体重写 body_golden 指定的文件
+// 开启后不再与文件比较响应体，其余断言（状态码、validators 等）都通过后才重写文件，每个测试只写一次
+func (e *Executor) SetUpdateGolden(enabled bool) {
+	e.updateGolden = enabled
+}
+
+// SetLogger 设置执行器和 HTTP 客户端使用的日志记录器，默认丢弃所有日志
+func (e *Executor) SetLogger(l logger.Logger) {
+	if l == nil {
+		l = logger.Nop()
+	}
+	e.logger = l
+	e.client.SetLogger(l)
 }
 
 // Execute 执行所有测试
 func (e *Executor) Execute() *TestReport {
+	return e.executeTests(e.config.APIs, focusedTests(e.config.APIs))
+}
+
+// executeTests 顺序执行指定的测试，focused 不为 nil 时只执行其中的测试，其余标记为跳过
+// 准备和清理接口总是执行
+func (e *Executor) executeTests(apis []config.APITest, focused map[string]bool) *TestReport {
 	startTime := time.Now()
 
 	report := &TestReport{
@@ -84,83 +263,45 @@ func (e *Executor) Execute() *TestReport {
 		BaseURL:   e.config.BaseURL,
 	}
 
-	// 按权重排序 APIs（权重高的在前）
-	sortedAPIs := e.sortAPIsByWeight()
+	// 按依赖层级和权重确定执行顺序，存在循环依赖时不执行任何测试
+	executionOrder, err := e.resolveExecutionOrder(apis)
+	if err != nil {
+		return e.failedReport(report, err)
+	}
+	e.focused = focused
 
-	// 按拓扑顺序执行（考虑依赖关系）
-	executionOrder := e.resolveExecutionOrder(sortedAPIs)
+	// 顺序执行时在请求之间等待 think_time
+	e.sequential, e.sentRequest, e.thinkTime = true, false, 0
+	defer func() { e.sequential = false }()
 
-	for _, apiTest := range executionOrder {
-		// 检查依赖是否已成功执行
-		if apiTest.DependsOn != "" {
-			depResult := e.getResult(apiTest.DependsOn)
-			if depResult == nil {
-				// 依赖接口未执行
-				result := TestResult{
-					Name:        apiTest.Name,
-					Description: apiTest.Description,
-					Version:     apiTest.Version,
-					Request:     apiTest.Request,
-					ExecutedAt:  time.Now(),
-					Passed:      false,
-					Skipped:     true,
-					SkipReason:  fmt.Sprintf("依赖接口 '%s' 未找到或未执行", apiTest.DependsOn),
-				}
-				report.Results = append(report.Results, result)
-				report.SkippedTests++
-				report.TotalTests++
-				e.storeResult(&result)
-				continue
-			}
-			if !depResult.Passed || depResult.Skipped {
-				// 依赖接口执行失败或被跳过，需要跟踪依赖链找到根本原因
-				rootCause := e.findRootCause(apiTest.DependsOn)
-				skipReason := fmt.Sprintf("依赖接口 '%s' %s", apiTest.DependsOn, e.getDependencyFailureReason(depResult))
-				if rootCause != "" && rootCause != apiTest.DependsOn {
-					skipReason = fmt.Sprintf("依赖接口 '%s' %s（根本原因：接口 '%s' 执行失败）",
-						apiTest.DependsOn, e.getDependencyFailureReason(depResult), rootCause)
-				}
+	// 执行准备接口，失败时跳过所有测试
+	report.SetupResults = e.runHooks(e.config.Setup)
+	failedSetup := firstFailedHook(report.SetupResults)
 
-				result := TestResult{
-					Name:        apiTest.Name,
-					Description: apiTest.Description,
-					Version:     apiTest.Version,
-					Request:     apiTest.Request,
-					ExecutedAt:  time.Now(),
-					Passed:      false,
-					Skipped:     true,
-					SkipReason:  skipReason,
-				}
-				report.Results = append(report.Results, result)
-				report.SkippedTests++
-				report.TotalTests++
-				e.storeResult(&result)
-				continue
-			}
+	for _, apiTest := range executionOrder {
+		if e.failFast && report.FailedTests > 0 {
+			report.addResult(e.abort(apiTest))
+			continue
 		}
-
-		// 替换请求中的变量
-		processedTest := e.replaceVariables(apiTest)
-
-		result := e.executeAPITest(processedTest)
-		e.storeResult(&result)
-		report.Results = append(report.Results, result)
-
-		if result.Passed {
-			report.PassedTests++
-		} else {
-			report.FailedTests++
+		for _, result := range e.runTest(apiTest, failedSetup) {
+			report.addResult(result)
 		}
-		report.TotalTests++
 	}
 
+	// 无论测试是否失败都执行清理接口
+	report.TeardownResults = e.runHooks(e.config.Teardown)
+	report.FailedHooks = countFailedHooks(report.SetupResults) + countFailedHooks(report.TeardownResults)
+
+	report.ThinkTime = e.thinkTime
 	report.EndTime = time.Now()
 	report.Duration = report.EndTime.Sub(startTime)
 
 	return report
 }
 
-// ExecuteConcurrent 并发执行所有测试
+// ExecuteConcurrent 按依赖层级并发执行所有测试
+// 同一层级的测试并发执行（最多 maxConcurrency 个），上一层级全部完成后才执行下一层级，
+// 依赖失败时的跳过逻辑与 Execute 一致
 func (e *Executor) ExecuteConcurrent(maxConcurrency int) *TestReport {
 	startTime := time.Now()
 
@@ -171,41 +312,611 @@ func (e *Executor) ExecuteConcurrent(maxConcurrency int) *TestReport {
 		BaseURL:   e.config.BaseURL,
 	}
 
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	semaphore := make(chan struct{}, maxConcurrency)
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
 
-	for _, apiTest := range e.config.APIs {
-		wg.Add(1)
-		go func(test config.APITest) {
-			defer wg.Done()
+	// 存在循环依赖时不执行任何测试
+	executionOrder, err := e.resolveExecutionOrder(e.config.APIs)
+	if err != nil {
+		return e.failedReport(report, err)
+	}
+	e.focused = focusedTests(e.config.APIs)
 
-			semaphore <- struct{}{}        // 获取信号量
-			defer func() { <-semaphore }() // 释放信号量
+	// 准备接口按顺序执行，完成后再并发执行测试
+	report.SetupResults = e.runHooks(e.config.Setup)
+	failedSetup := firstFailedHook(report.SetupResults)
 
-			result := e.executeAPITest(test)
+	semaphore := make(chan struct{}, maxConcurrency)
 
-			mu.Lock()
-			report.Results = append(report.Results, result)
-			if result.Passed {
-				report.PassedTests++
-			} else {
-				report.FailedTests++
+	// 每个测试的结果写入各自的位置，最终按与 Execute 相同的顺序汇总，保证报告顺序稳定
+	testResults := make([][]TestResult, len(executionOrder))
+
+	// fail-fast 模式下出现失败后，尚未开始的测试（包括同一层级中等待信号量的测试）都标记为中止
+	var aborted atomic.Bool
+	for _, wave := range executionWaves(executionOrder) {
+		if aborted.Load() {
+			for _, index := range wave {
+				testResults[index] = []TestResult{e.abort(executionOrder[index])}
 			}
-			report.TotalTests++
-			mu.Unlock()
-		}(apiTest)
+			continue
+		}
+
+		var wg sync.WaitGroup
+
+		for _, index := range wave {
+			// 按执行顺序获取信号量，并发数为 1 时与 Execute 的顺序一致
+			semaphore <- struct{}{}
+			wg.Add(1)
+			go func(index int) {
+				defer wg.Done()
+				defer func() { <-semaphore }() // 释放信号量
+
+				if aborted.Load() {
+					testResults[index] = []TestResult{e.abort(executionOrder[index])}
+					return
+				}
+				testResults[index] = e.runTest(executionOrder[index], failedSetup)
+				if e.failFast && hasFailure(testResults[index]) {
+					aborted.Store(true)
+				}
+			}(index)
+		}
+
+		wg.Wait()
+	}
+
+	for _, results := range testResults {
+		for _, result := range results {
+			report.addResult(result)
+		}
 	}
 
-	wg.Wait()
+	// 无论测试是否失败都执行清理接口
+	report.TeardownResults = e.runHooks(e.config.Teardown)
+	report.FailedHooks = countFailedHooks(report.SetupResults) + countFailedHooks(report.TeardownResults)
+
 	report.EndTime = time.Now()
 	report.Duration = report.EndTime.Sub(startTime)
 
 	return report
 }
 
-// executeAPITest 执行单个API测试
+// failedReport 在执行任何测试之前出错（如循环依赖）时结束报告并记录错误
+func (e *Executor) failedReport(report *TestReport, err error) *TestReport {
+	report.Error = err
+	report.EndTime = time.Now()
+	report.Duration = report.EndTime.Sub(report.StartTime)
+	return report
+}
+
+// executionWaves 将拓扑排序后的测试按依赖层级分组，返回每一层测试在 order 中的下标
+// 没有依赖（或依赖不在本次执行中）的测试位于第 0 层，其余测试位于所依赖测试的下一层
+func executionWaves(order []config.APITest) [][]int {
+	levels := make(map[string]int, len(order))
+	waves := make([][]int, 0)
+
+	for i, apiTest := range order {
+		level := 0
+		if depLevel, ok := levels[apiTest.DependsOn]; ok && apiTest.DependsOn != "" {
+			level = depLevel + 1
+		}
+		levels[apiTest.Name] = level
+
+		for len(waves) <= level {
+			waves = append(waves, nil)
+		}
+		waves[level] = append(waves[level], i)
+	}
+
+	return waves
+}
+
+// MergeReports 将多个配置文件的测试报告合并为一个报告
+// 每个结果的 ConfigFile 记录其所属报告的 ConfigFileName，用于在报告中按文件分组展示
+func MergeReports(name string, reports []*TestReport) *TestReport {
+	merged := &TestReport{
+		Results:        make([]TestResult, 0),
+		ConfigFileName: name,
+	}
+
+	baseURLs := make([]string, 0, len(reports))
+	versions := make([]string, 0, len(reports))
+	for _, report := range reports {
+		merged.TotalTests += report.TotalTests
+		merged.PassedTests += report.PassedTests
+		merged.FailedTests += report.FailedTests
+		merged.SkippedTests += report.SkippedTests
+		merged.AbortedTests += report.AbortedTests
+		merged.FailedHooks += report.FailedHooks
+		merged.ThinkTime += report.ThinkTime
+
+		merged.Results = append(merged.Results, withConfigFile(report.Results, report.ConfigFileName)...)
+		merged.SetupResults = append(merged.SetupResults, withConfigFile(report.SetupResults, report.ConfigFileName)...)
+		merged.TeardownResults = append(merged.TeardownResults, withConfigFile(report.TeardownResults, report.ConfigFileName)...)
+
+		if merged.StartTime.IsZero() || report.StartTime.Before(merged.StartTime) {
+			merged.StartTime = report.StartTime
+		}
+		if report.EndTime.After(merged.EndTime) {
+			merged.EndTime = report.EndTime
+		}
+		baseURLs = appendUnique(baseURLs, report.BaseURL)
+		versions = appendUnique(versions, report.Version)
+
+		if report.Iterations > merged.Iterations {
+			merged.Iterations = report.Iterations
+		}
+		for _, flaky := range report.FlakyTests {
+			flaky.ConfigFile = report.ConfigFileName
+			merged.FlakyTests = append(merged.FlakyTests, flaky)
+		}
+	}
+
+	merged.Duration = merged.EndTime.Sub(merged.StartTime)
+	merged.BaseURL = strings.Join(baseURLs, ", ")
+	merged.Version = strings.Join(versions, ", ")
+	return merged
+}
+
+// withConfigFile 复制结果并标记所属的配置文件
+func withConfigFile(results []TestResult, file string) []TestResult {
+	tagged := make([]TestResult, len(results))
+	for i, result := range results {
+		result.ConfigFile = file
+		tagged[i] = result
+	}
+	return tagged
+}
+
+// appendUnique 追加非空且未出现过的字符串
+func appendUnique(values []string, value string) []string {
+	if value == "" {
+		return values
+	}
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// abort 将测试标记为因 fail-fast 中止
+func (e *Executor) abort(apiTest config.APITest) TestResult {
+	result := e.newSkippedResult(apiTest, failFastSkipReason)
+	result.Aborted = true
+	e.storeResult(&result)
+	return result
+}
+
+// hasFailure 判断结果中是否有失败（不包括跳过）
+func hasFailure(results []TestResult) bool {
+	for _, result := range results {
+		if !result.Passed && !result.Skipped {
+			return true
+		}
+	}
+	return false
+}
+
+// Failed 判断本次执行是否失败：有测试失败，或者准备、清理接口执行失败
+// 准备接口失败时所有测试都被跳过，FailedTests 为 0，但执行同样应视为失败
+func (r *TestReport) Failed() bool {
+	return r.FailedTests > 0 || r.FailedHooks > 0
+}
+
+// addResult 将测试结果计入报告统计
+func (r *TestReport) addResult(result TestResult) {
+	r.Results = append(r.Results, result)
+	if result.Aborted {
+		r.AbortedTests++
+	}
+	if result.Skipped {
+		r.SkippedTests++
+	} else if result.Passed {
+		r.PassedTests++
+	} else {
+		r.FailedTests++
+	}
+	r.TotalTests++
+}
+
+// runTest 执行单个测试并保存结果，返回本测试产生的所有结果
+// 依次检查准备接口、聚焦状态、禁用状态、依赖和执行条件，配置了 dataset 时每行数据各执行一次
+func (e *Executor) runTest(apiTest config.APITest, failedSetup string) []TestResult {
+	skip := func(reason string) []TestResult {
+		e.logger.Debugf("skipping test '%s': %s", apiTest.Name, reason)
+		result := e.newSkippedResult(apiTest, reason)
+		e.storeResult(&result)
+		return []TestResult{result}
+	}
+
+	if failedSetup != "" {
+		return skip(setupFailedReason(failedSetup))
+	}
+
+	// 存在 only 测试时，未被聚焦的测试直接跳过
+	if e.focused != nil && !e.focused[apiTest.Name] {
+		return skip(notFocusedSkipReason)
+	}
+
+	// 被禁用的接口直接标记为跳过
+	if isDisabled(apiTest) {
+		return skip(disabledReason(apiTest))
+	}
+
+	// 检查依赖是否已成功执行
+	if reason := e.dependencySkipReason(apiTest); reason != "" {
+		return skip(reason)
+	}
+
+	// 检查执行条件
+	if conditionResult := e.checkCondition(apiTest); conditionResult != nil {
+		e.storeResult(conditionResult)
+		return []TestResult{*conditionResult}
+	}
+
+	apiTest, err := loadBodyFile(apiTest)
+	if err != nil {
+		result := e.newErrorResult(apiTest, err)
+		e.storeResult(&result)
+		return []TestResult{result}
+	}
+
+	// 配置了 dataset 时每行数据各执行一次
+	runs := expandDataset(apiTest)
+	results := make([]TestResult, 0, len(runs))
+	for _, run := range runs {
+		// 替换请求中的变量
+		processedTest := e.replaceVariablesWithRow(run.test, run.row)
+
+		e.waitThinkTime(run.test)
+		e.logger.Debugf("running test '%s'", run.test.Name)
+		result := e.executeAPITest(processedTest)
+		e.logger.Debugf("test '%s' finished in %s (passed: %t)", run.test.Name, result.Duration, result.Passed)
+		e.storeResult(&result)
+		if result.Passed {
+			e.captureVariables(run.test, &result)
+		}
+		results = append(results, result)
+	}
+	e.storeDatasetSummary(apiTest)
+
+	return results
+}
+
+// dependencySkipReason 检查依赖接口是否已成功执行，需要跳过时返回跳过原因
+func (e *Executor) dependencySkipReason(apiTest config.APITest) string {
+	if apiTest.DependsOn == "" {
+		return ""
+	}
+
+	depResult := e.getResult(apiTest.DependsOn)
+	if depResult == nil {
+		// 依赖接口未执行
+		return fmt.Sprintf("依赖接口 '%s' 未找到或未执行", apiTest.DependsOn)
+	}
+	if depResult.Passed && !depResult.Skipped {
+		return ""
+	}
+	// dry-run 模式下依赖接口没有真正执行，不阻止后续测试组装请求
+	if e.dryRun && depResult.SkipReason == dryRunSkipReason {
+		return ""
+	}
+
+	// 依赖接口执行失败或被跳过，需要跟踪依赖链找到根本原因
+	rootCause := e.findRootCause(apiTest.DependsOn)
+	skipReason := fmt.Sprintf("依赖接口 '%s' %s", apiTest.DependsOn, e.getDependencyFailureReason(depResult))
+	if rootCause != "" && rootCause != apiTest.DependsOn {
+		rootReason := "执行失败"
+		if rootResult := e.getResult(rootCause); rootResult != nil {
+			rootReason = e.getDependencyFailureReason(rootResult)
+		}
+		skipReason = fmt.Sprintf("依赖接口 '%s' %s（根本原因：接口 '%s' %s）",
+			apiTest.DependsOn, e.getDependencyFailureReason(depResult), rootCause, rootReason)
+	}
+	return skipReason
+}
+
+// newSkippedResult 创建一个被跳过的测试结果
+func (e *Executor) newSkippedResult(apiTest config.APITest, reason string) TestResult {
+	return TestResult{
+		Name:        apiTest.Name,
+		Description: apiTest.Description,
+		Version:     apiTest.Version,
+		Request:     apiTest.Request,
+		ExecutedAt:  time.Now(),
+		Passed:      false,
+		Skipped:     true,
+		SkipReason:  reason,
+	}
+}
+
+// newErrorResult 创建未发送请求就失败的测试结果
+func (e *Executor) newErrorResult(apiTest config.APITest, err error) TestResult {
+	return TestResult{
+		Name:        apiTest.Name,
+		Description: apiTest.Description,
+		Version:     apiTest.Version,
+		Request:     apiTest.Request,
+		ExecutedAt:  time.Now(),
+		Error:       err,
+	}
+}
+
+// loadBodyFile 读取 body_file 指定的 JSON 文件作为请求体，之后仍会进行变量替换和 body_schema 类型转换
+func loadBodyFile(apiTest config.APITest) (config.APITest, error) {
+	if apiTest.Request.BodyFile == "" {
+		return apiTest, nil
+	}
+
+	data, err := os.ReadFile(apiTest.Request.BodyFile)
+	if err != nil {
+		return apiTest, fmt.Errorf("failed to read body file: %w", err)
+	}
+	var body interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return apiTest, fmt.Errorf("failed to parse body file '%s': %w", apiTest.Request.BodyFile, err)
+	}
+	apiTest.Request.Body = body
+	return apiTest, nil
+}
+
+// datasetRun 数据驱动测试展开后的一次执行
+type datasetRun struct {
+	test config.APITest
+	row  map[string]interface{} // 本次执行的行数据，未配置 dataset 时为 nil
+}
+
+// datasetRowPrefix 引用数据行字段的变量前缀，例如 {{row.email}}
+const datasetRowPrefix = "row."
+
+// expandDataset 将配置了 dataset 的测试按行展开，每行的名称为 testName[i]
+// 未配置 dataset 时返回测试本身
+func expandDataset(apiTest config.APITest) []datasetRun {
+	if len(apiTest.Dataset) == 0 {
+		return []datasetRun{{test: apiTest}}
+	}
+
+	runs := make([]datasetRun, 0, len(apiTest.Dataset))
+	for i, row := range apiTest.Dataset {
+		test := apiTest
+		test.Name = fmt.Sprintf("%s[%d]", apiTest.Name, i)
+		runs = append(runs, datasetRun{test: test, row: row})
+	}
+	return runs
+}
+
+// storeDatasetSummary 以原测试名称保存数据驱动测试的汇总结果，所有行都通过才视为通过
+// 依赖该测试的接口据此判断是否继续执行，变量引用使用最后一行的结果
+func (e *Executor) storeDatasetSummary(apiTest config.APITest) {
+	if len(apiTest.Dataset) == 0 {
+		return
+	}
+
+	var summary TestResult
+	passed := true
+	for _, run := range expandDataset(apiTest) {
+		result := e.getResult(run.test.Name)
+		if result == nil {
+			return
+		}
+		summary = *result
+		passed = passed && result.Passed
+	}
+	summary.Name = apiTest.Name
+	summary.Passed = passed
+	e.storeResult(&summary)
+}
+
+// waitThinkTime 顺序执行时在发送请求之前等待，避免触发接口的限流
+// 测试设置了 delay 时等待 delay，否则等待全局的 think_time（第一个请求之前不等待），dry-run 时不等待
+func (e *Executor) waitThinkTime(apiTest config.APITest) {
+	if !e.sequential || e.dryRun {
+		return
+	}
+	wait := apiTest.Delay
+	if wait <= 0 && e.sentRequest {
+		wait = e.config.ThinkTime
+	}
+	e.sentRequest = true
+	if wait <= 0 {
+		return
+	}
+
+	e.logger.Debugf("waiting %s before test '%s'", wait, apiTest.Name)
+	time.Sleep(wait)
+	e.thinkTime += wait
+}
+
+// runHooks 按顺序执行 setup 或 teardown 接口
+// 结果会被存储，供后续请求通过 {{name.response...}} 引用，通过时同样支持 capture
+func (e *Executor) runHooks(hooks []config.APITest) []TestResult {
+	results := make([]TestResult, 0, len(hooks))
+	for _, hook := range hooks {
+		var result TestResult
+		if isDisabled(hook) {
+			result = e.newSkippedResult(hook, disabledReason(hook))
+		} else if loaded, err := loadBodyFile(hook); err != nil {
+			result = e.newErrorResult(hook, err)
+		} else {
+			result = e.executeAPITest(e.replaceVariables(loaded))
+		}
+		e.storeResult(&result)
+		if result.Passed {
+			e.captureVariables(hook, &result)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// firstFailedHook 返回第一个执行失败的 hook 名称，被禁用的 hook 不算失败
+func firstFailedHook(results []TestResult) string {
+	for _, result := range results {
+		if !result.Passed && !result.Skipped {
+			return result.Name
+		}
+	}
+	return ""
+}
+
+// countFailedHooks 统计执行失败的 hook 数量，被禁用的 hook 不算失败
+func countFailedHooks(results []TestResult) int {
+	count := 0
+	for _, result := range results {
+		if !result.Passed && !result.Skipped {
+			count++
+		}
+	}
+	return count
+}
+
+// setupFailedReason 准备接口失败导致测试被跳过的原因
+func setupFailedReason(name string) string {
+	return fmt.Sprintf("准备接口 '%s' 执行失败", name)
+}
+
+// executeAPITest 执行单个API测试，配置了 hosts_file 时对每个主机各执行一次
+// 结果中记录的请求隐藏了敏感的请求头（如 auth_flow 注入的 token）和认证信息，避免泄露到报告中
 func (e *Executor) executeAPITest(apiTest config.APITest) TestResult {
+	result := e.sendAPITest(apiTest)
+	if e.updateGolden {
+		e.writeGolden(apiTest, &result)
+	}
+	result.Request = e.client.RedactRequest(result.Request)
+	return result
+}
+
+// writeGolden 测试通过后用最终的响应体重写 body_golden 文件，写入失败时判定测试失败
+func (e *Executor) writeGolden(apiTest config.APITest, result *TestResult) {
+	path := apiTest.Response.BodyGolden
+	if path == "" || !result.Passed || result.Response == nil {
+		return
+	}
+
+	e.goldenMu.Lock()
+	defer e.goldenMu.Unlock()
+	if err := validator.UpdateGolden(path, result.Response); err != nil {
+		result.Passed = false
+		result.Error = err
+		return
+	}
+	e.logger.Infof("updated golden file %s", path)
+}
+
+// sendAPITest 发送单个测试的请求并验证响应
+func (e *Executor) sendAPITest(apiTest config.APITest) TestResult {
+	// 引用了未设置的环境变量（或严格模式下引用了无法解析的变量）时直接判定失败，避免发送包含占位符的请求
+	if err := e.checkUnresolvedReferences(apiTest.Request); err != nil {
+		return e.newErrorResult(apiTest, err)
+	}
+
+	if e.dryRun {
+		return e.prepareDryRun(apiTest)
+	}
+
+	if apiTest.HostsFile != "" {
+		return e.executeFanOut(apiTest)
+	}
+	return e.executeWithRetry(apiTest)
+}
+
+// prepareDryRun 组装请求但不发送，组装失败（如 body_schema 校验不通过）时判定测试失败
+func (e *Executor) prepareDryRun(apiTest config.APITest) TestResult {
+	prepared, err := e.client.Prepare(apiTest.Request)
+	if err != nil {
+		return e.newErrorResult(apiTest, err)
+	}
+	result := e.newSkippedResult(apiTest, dryRunSkipReason)
+	result.Prepared = prepared
+	return result
+}
+
+// executeFanOut 读取主机列表，将 {{host}} 替换到基础URL和路径中，对每个主机分别执行并汇总结果
+func (e *Executor) executeFanOut(apiTest config.APITest) TestResult {
+	result := TestResult{
+		Name:        apiTest.Name,
+		Description: apiTest.Description,
+		Version:     apiTest.Version,
+		Request:     apiTest.Request,
+		ExecutedAt:  time.Now(),
+	}
+
+	hosts, err := readHostsFile(apiTest.HostsFile)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	if len(hosts) == 0 {
+		result.Error = fmt.Errorf("hosts file '%s' contains no hosts", apiTest.HostsFile)
+		return result
+	}
+
+	baseURL := apiTest.Request.BaseURL
+	if baseURL == "" && e.config != nil {
+		baseURL = e.config.BaseURL
+	}
+
+	result.Passed = true
+	for _, host := range hosts {
+		hostTest := apiTest
+		hostTest.Request.BaseURL = strings.ReplaceAll(baseURL, hostPlaceholder, host)
+		hostTest.Request.Path = strings.ReplaceAll(apiTest.Request.Path, hostPlaceholder, host)
+
+		hostResult := e.executeWithRetry(hostTest)
+		result.HostResults = append(result.HostResults, HostResult{
+			Host:       host,
+			Passed:     hostResult.Passed,
+			StatusCode: hostResult.StatusCode,
+			Duration:   hostResult.Duration,
+			Validation: hostResult.Validation,
+			Error:      hostResult.Error,
+		})
+
+		result.Duration += hostResult.Duration
+		result.RetryCount += hostResult.RetryCount
+		result.Attempts = append(result.Attempts, hostResult.Attempts...)
+
+		// 保留最后一个响应，失败时保留第一个失败主机的响应，便于排查
+		if result.Passed {
+			result.Request = hostTest.Request
+			result.Response = hostResult.Response
+			result.StatusCode = hostResult.StatusCode
+			result.Validation = hostResult.Validation
+		}
+		if !hostResult.Passed {
+			result.Passed = false
+		}
+	}
+
+	return result
+}
+
+// hostPlaceholder 主机列表展开时使用的占位符
+const hostPlaceholder = "{{host}}"
+
+// readHostsFile 读取主机列表文件，忽略空行和 # 开头的注释行
+func readHostsFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hosts file: %w", err)
+	}
+
+	hosts := make([]string, 0)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		hosts = append(hosts, line)
+	}
+	return hosts, nil
+}
+
+// executeWithRetry 按重试策略执行单个API测试
+func (e *Executor) executeWithRetry(apiTest config.APITest) TestResult {
 	result := TestResult{
 		Name:        apiTest.Name,
 		Description: apiTest.Description,
@@ -216,7 +927,6 @@ func (e *Executor) executeAPITest(apiTest config.APITest) TestResult {
 	}
 
 	maxRetries := apiTest.RetryPolicy.MaxRetries
-	retryInterval := apiTest.RetryPolicy.Interval
 
 	// 默认不重试
 	if maxRetries == 0 {
@@ -229,8 +939,8 @@ func (e *Executor) executeAPITest(apiTest config.APITest) TestResult {
 	for attempt := 0; attempt < maxRetries; attempt++ {
 		if attempt > 0 {
 			result.RetryCount++
-			if retryInterval > 0 {
-				time.Sleep(retryInterval)
+			if delay := e.retryDelay(apiTest.RetryPolicy, attempt); delay > 0 {
+				time.Sleep(delay)
 			}
 		}
 
@@ -242,23 +952,41 @@ func (e *Executor) executeAPITest(apiTest config.APITest) TestResult {
 		result.Duration = duration
 
 		if err != nil {
+			result.Attempts = append(result.Attempts, 0)
 			lastErr = err
 			continue // 重试
 		}
+		result.Attempts = append(result.Attempts, resp.StatusCode)
 
+		// 使用客户端记录的耗时，不包括 rate_limit 限流等待的时间
+		result.Duration = resp.Duration
 		result.Response = resp
 		result.StatusCode = resp.StatusCode
 
-		// 验证响应
-		v := validator.NewValidator(apiTest.Response)
+		// 验证响应，-update-golden 时 golden 文件在测试通过后重写，不参与比较
+		expectation := e.resolveExpectation(apiTest.Response)
+		if e.updateGolden {
+			expectation.BodyGolden = ""
+		}
+		v := validator.NewValidator(expectation)
 		validationResult := v.Validate(resp)
 		result.Validation = validationResult
 
+		// 幂等性测试：首次响应验证通过后再次发送相同请求进行比较
+		if validationResult.Passed && apiTest.Type == testTypeIdempotency {
+			e.checkIdempotency(apiTest, resp, validationResult)
+		}
+
 		if validationResult.Passed {
 			result.Passed = true
 			return result
 		}
 
+		// 配置了 retry_on 时，只有指定的状态码才会重试
+		if !e.shouldRetryStatus(apiTest.RetryPolicy, resp.StatusCode) {
+			break
+		}
+
 		// 如果验证失败且有重试次数，继续重试
 		if attempt < maxRetries-1 {
 			continue
@@ -274,95 +1002,203 @@ func (e *Executor) executeAPITest(apiTest config.APITest) TestResult {
 	return result
 }
 
-// ExecuteByName 按名称执行指定的测试
-func (e *Executor) ExecuteByName(name string) (*TestResult, error) {
-	for _, apiTest := range e.config.APIs {
-		if apiTest.Name == name {
-			result := e.executeAPITest(apiTest)
-			return &result, nil
+// retryDelay 计算第 retry 次重试前的等待时间（retry 从 1 开始）
+// 支持指数退避、最大间隔限制以及 ±20% 的随机抖动
+func (e *Executor) retryDelay(policy config.RetryPolicy, retry int) time.Duration {
+	delay := float64(policy.Interval)
+	if policy.BackoffMultiplier > 0 && policy.BackoffMultiplier != 1 {
+		delay *= math.Pow(policy.BackoffMultiplier, float64(retry-1))
+	}
+
+	if policy.MaxInterval > 0 && delay > float64(policy.MaxInterval) {
+		delay = float64(policy.MaxInterval)
+	}
+
+	if policy.Jitter && delay > 0 {
+		// 在 [0.8, 1.2) 范围内随机缩放
+		delay *= 0.8 + mathrand.Float64()*0.4
+	}
+
+	return time.Duration(delay)
+}
+
+// shouldRetryStatus 判断验证失败时该状态码是否允许重试
+func (e *Executor) shouldRetryStatus(policy config.RetryPolicy, statusCode int) bool {
+	if len(policy.RetryOn) == 0 {
+		return true
+	}
+	for _, code := range policy.RetryOn {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIdempotency 再次发送相同请求，比较两次响应体是否一致
+func (e *Executor) checkIdempotency(apiTest config.APITest, first *client.Response, validationResult *validator.ValidationResult) {
+	second, err := e.client.Do(apiTest.Request)
+	if err != nil {
+		validationResult.Passed = false
+		validationResult.Errors = append(validationResult.Errors, validator.ValidationError{
+			Field:   "Idempotency",
+			Message: fmt.Sprintf("failed to send second request: %v", err),
+		})
+		return
+	}
+
+	diffs := validator.DiffValues(responseBodyValue(first), responseBodyValue(second), apiTest.Response.IgnoreFields)
+	for _, diff := range diffs {
+		field := "Idempotency.Body"
+		if diff.Path != "" {
+			field = "Idempotency." + diff.Path
+		}
+		validationResult.Passed = false
+		validationResult.Errors = append(validationResult.Errors, validator.ValidationError{
+			Field:    field,
+			Expected: diff.Expected,
+			Actual:   diff.Actual,
+			Message:  fmt.Sprintf("Response differs between requests: first %v, second %v", diff.Expected, diff.Actual),
+		})
+	}
+}
+
+// responseBodyValue 获取用于比较的响应体（优先使用解析后的 JSON）
+func responseBodyValue(resp *client.Response) interface{} {
+	if resp.BodyJSON != nil {
+		return resp.BodyJSON
+	}
+	return string(resp.Body)
+}
+
+// resolveExpectation 合并全局配置到响应预期中（单个测试的配置优先）
+func (e *Executor) resolveExpectation(expectation config.ResponseExpectation) config.ResponseExpectation {
+	if expectation.SuccessField == nil && e.config != nil {
+		expectation.SuccessField = e.config.SuccessField
+	}
+	return expectation
+}
+
+// ExecuteByName 按名称执行指定的测试
+// 先按依赖链执行该测试依赖的所有测试，报告中包含这些依赖测试的结果，准备和清理接口照常执行
+func (e *Executor) ExecuteByName(name string) (*TestReport, error) {
+	chain := e.dependencyChain(name)
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("test '%s' not found", name)
+	}
+	return e.executeTests(chain, nil), nil
+}
+
+// dependencyChain 返回指定测试及其依赖链上的所有测试，依赖的测试不在 apis 中（如准备接口）时停止
+// 测试不存在时返回空列表
+func (e *Executor) dependencyChain(name string) []config.APITest {
+	byName := make(map[string]config.APITest, len(e.config.APIs))
+	for _, api := range e.config.APIs {
+		if _, ok := byName[api.Name]; !ok {
+			byName[api.Name] = api
+		}
+	}
+
+	chain := make([]config.APITest, 0)
+	visited := make(map[string]bool)
+	for current := name; current != "" && !visited[current]; {
+		api, ok := byName[current]
+		if !ok {
+			break
 		}
+		visited[current] = true
+		chain = append(chain, api)
+		current = api.DependsOn
 	}
-	return nil, fmt.Errorf("test '%s' not found", name)
+	return chain
 }
 
-// GetTestNames 获取所有测试名称
-func (e *Executor) GetTestNames() []string {
+// GetTestNames 获取所有测试名称，includeDisabled 为 false 时不包含被禁用的测试
+func (e *Executor) GetTestNames(includeDisabled bool) []string {
 	names := make([]string, 0, len(e.config.APIs))
 	for _, apiTest := range e.config.APIs {
+		if isDisabled(apiTest) && !includeDisabled {
+			continue
+		}
 		names = append(names, apiTest.Name)
 	}
 	return names
 }
 
-// sortAPIsByWeight 按权重排序 APIs（权重高的在前）
-func (e *Executor) sortAPIsByWeight() []config.APITest {
-	sorted := make([]config.APITest, len(e.config.APIs))
-	copy(sorted, e.config.APIs)
-
-	sort.SliceStable(sorted, func(i, j int) bool {
-		// 权重高的排在前面（降序）
-		if sorted[i].Weight != sorted[j].Weight {
-			return sorted[i].Weight > sorted[j].Weight
-		}
-		// 权重相同时保持原有顺序
-		return false
-	})
-
-	return sorted
-}
-
-// resolveExecutionOrder 解析执行顺序（考虑依赖关系）
-// 使用拓扑排序确保依赖的接口先执行
-func (e *Executor) resolveExecutionOrder(apis []config.APITest) []config.APITest {
+// resolveExecutionOrder 解析执行顺序
+// 先保证依赖关系：测试按依赖层级排列（没有依赖或依赖的测试不存在时为第 0 层，否则为所依赖测试的层级加 1），
+// 同一层级内按权重从高到低排列，权重相同时保持配置顺序。
+// 顺序执行时的顺序与并发执行时的分批顺序一致，存在循环依赖时返回列出循环中所有测试的错误
+func (e *Executor) resolveExecutionOrder(apis []config.APITest) ([]config.APITest, error) {
 	// 构建名称到索引的映射
 	nameToIndex := make(map[string]int)
 	for i, api := range apis {
 		nameToIndex[api.Name] = i
 	}
 
-	// 拓扑排序
-	visited := make(map[int]bool)
+	// 计算每个测试的依赖层级，path 记录当前正在访问的依赖链，用于报告循环
+	levels := make(map[int]int)
 	visiting := make(map[int]bool)
-	result := make([]config.APITest, 0, len(apis))
+	path := make([]string, 0)
 
-	var visit func(int) bool
-	visit = func(idx int) bool {
-		if visited[idx] {
-			return true
+	var visit func(int) (int, error)
+	visit = func(idx int) (int, error) {
+		if level, ok := levels[idx]; ok {
+			return level, nil
 		}
+		api := apis[idx]
 		if visiting[idx] {
-			// 检测到循环依赖
-			return false
+			// 检测到循环依赖，从首次访问该测试的位置截取循环
+			for i, name := range path {
+				if name == api.Name {
+					cycle := append(append([]string(nil), path[i:]...), api.Name)
+					return 0, fmt.Errorf("circular dependency: %s", strings.Join(cycle, " -> "))
+				}
+			}
 		}
 
 		visiting[idx] = true
+		path = append(path, api.Name)
 
-		// 先访问依赖
-		api := apis[idx]
+		level := 0
 		if api.DependsOn != "" {
 			if depIdx, exists := nameToIndex[api.DependsOn]; exists {
-				if !visit(depIdx) {
-					return false
+				depLevel, err := visit(depIdx)
+				if err != nil {
+					return 0, err
 				}
+				level = depLevel + 1
 			}
 		}
 
+		path = path[:len(path)-1]
 		visiting[idx] = false
-		visited[idx] = true
-		result = append(result, api)
-		return true
+		levels[idx] = level
+		return level, nil
 	}
 
+	indexes := make([]int, len(apis))
 	for i := range apis {
-		if !visited[i] {
-			if !visit(i) {
-				// 检测到循环依赖，回退到原顺序
-				return apis
-			}
+		if _, err := visit(i); err != nil {
+			return nil, err
 		}
+		indexes[i] = i
 	}
 
-	return result
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, b := indexes[i], indexes[j]
+		if levels[a] != levels[b] {
+			return levels[a] < levels[b]
+		}
+		// 同一层级内权重高的排在前面，权重相同时保持原有顺序
+		return apis[a].Weight > apis[b].Weight
+	})
+
+	result := make([]config.APITest, 0, len(apis))
+	for _, idx := range indexes {
+		result = append(result, apis[idx])
+	}
+	return result, nil
 }
 
 // storeResult 存储测试结果
@@ -401,6 +1237,11 @@ func (e *Executor) findRootCause(testName string) string {
 			return current
 		}
 
+		// 被禁用的接口本身就是根本原因
+		if isDisabledResult(result) {
+			return current
+		}
+
 		// 如果这个接口是被跳过的，继续查找它的依赖
 		if result.Skipped {
 			// 尝试从配置中找到它依赖的接口
@@ -428,97 +1269,34 @@ func (e *Executor) findDependsOn(testName string) string {
 
 // getDependencyFailureReason 获取依赖失败的原因描述
 func (e *Executor) getDependencyFailureReason(result *TestResult) string {
+	if isDisabledResult(result) {
+		return "已被禁用"
+	}
 	if result.Skipped {
 		return "被跳过"
 	}
 	return "执行失败"
 }
 
+// varPattern 匹配 {{name.field.path}} 或 {{$random.type}} 形式的变量引用
+var varPattern = regexp.MustCompile(`\{\{([^}]+)\}\}`)
+
 // replaceVariables 替换请求中的变量
 // 支持格式：
+//   - {{变量名}}，引用通过 capture 捕获的变量，例如 {{userId}}，优先级最高
 //   - {{接口名称.request.字段路径}}，引用请求数据，例如 {{创建部门.request.name}}
 //   - {{接口名称.response.字段路径}}，引用响应数据，例如 {{创建部门.response.data.id}}
 //   - {{接口名称.字段路径}}，默认引用响应数据（向后兼容），例如 {{创建部门.data.id}}
 //   - {{$random.type}}，例如 {{$random.name}}, {{$random.string.10}}
+//   - {{$env.NAME}}，引用环境变量，未设置时测试失败
+//
+// 任意变量都可以追加 "| default: 值"，在变量缺失时使用默认值，例如 {{创建部门.response.data.id | default: 0}}
 func (e *Executor) replaceVariables(apiTest config.APITest) config.APITest {
-	// 正则表达式匹配 {{name.field.path}} 或 {{$random.type}}
-	varPattern := regexp.MustCompile(`\{\{([^}]+)\}\}`)
-
-	// 辅助函数：提取单个变量的值（保持原始类型）
-	extractValue := func(varPath string) (interface{}, bool) {
-		varPath = strings.TrimSpace(varPath)
-
-		// 检查是否是随机值占位符
-		if strings.HasPrefix(varPath, "$random") {
-			randomValue := e.generateRandomValue(varPath)
-			if randomValue != "" {
-				return randomValue, true
-			}
-			return nil, false
-		}
-
-		// 处理接口返回值引用
-		parts := strings.SplitN(varPath, ".", 2)
-		if len(parts) < 1 {
-			return nil, false
-		}
-
-		testName := parts[0]
-		var fieldPath string
-		if len(parts) == 2 {
-			fieldPath = parts[1]
-		}
-
-		// 获取依赖接口的结果
-		depResult := e.getResult(testName)
-		if depResult == nil {
-			return nil, false
-		}
-
-		// 判断是引用请求数据还是响应数据
-		var sourceData interface{}
-		if strings.HasPrefix(fieldPath, "request.") {
-			// 引用请求数据
-			fieldPath = strings.TrimPrefix(fieldPath, "request.")
-			sourceData = depResult.Request.Body
-		} else if strings.HasPrefix(fieldPath, "response.") {
-			// 引用响应数据
-			fieldPath = strings.TrimPrefix(fieldPath, "response.")
-			if depResult.Response == nil {
-				return nil, false
-			}
-			sourceData = depResult.Response.BodyJSON
-		} else {
-			// 默认引用响应数据（向后兼容）
-			if depResult.Response == nil {
-				return nil, false
-			}
-			sourceData = depResult.Response.BodyJSON
-		}
-
-		// 从数据源中提取字段值
-		var value interface{}
-		if fieldPath == "" {
-			value = sourceData
-		} else {
-			value = e.extractFieldValue(sourceData, fieldPath)
-		}
-
-		return value, value != nil
-	}
-
-	// 辅助函数：替换字符串中的变量（返回字符串）
-	replaceInString := func(s string) string {
-		return varPattern.ReplaceAllStringFunc(s, func(match string) string {
-			varPath := strings.Trim(match, "{}")
-			value, ok := extractValue(varPath)
-			if ok && value != nil {
-				return fmt.Sprintf("%v", value)
-			}
-			return match // 保持原样
-		})
-	}
+	return e.replaceVariablesWithRow(apiTest, nil)
+}
 
+// replaceVariablesWithRow 替换请求中的变量，并通过 {{row.字段}} 引用数据驱动测试的当前行
+func (e *Executor) replaceVariablesWithRow(apiTest config.APITest, row map[string]interface{}) config.APITest {
 	// 辅助函数：递归替换 interface{} 中的变量
 	var replaceInInterface func(interface{}) interface{}
 	replaceInInterface = func(v interface{}) interface{} {
@@ -531,7 +1309,7 @@ func (e *Executor) replaceVariables(apiTest config.APITest) config.APITest {
 			// 如果整个字符串就是一个单独的变量，返回原始类型的值
 			if len(matches) == 1 && trimmed == matches[0][0] {
 				varPath := matches[0][1]
-				if value, ok := extractValue(varPath); ok {
+				if value, ok := e.resolveVariable(varPath, row); ok {
 					// JSON 解析数字默认为 float64，如果是整数则转换为 int64
 					if f, ok := value.(float64); ok {
 						if f == float64(int64(f)) {
@@ -543,7 +1321,7 @@ func (e *Executor) replaceVariables(apiTest config.APITest) config.APITest {
 			}
 
 			// 否则作为字符串处理（可能包含多个变量或混合文本）
-			return replaceInString(val)
+			return e.replaceInString(val, row)
 
 		case map[string]interface{}:
 			result := make(map[string]interface{})
@@ -565,13 +1343,31 @@ func (e *Executor) replaceVariables(apiTest config.APITest) config.APITest {
 	// 创建副本以避免修改原始配置
 	processedTest := apiTest
 
+	// 替换 BaseURL，保留 hosts_file 使用的 {{host}} 占位符
+	if apiTest.Request.BaseURL != "" {
+		parts := strings.Split(apiTest.Request.BaseURL, hostPlaceholder)
+		for i, part := range parts {
+			parts[i] = e.replaceInString(part, row)
+		}
+		processedTest.Request.BaseURL = strings.Join(parts, hostPlaceholder)
+	}
+
 	// 替换 Path
-	processedTest.Request.Path = replaceInString(apiTest.Request.Path)
+	processedTest.Request.Path = e.replaceInString(apiTest.Request.Path, row)
 
 	// 替换 Query 参数
 	if apiTest.Request.Query != nil {
 		processedTest.Request.Query = replaceInInterface(apiTest.Request.Query).(map[string]interface{})
 	}
+	if apiTest.Request.QueryParams != nil {
+		processedTest.Request.QueryParams = make([]config.QueryParam, len(apiTest.Request.QueryParams))
+		for i, param := range apiTest.Request.QueryParams {
+			processedTest.Request.QueryParams[i] = config.QueryParam{
+				Name:  e.replaceInString(param.Name, row),
+				Value: replaceInInterface(param.Value),
+			}
+		}
+	}
 
 	// 替换 Body
 	if apiTest.Request.Body != nil {
@@ -583,18 +1379,395 @@ func (e *Executor) replaceVariables(apiTest config.APITest) config.APITest {
 		}
 	}
 
+	// 替换 GraphQL 查询和变量
+	if apiTest.Request.GraphQL != nil {
+		processedGraphQL := *apiTest.Request.GraphQL
+		processedGraphQL.Query = e.replaceInString(processedGraphQL.Query, row)
+		if processedGraphQL.Variables != nil {
+			processedGraphQL.Variables = replaceInInterface(processedGraphQL.Variables).(map[string]interface{})
+		}
+		processedTest.Request.GraphQL = &processedGraphQL
+	}
+
+	// 替换认证信息
+	if apiTest.Request.Auth != nil {
+		processedAuth := *apiTest.Request.Auth
+		processedAuth.Username = e.replaceInString(processedAuth.Username, row)
+		processedAuth.Password = e.replaceInString(processedAuth.Password, row)
+		processedAuth.Token = e.replaceInString(processedAuth.Token, row)
+		processedTest.Request.Auth = &processedAuth
+	}
+
 	// 替换 Headers
 	if apiTest.Request.Headers != nil {
 		processedHeaders := make(map[string]string)
 		for k, v := range apiTest.Request.Headers {
-			processedHeaders[k] = replaceInString(v)
+			processedHeaders[k] = e.replaceInString(v, row)
 		}
 		processedTest.Request.Headers = processedHeaders
 	}
 
+	// 添加 auth_flow 登录后获得的 token，先于全局 headers 添加，因此优先于全局 headers 中的同名请求头
+	processedTest.Request.Headers = e.applyAuthFlow(apiTest, processedTest.Request.Headers)
+
+	// 替换全局 headers 中的变量，捕获的变量在运行过程中会变化，因此每次请求前重新替换
+	processedTest.Request.Headers = e.applyGlobalHeaders(processedTest.Request.Headers, row)
+
 	return processedTest
 }
 
+// applyGlobalHeaders 将替换变量后的全局请求头合并到请求头中，返回新的请求头
+// 单个测试设置的同名请求头和 auth_flow 添加的请求头优先
+func (e *Executor) applyGlobalHeaders(headers map[string]string, row map[string]interface{}) map[string]string {
+	if len(e.headers) == 0 {
+		return headers
+	}
+
+	merged := make(map[string]string, len(headers)+len(e.headers))
+	for name, value := range e.headers {
+		if !hasHeader(headers, name) {
+			merged[name] = e.replaceInString(value, row)
+		}
+	}
+	for name, value := range headers {
+		merged[name] = value
+	}
+	return merged
+}
+
+// hasHeader 判断请求头中是否包含指定名称（不区分大小写）
+func hasHeader(headers map[string]string, name string) bool {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultAuthFlowHeader auth_flow 未指定 header 时携带 token 的请求头
+const defaultAuthFlowHeader = "Authorization"
+
+// applyAuthFlow 为登录测试以外的请求添加携带 token 的请求头，返回新的请求头
+// 尚未获得 token、测试自己设置了该请求头或通过 auth 覆盖了 Authorization 时保持不变
+func (e *Executor) applyAuthFlow(apiTest config.APITest, headers map[string]string) map[string]string {
+	e.mu.RLock()
+	token := e.authToken
+	e.mu.RUnlock()
+	// 只有配置了 auth_flow 且登录测试通过后才有 token
+	if token == "" {
+		return headers
+	}
+	flow := e.config.AuthFlow
+	if apiTest.Name == flow.Login {
+		return headers
+	}
+
+	header := flow.Header
+	if header == "" {
+		header = defaultAuthFlowHeader
+	}
+	if hasHeader(headers, header) || (apiTest.Request.Auth != nil && strings.EqualFold(header, "Authorization")) {
+		return headers
+	}
+
+	withToken := make(map[string]string, len(headers)+1)
+	for name, value := range headers {
+		withToken[name] = value
+	}
+	withToken[header] = flow.Prefix + token
+	return withToken
+}
+
+// captureAuthToken 登录测试通过后按 auth_flow.token_path 提取 token
+func (e *Executor) captureAuthToken(apiTest config.APITest, result *TestResult) {
+	flow := e.config.AuthFlow
+	if flow == nil || apiTest.Name != flow.Login {
+		return
+	}
+	value := e.extractResultValue(result, flow.TokenPath)
+	if value == nil {
+		e.logger.Warnf("auth_flow: token path '%s' not found in response of test '%s'", flow.TokenPath, apiTest.Name)
+		return
+	}
+
+	e.mu.Lock()
+	e.authToken = fmt.Sprintf("%v", value)
+	e.mu.Unlock()
+}
+
+// lookupVariable 查找单个变量的值（保持原始类型），row 为数据驱动测试的当前行
+func (e *Executor) lookupVariable(varPath string, row map[string]interface{}) (interface{}, bool) {
+	varPath = strings.TrimSpace(varPath)
+
+	// 数据驱动测试的行数据
+	if row != nil && strings.HasPrefix(varPath, datasetRowPrefix) {
+		value := e.extractFieldValue(row, strings.TrimPrefix(varPath, datasetRowPrefix))
+		return value, value != nil
+	}
+
+	// 捕获的变量优先
+	if value, ok := e.getVariable(varPath); ok {
+		return value, true
+	}
+
+	// 检查是否是环境变量占位符，已设置的空字符串也视为有效值
+	if strings.HasPrefix(varPath, envVarPrefix) {
+		return os.LookupEnv(strings.TrimPrefix(varPath, envVarPrefix))
+	}
+
+	// 检查是否是随机值占位符
+	if strings.HasPrefix(varPath, "$random") {
+		randomValue := e.generateRandomValue(varPath)
+		if randomValue != "" {
+			return typedRandomValue(varPath, randomValue), true
+		}
+		return nil, false
+	}
+
+	// 处理接口返回值引用
+	parts := strings.SplitN(varPath, ".", 2)
+	if len(parts) < 1 {
+		return nil, false
+	}
+
+	testName := parts[0]
+	var fieldPath string
+	if len(parts) == 2 {
+		fieldPath = parts[1]
+	}
+
+	// 获取依赖接口的结果
+	depResult := e.getResult(testName)
+	if depResult == nil {
+		return nil, false
+	}
+
+	value := e.extractResultValue(depResult, fieldPath)
+	return value, value != nil
+}
+
+// extractResultValue 从测试结果中提取字段值
+// 以 request. 开头时引用请求数据，以 response. 开头或不带前缀时引用响应数据
+func (e *Executor) extractResultValue(result *TestResult, fieldPath string) interface{} {
+	// 判断是引用请求数据还是响应数据
+	var sourceData interface{}
+	if strings.HasPrefix(fieldPath, "request.") {
+		// 引用请求数据
+		fieldPath = strings.TrimPrefix(fieldPath, "request.")
+		sourceData = result.Request.Body
+	} else {
+		// 引用响应数据，不带前缀时默认引用响应数据（向后兼容）
+		fieldPath = strings.TrimPrefix(fieldPath, "response.")
+		if result.Response == nil {
+			return nil
+		}
+		sourceData = result.Response.BodyJSON
+	}
+
+	// 从数据源中提取字段值
+	if fieldPath == "" {
+		return sourceData
+	}
+	return e.extractFieldValue(sourceData, fieldPath)
+}
+
+// envVarPrefix 环境变量占位符前缀，例如 {{$env.API_TOKEN}}
+const envVarPrefix = "$env."
+
+// checkUnresolvedReferences 检查请求中是否残留未能解析的变量占位符
+// 环境变量未设置时总是返回错误；启用严格模式时任何未解析的变量都返回错误，{{host}} 除外
+func (e *Executor) checkUnresolvedReferences(request config.RequestConfig) error {
+	unresolved := unresolvedReferences(request)
+	for _, name := range unresolved {
+		if strings.HasPrefix(name, envVarPrefix) {
+			return fmt.Errorf("environment variable '%s' is not set", strings.TrimPrefix(name, envVarPrefix))
+		}
+	}
+	if !e.strictVars || len(unresolved) == 0 {
+		return nil
+	}
+	if len(unresolved) == 1 {
+		return fmt.Errorf("unresolved variable '%s'", unresolved[0])
+	}
+	return fmt.Errorf("unresolved variables: %s", strings.Join(unresolved, ", "))
+}
+
+// unresolvedReferences 返回请求中残留的变量占位符名称（去重并排序），主机占位符 {{host}} 不计入
+func unresolvedReferences(request config.RequestConfig) []string {
+	found := make(map[string]bool)
+	var collect func(interface{})
+	collect = func(v interface{}) {
+		switch val := v.(type) {
+		case string:
+			for _, match := range varPattern.FindAllStringSubmatch(val, -1) {
+				if match[0] != hostPlaceholder {
+					found[strings.TrimSpace(match[1])] = true
+				}
+			}
+		case map[string]interface{}:
+			for _, item := range val {
+				collect(item)
+			}
+		case []interface{}:
+			for _, item := range val {
+				collect(item)
+			}
+		}
+	}
+
+	values := []interface{}{request.BaseURL, request.Path, request.Query, request.Body}
+	if request.Auth != nil {
+		values = append(values, request.Auth.Username, request.Auth.Password, request.Auth.Token)
+	}
+	if request.GraphQL != nil {
+		values = append(values, request.GraphQL.Query, request.GraphQL.Variables)
+	}
+	for _, value := range request.Headers {
+		values = append(values, value)
+	}
+	for _, param := range request.QueryParams {
+		values = append(values, param.Name, param.Value)
+	}
+	for _, value := range values {
+		collect(value)
+	}
+
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveVariable 提取单个变量的值，变量缺失时使用 "| default: 值" 指定的默认值
+func (e *Executor) resolveVariable(varPath string, row map[string]interface{}) (interface{}, bool) {
+	expr, defaultValue, hasDefault := e.parseDefaultExpr(varPath)
+	if value, ok := e.lookupVariable(expr, row); ok {
+		return value, true
+	}
+	if hasDefault {
+		return defaultValue, true
+	}
+	return nil, false
+}
+
+// replaceInString 替换字符串中的变量（返回字符串），无法解析的变量保持原样
+func (e *Executor) replaceInString(s string, row map[string]interface{}) string {
+	return varPattern.ReplaceAllStringFunc(s, func(match string) string {
+		varPath := strings.Trim(match, "{}")
+		value, ok := e.resolveVariable(varPath, row)
+		if ok && value != nil {
+			return fmt.Sprintf("%v", value)
+		}
+		return match // 保持原样
+	})
+}
+
+// captureVariables 测试通过后按 capture 配置提取命名变量
+// 捕获值为字段路径时从本测试的结果中提取（支持 request./response. 前缀），
+// 包含 {{...}} 时作为模板解析，整个值为单个变量引用时保持原始类型；auth_flow 的登录测试同时提取 token
+func (e *Executor) captureVariables(apiTest config.APITest, result *TestResult) {
+	e.captureAuthToken(apiTest, result)
+	for name, expr := range apiTest.Capture {
+		var value interface{}
+		trimmed := strings.TrimSpace(expr)
+		if matches := varPattern.FindAllStringSubmatch(trimmed, -1); len(matches) > 0 {
+			if len(matches) == 1 && trimmed == matches[0][0] {
+				value, _ = e.resolveVariable(matches[0][1], nil)
+			} else {
+				value = e.replaceInString(expr, nil)
+			}
+		} else {
+			value = e.extractResultValue(result, trimmed)
+		}
+
+		if value != nil {
+			e.setVariable(name, value)
+		}
+	}
+}
+
+// setVariable 保存捕获的变量
+func (e *Executor) setVariable(name string, value interface{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.variables == nil {
+		e.variables = make(map[string]interface{})
+	}
+	e.variables[name] = value
+	if e.captured == nil {
+		e.captured = make(map[string]interface{})
+	}
+	e.captured[name] = value
+}
+
+// SetVariables 导入变量（如上一次运行保存的捕获变量），覆盖配置中的同名全局变量
+// 导入的变量与捕获的变量一样会包含在 CapturedVariables 的结果中
+func (e *Executor) SetVariables(variables map[string]interface{}) {
+	for name, value := range variables {
+		e.setVariable(name, value)
+	}
+}
+
+// CapturedVariables 返回通过 capture 捕获和通过 SetVariables 导入的变量副本，不包括配置中的全局变量
+func (e *Executor) CapturedVariables() map[string]interface{} {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	variables := make(map[string]interface{}, len(e.captured))
+	for name, value := range e.captured {
+		variables[name] = value
+	}
+	return variables
+}
+
+// getVariable 获取捕获的变量
+func (e *Executor) getVariable(name string) (interface{}, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	value, ok := e.variables[name]
+	return value, ok
+}
+
+// parseDefaultExpr 解析变量表达式中的默认值部分
+// 例如 "创建部门.response.data.id | default: 0" 返回 ("创建部门.response.data.id", 0, true)
+func (e *Executor) parseDefaultExpr(varPath string) (string, interface{}, bool) {
+	idx := strings.Index(varPath, "|")
+	if idx < 0 {
+		return strings.TrimSpace(varPath), nil, false
+	}
+
+	expr := strings.TrimSpace(varPath[:idx])
+	filter := strings.TrimSpace(varPath[idx+1:])
+	if !strings.HasPrefix(filter, "default:") {
+		return expr, nil, false
+	}
+
+	literal := strings.TrimSpace(strings.TrimPrefix(filter, "default:"))
+	return expr, e.parseLiteral(literal), true
+}
+
+// parseLiteral 将默认值字面量解析为对应类型（整数、浮点数、布尔值或字符串）
+func (e *Executor) parseLiteral(literal string) interface{} {
+	if len(literal) >= 2 {
+		if (literal[0] == '"' && literal[len(literal)-1] == '"') ||
+			(literal[0] == '\'' && literal[len(literal)-1] == '\'') {
+			return literal[1 : len(literal)-1]
+		}
+	}
+	if i, err := strconv.ParseInt(literal, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(literal, 64); err == nil {
+		return f
+	}
+	if b, err := strconv.ParseBool(literal); err == nil {
+		return b
+	}
+	return literal
+}
+
 // extractFieldValue 从响应体中提取字段值
 // 支持点号分隔的路径，例如 "data.user.id"
 // 支持数组索引，例如 "data[0].id" 或 "items[0].children[1].name"
@@ -899,6 +2072,11 @@ func (e *Executor) convertToSchemaType(value interface{}, expectedType string) i
 //   - {{$random.name}} - 随机中文名字
 //   - {{$random.username}} - 随机用户名
 func (e *Executor) generateRandomValue(randomType string) string {
+	// 候选项中可能包含点号，需要在按点号拆分之前处理
+	if strings.HasPrefix(randomType, randomChoicePrefix) && strings.HasSuffix(randomType, ")") {
+		return e.randomChoice(strings.TrimSuffix(strings.TrimPrefix(randomType, randomChoicePrefix), ")"))
+	}
+
 	// 解析类型和参数
 	parts := strings.Split(randomType, ".")
 	if len(parts) < 2 {
@@ -930,6 +2108,12 @@ func (e *Executor) generateRandomValue(randomType string) string {
 		}
 		return e.randomNumber(length)
 
+	case "int":
+		return e.randomIntInRange(parts[2:])
+
+	case "float":
+		return e.randomFloatInRange(parts[2:])
+
 	case "uuid":
 		return e.randomUUID()
 
@@ -959,13 +2143,116 @@ func (e *Executor) generateRandomValue(randomType string) string {
 	}
 }
 
+// randomChoicePrefix 从候选列表中随机选择的占位符前缀，如 $random.choice(北京,上海,广州)
+const randomChoicePrefix = "$random.choice("
+
+// randomChoice 从逗号分隔的候选列表中随机返回一项，忽略空白项，列表为空时返回空字符串
+func (e *Executor) randomChoice(list string) string {
+	options := make([]string, 0)
+	for _, option := range strings.Split(list, ",") {
+		if option = strings.TrimSpace(option); option != "" {
+			options = append(options, option)
+		}
+	}
+	if len(options) == 0 {
+		return ""
+	}
+	return options[e.randomIntn(len(options))]
+}
+
+// typedRandomValue 将 $random.int 和 $random.float 的结果转换为数字，
+// 使整个值为单个变量引用时请求体中得到数字而不是字符串
+func typedRandomValue(varPath, value string) interface{} {
+	switch {
+	case strings.HasPrefix(varPath, "$random.int."):
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case strings.HasPrefix(varPath, "$random.float."):
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return value
+}
+
+// randomIntInRange 生成 [min, max] 范围内的随机整数，参数无效时返回空字符串
+func (e *Executor) randomIntInRange(params []string) string {
+	if len(params) != 2 {
+		return ""
+	}
+	lower, err := strconv.ParseInt(params[0], 10, 64)
+	if err != nil {
+		return ""
+	}
+	upper, err := strconv.ParseInt(params[1], 10, 64)
+	if err != nil || lower > upper {
+		return ""
+	}
+	span := upper - lower + 1
+	if span <= 0 {
+		// 范围超出 int64
+		return ""
+	}
+	return strconv.FormatInt(lower+e.randomInt63n(span), 10)
+}
+
+// randomFloatInRange 生成 [min, max) 范围内的随机浮点数，参数无效时返回空字符串
+// 由于点号用作分隔符，边界只能是整数，如 $random.float.0.1
+func (e *Executor) randomFloatInRange(params []string) string {
+	if len(params) != 2 {
+		return ""
+	}
+	lower, err := strconv.ParseFloat(params[0], 64)
+	if err != nil {
+		return ""
+	}
+	upper, err := strconv.ParseFloat(params[1], 64)
+	if err != nil || lower > upper {
+		return ""
+	}
+	return strconv.FormatFloat(lower+e.randomFloat64()*(upper-lower), 'f', -1, 64)
+}
+
+// randomIntn 返回 [0, n) 范围内的随机整数，配置了随机种子时使用确定性随机源
+func (e *Executor) randomIntn(n int) int {
+	return int(e.randomInt63n(int64(n)))
+}
+
+// randomInt63n 返回 [0, n) 范围内的随机 int64，配置了随机种子时使用确定性随机源
+func (e *Executor) randomInt63n(n int64) int64 {
+	if e.random != nil {
+		e.randomMu.Lock()
+		defer e.randomMu.Unlock()
+		return e.random.Int63n(n)
+	}
+	value, _ := rand.Int(rand.Reader, big.NewInt(n))
+	return value.Int64()
+}
+
+// randomFloat64 返回 [0, 1) 范围内的随机浮点数
+func (e *Executor) randomFloat64() float64 {
+	const precision = 1 << 53
+	return float64(e.randomInt63n(precision)) / precision
+}
+
+// randomBytes 用随机字节填充 b，配置了随机种子时使用确定性随机源
+func (e *Executor) randomBytes(b []byte) {
+	if e.random != nil {
+		e.randomMu.Lock()
+		defer e.randomMu.Unlock()
+		e.random.Read(b)
+		return
+	}
+	rand.Read(b)
+}
+
 // randomString 生成随机字符串
 func (e *Executor) randomString(length int) string {
 	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	result := make([]byte, length)
 	for i := range result {
-		n, _ := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
-		result[i] = charset[n.Int64()]
+		result[i] = charset[e.randomIntn(len(charset))]
 	}
 	return string(result)
 }
@@ -975,13 +2262,11 @@ func (e *Executor) randomNumber(length int) string {
 	const charset = "0123456789"
 	result := make([]byte, length)
 	for i := range result {
-		n, _ := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
-		result[i] = charset[n.Int64()]
+		result[i] = charset[e.randomIntn(len(charset))]
 	}
 	// 确保第一位不为0
 	if result[0] == '0' {
-		n, _ := rand.Int(rand.Reader, big.NewInt(9))
-		result[0] = charset[n.Int64()+1]
+		result[0] = charset[e.randomIntn(9)+1]
 	}
 	return string(result)
 }
@@ -989,7 +2274,7 @@ func (e *Executor) randomNumber(length int) string {
 // randomUUID 生成UUID
 func (e *Executor) randomUUID() string {
 	uuid := make([]byte, 16)
-	rand.Read(uuid)
+	e.randomBytes(uuid)
 	// 设置版本4和变体位
 	uuid[6] = (uuid[6] & 0x0f) | 0x40
 	uuid[8] = (uuid[8] & 0x3f) | 0x80
@@ -1004,8 +2289,7 @@ func (e *Executor) randomUUID() string {
 // randomEmail 生成随机邮箱
 func (e *Executor) randomEmail() string {
 	domains := []string{"test.com", "example.com", "demo.org", "mail.com"}
-	n, _ := rand.Int(rand.Reader, big.NewInt(int64(len(domains))))
-	return fmt.Sprintf("%s@%s", e.randomString(8), domains[n.Int64()])
+	return fmt.Sprintf("%s@%s", e.randomString(8), domains[e.randomIntn(len(domains))])
 }
 
 // randomPhone 生成随机手机号
@@ -1013,8 +2297,7 @@ func (e *Executor) randomPhone() string {
 	prefixes := []string{"130", "131", "132", "133", "134", "135", "136", "137", "138", "139",
 		"150", "151", "152", "153", "155", "156", "157", "158", "159",
 		"180", "181", "182", "183", "184", "185", "186", "187", "188", "189"}
-	n, _ := rand.Int(rand.Reader, big.NewInt(int64(len(prefixes))))
-	return prefixes[n.Int64()] + e.randomNumber(8)
+	return prefixes[e.randomIntn(len(prefixes))] + e.randomNumber(8)
 }
 
 // randomChineseName 生成随机中文名字
@@ -1025,14 +2308,11 @@ func (e *Executor) randomChineseName() string {
 		"勇", "艳", "杰", "娟", "涛", "明", "超", "秀兰", "霞", "平",
 		"刚", "桂英", "文", "华", "建", "国", "志", "海", "云", "峰"}
 
-	sn, _ := rand.Int(rand.Reader, big.NewInt(int64(len(surnames))))
-	nn, _ := rand.Int(rand.Reader, big.NewInt(int64(len(names))))
-	return surnames[sn.Int64()] + names[nn.Int64()]
+	return surnames[e.randomIntn(len(surnames))] + names[e.randomIntn(len(names))]
 }
 
 // randomUsername 生成随机用户名
 func (e *Executor) randomUsername() string {
 	prefixes := []string{"user", "test", "dev", "admin", "guest", "demo"}
-	n, _ := rand.Int(rand.Reader, big.NewInt(int64(len(prefixes))))
-	return fmt.Sprintf("%s_%s", prefixes[n.Int64()], e.randomString(6))
+	return fmt.Sprintf("%s_%s", prefixes[e.randomIntn(len(prefixes))], e.randomString(6))
 }
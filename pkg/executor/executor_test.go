@@ -1,7 +1,20 @@
 package executor
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"api_auto_test/pkg/client"
 	"api_auto_test/pkg/config"
+	"api_auto_test/pkg/logger"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -599,3 +612,2423 @@ var _ = Describe("Dependency Tracking", func() {
 		})
 	})
 })
+
+var _ = Describe("Disabled Tests", func() {
+	var executor *Executor
+
+	BeforeEach(func() {
+		var err error
+		executor, err = NewExecutor(&config.TestConfig{
+			BaseURL: "http://127.0.0.1:0",
+			APIs: []config.APITest{
+				{Name: "login", Disabled: true, Request: config.RequestConfig{Method: "POST", Path: "/login"}},
+				{Name: "profile", DependsOn: "login", Request: config.RequestConfig{Method: "GET", Path: "/profile"}},
+				{Name: "orders", DependsOn: "profile", Request: config.RequestConfig{Method: "GET", Path: "/orders"}},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should mark disabled tests as skipped without executing them", func() {
+		report := executor.Execute()
+		Expect(report.TotalTests).To(Equal(3))
+		Expect(report.SkippedTests).To(Equal(3))
+		Expect(report.FailedTests).To(Equal(0))
+
+		Expect(report.Results[0].Name).To(Equal("login"))
+		Expect(report.Results[0].SkipReason).To(Equal("disabled"))
+		Expect(report.Results[0].Response).To(BeNil())
+	})
+
+	It("should skip dependents with a clear reason", func() {
+		report := executor.Execute()
+		Expect(report.Results[1].SkipReason).To(Equal("依赖接口 'login' 已被禁用"))
+		Expect(report.Results[2].SkipReason).To(ContainSubstring("根本原因：接口 'login' 已被禁用"))
+	})
+
+	It("should hide disabled tests from the test list unless requested", func() {
+		Expect(executor.GetTestNames(false)).To(Equal([]string{"profile", "orders"}))
+		Expect(executor.GetTestNames(true)).To(Equal([]string{"login", "profile", "orders"}))
+	})
+
+	It("should treat skip as disabled and report the skip reason", func() {
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL: "http://127.0.0.1:0",
+			APIs: []config.APITest{
+				{Name: "flaky", Skip: true, SkipReason: "waiting for BUG-123", Request: config.RequestConfig{Method: "GET", Path: "/flaky"}},
+				{Name: "details", DependsOn: "flaky", Request: config.RequestConfig{Method: "GET", Path: "/details"}},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		report := executor.Execute()
+		Expect(report.SkippedTests).To(Equal(2))
+		Expect(report.Results[0].SkipReason).To(Equal("disabled: waiting for BUG-123"))
+		Expect(report.Results[0].Response).To(BeNil())
+		Expect(report.Results[1].SkipReason).To(Equal("依赖接口 'flaky' 已被禁用"))
+		Expect(executor.GetTestNames(false)).To(Equal([]string{"details"}))
+	})
+})
+
+var _ = Describe("Focused Tests", func() {
+	var (
+		server *httptest.Server
+		mu     sync.Mutex
+		paths  []string
+	)
+
+	BeforeEach(func() {
+		paths = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			paths = append(paths, r.URL.Path)
+			mu.Unlock()
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newTest := func(name, dependsOn string) config.APITest {
+		return config.APITest{
+			Name:      name,
+			DependsOn: dependsOn,
+			Request:   config.RequestConfig{Method: "GET", Path: "/" + name},
+			Response:  config.ResponseExpectation{StatusCode: 200},
+		}
+	}
+
+	It("should run only focused tests and their dependencies", func() {
+		focused := newTest("orders", "profile")
+		focused.Only = true
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL: server.URL,
+			APIs: []config.APITest{
+				newTest("login", ""),
+				newTest("profile", "login"),
+				newTest("settings", "login"),
+				focused,
+				newTest("invoices", "orders"),
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, report := range []*TestReport{executor.Execute(), executor.ExecuteConcurrent(4)} {
+			Expect(report.PassedTests).To(Equal(3))
+			Expect(report.SkippedTests).To(Equal(2))
+			for _, result := range report.Results {
+				if result.Name == "settings" || result.Name == "invoices" {
+					Expect(result.SkipReason).To(Equal("not focused"))
+				}
+			}
+		}
+		Expect(paths).To(Equal([]string{"/login", "/profile", "/orders", "/login", "/profile", "/orders"}))
+	})
+
+	It("should run everything when no test is focused", func() {
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL: server.URL,
+			APIs:    []config.APITest{newTest("login", ""), newTest("profile", "login")},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(executor.Execute().PassedTests).To(Equal(2))
+	})
+})
+
+var _ = Describe("Execute By Name", func() {
+	var (
+		server *httptest.Server
+		paths  []string
+	)
+
+	BeforeEach(func() {
+		paths = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"token": "abc", "id": 7}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newTest := func(name, dependsOn, path string) config.APITest {
+		return config.APITest{
+			Name:      name,
+			DependsOn: dependsOn,
+			Request:   config.RequestConfig{Method: "GET", Path: path},
+			Response:  config.ResponseExpectation{StatusCode: 200},
+		}
+	}
+
+	It("should run the dependency chain before the named test", func() {
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL: server.URL,
+			Setup:   []config.APITest{newTest("init", "", "/init")},
+			APIs: []config.APITest{
+				newTest("login", "", "/login"),
+				newTest("unrelated", "", "/unrelated"),
+				newTest("create", "login", "/create/{{login.response.token}}"),
+				newTest("get", "create", "/get/{{create.response.id}}"),
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		report, err := executor.ExecuteByName("get")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(paths).To(Equal([]string{"/init", "/login", "/create/abc", "/get/7"}))
+		Expect(report.TotalTests).To(Equal(3))
+		Expect(report.PassedTests).To(Equal(3))
+		Expect(report.Results[2].Name).To(Equal("get"))
+		Expect(report.SetupResults).To(HaveLen(1))
+	})
+
+	It("should return an error for an unknown test", func() {
+		executor, err := NewExecutor(&config.TestConfig{BaseURL: server.URL})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = executor.ExecuteByName("missing")
+		Expect(err).To(MatchError("test 'missing' not found"))
+	})
+})
+
+var _ = Describe("XML Responses", func() {
+	var (
+		server *httptest.Server
+		paths  []string
+	)
+
+	BeforeEach(func() {
+		paths = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(`<Result><User id="42"><Name>Tom</Name></User></Result>`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should validate and reference XML fields by path", func() {
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL: server.URL,
+			APIs: []config.APITest{
+				{
+					Name:    "get",
+					Request: config.RequestConfig{Method: "GET", Path: "/users"},
+					Response: config.ResponseExpectation{
+						StatusCode: 200,
+						Body:       map[string]interface{}{"Result.User.Name": "Tom"},
+					},
+				},
+				{
+					Name:      "detail",
+					DependsOn: "get",
+					Request:   config.RequestConfig{Method: "GET", Path: "/users/{{get.response.Result.User.@id}}"},
+					Response:  config.ResponseExpectation{StatusCode: 200},
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		report := executor.Execute()
+		Expect(report.PassedTests).To(Equal(2))
+		Expect(paths).To(Equal([]string{"/users", "/users/42"}))
+	})
+})
+
+var _ = Describe("Variable Defaults", func() {
+	var executor *Executor
+
+	BeforeEach(func() {
+		executor = &Executor{
+			results: make(map[string]*TestResult),
+		}
+		executor.storeResult(&TestResult{
+			Name:   "创建部门",
+			Passed: true,
+			Response: &client.Response{
+				BodyJSON: map[string]interface{}{
+					"data": map[string]interface{}{"id": float64(42)},
+				},
+			},
+		})
+	})
+
+	Context("when the referenced value is present", func() {
+		It("should use the actual value", func() {
+			test := config.APITest{Request: config.RequestConfig{
+				Path: "/dept/{{创建部门.response.data.id | default: 0}}",
+				Body: map[string]interface{}{"id": "{{创建部门.response.data.id | default: 0}}"},
+			}}
+			processed := executor.replaceVariables(test)
+			Expect(processed.Request.Path).To(Equal("/dept/42"))
+			Expect(processed.Request.Body.(map[string]interface{})["id"]).To(Equal(int64(42)))
+		})
+	})
+
+	Context("when the referenced value is missing", func() {
+		It("should substitute the default value", func() {
+			test := config.APITest{Request: config.RequestConfig{
+				Path: "/dept/{{创建部门.response.data.parent_id | default: 0}}",
+				Body: map[string]interface{}{
+					"parent_id": "{{创建部门.response.data.parent_id | default: 0}}",
+					"name":      "{{不存在.response.name | default: \"未命名\"}}",
+				},
+			}}
+			processed := executor.replaceVariables(test)
+			Expect(processed.Request.Path).To(Equal("/dept/0"))
+
+			body := processed.Request.Body.(map[string]interface{})
+			Expect(body["parent_id"]).To(Equal(int64(0)))
+			Expect(body["name"]).To(Equal("未命名"))
+		})
+
+		It("should leave the placeholder when no default is given", func() {
+			test := config.APITest{Request: config.RequestConfig{
+				Path: "/dept/{{创建部门.response.data.parent_id}}",
+			}}
+			processed := executor.replaceVariables(test)
+			Expect(processed.Request.Path).To(Equal("/dept/{{创建部门.response.data.parent_id}}"))
+		})
+	})
+})
+
+var _ = Describe("Environment Variables", func() {
+	var (
+		server   *httptest.Server
+		executor *Executor
+		gotAuth  string
+		calls    int
+	)
+
+	BeforeEach(func() {
+		calls = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			gotAuth = r.Header.Get("Authorization")
+		}))
+
+		var err error
+		executor, err = NewExecutor(&config.TestConfig{BaseURL: server.URL})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newTest := func() config.APITest {
+		return config.APITest{
+			Name: "secured",
+			Request: config.RequestConfig{
+				Method:  "GET",
+				Path:    "/secured",
+				Headers: map[string]string{"Authorization": "Bearer {{$env.API_AUTO_TEST_TOKEN}}"},
+			},
+			Response: config.ResponseExpectation{StatusCode: 200},
+		}
+	}
+
+	run := func(apiTest config.APITest) TestResult {
+		return executor.executeAPITest(executor.replaceVariables(apiTest))
+	}
+
+	It("should substitute the value of a set environment variable", func() {
+		GinkgoT().Setenv("API_AUTO_TEST_TOKEN", "secret")
+		result := run(newTest())
+		Expect(result.Passed).To(BeTrue())
+		Expect(gotAuth).To(Equal("Bearer secret"))
+	})
+
+	It("should substitute an empty string for a set but empty variable", func() {
+		GinkgoT().Setenv("API_AUTO_TEST_TOKEN", "")
+		apiTest := newTest()
+		apiTest.Request.Body = map[string]interface{}{"token": "{{$env.API_AUTO_TEST_TOKEN}}"}
+		processed := executor.replaceVariables(apiTest)
+		Expect(processed.Request.Body.(map[string]interface{})["token"]).To(Equal(""))
+		Expect(processed.Request.Headers["Authorization"]).To(Equal("Bearer "))
+
+		result := executor.executeAPITest(processed)
+		Expect(result.Passed).To(BeTrue())
+		Expect(calls).To(Equal(1))
+	})
+
+	It("should fail with a clear error when the variable is unset", func() {
+		GinkgoT().Setenv("API_AUTO_TEST_TOKEN", "")
+		Expect(os.Unsetenv("API_AUTO_TEST_TOKEN")).To(Succeed())
+		result := run(newTest())
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Error).To(MatchError("environment variable 'API_AUTO_TEST_TOKEN' is not set"))
+		Expect(calls).To(Equal(0))
+	})
+
+	It("should use the default when the variable is unset", func() {
+		GinkgoT().Setenv("API_AUTO_TEST_TOKEN", "")
+		Expect(os.Unsetenv("API_AUTO_TEST_TOKEN")).To(Succeed())
+		apiTest := newTest()
+		apiTest.Request.Headers["Authorization"] = "Bearer {{$env.API_AUTO_TEST_TOKEN | default: anonymous}}"
+		result := run(apiTest)
+		Expect(result.Passed).To(BeTrue())
+		Expect(gotAuth).To(Equal("Bearer anonymous"))
+	})
+})
+
+var _ = Describe("Expectation Resolution", func() {
+	var executor *Executor
+
+	BeforeEach(func() {
+		executor = &Executor{
+			config: &config.TestConfig{
+				SuccessField: &config.SuccessField{Path: "code", Equals: 0},
+			},
+		}
+	})
+
+	It("should apply the global success field when the test does not set one", func() {
+		expectation := executor.resolveExpectation(config.ResponseExpectation{StatusCode: 200})
+		Expect(expectation.SuccessField).To(Equal(&config.SuccessField{Path: "code", Equals: 0}))
+	})
+
+	It("should keep the per-test success field override", func() {
+		override := &config.SuccessField{Path: "errno", Equals: "OK"}
+		expectation := executor.resolveExpectation(config.ResponseExpectation{SuccessField: override})
+		Expect(expectation.SuccessField).To(BeIdenticalTo(override))
+	})
+})
+
+var _ = Describe("Idempotency Tests", func() {
+	var (
+		server   *httptest.Server
+		executor *Executor
+		calls    int
+	)
+
+	BeforeEach(func() {
+		calls = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"id":1,"status":"ok","updated_at":%d}`, calls)
+		}))
+
+		var err error
+		executor, err = NewExecutor(&config.TestConfig{BaseURL: server.URL})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should pass when the only differences are ignored fields", func() {
+		result := executor.executeAPITest(config.APITest{
+			Name:    "put-user",
+			Type:    "idempotency",
+			Request: config.RequestConfig{Method: "PUT", Path: "/users/1"},
+			Response: config.ResponseExpectation{
+				StatusCode:   200,
+				IgnoreFields: []string{"updated_at"},
+			},
+		})
+		Expect(calls).To(Equal(2))
+		Expect(result.Passed).To(BeTrue())
+	})
+
+	It("should report the differing fields", func() {
+		result := executor.executeAPITest(config.APITest{
+			Name:     "put-user",
+			Type:     "idempotency",
+			Request:  config.RequestConfig{Method: "PUT", Path: "/users/1"},
+			Response: config.ResponseExpectation{StatusCode: 200},
+		})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Validation.Errors).To(HaveLen(1))
+		Expect(result.Validation.Errors[0].Field).To(Equal("Idempotency.updated_at"))
+	})
+
+	It("should send the request once for regular tests", func() {
+		result := executor.executeAPITest(config.APITest{
+			Name:     "get-user",
+			Request:  config.RequestConfig{Method: "GET", Path: "/users/1"},
+			Response: config.ResponseExpectation{StatusCode: 200},
+		})
+		Expect(result.Passed).To(BeTrue())
+		Expect(calls).To(Equal(1))
+	})
+})
+
+var _ = Describe("Retry Backoff", func() {
+	var executor *Executor
+
+	BeforeEach(func() {
+		executor = &Executor{}
+	})
+
+	It("should keep a fixed interval when the multiplier is 0 or 1", func() {
+		for _, multiplier := range []float64{0, 1} {
+			policy := config.RetryPolicy{Interval: time.Second, BackoffMultiplier: multiplier}
+			Expect(executor.retryDelay(policy, 1)).To(Equal(time.Second))
+			Expect(executor.retryDelay(policy, 3)).To(Equal(time.Second))
+		}
+	})
+
+	It("should grow the interval exponentially", func() {
+		policy := config.RetryPolicy{Interval: 100 * time.Millisecond, BackoffMultiplier: 2}
+		Expect(executor.retryDelay(policy, 1)).To(Equal(100 * time.Millisecond))
+		Expect(executor.retryDelay(policy, 2)).To(Equal(200 * time.Millisecond))
+		Expect(executor.retryDelay(policy, 3)).To(Equal(400 * time.Millisecond))
+	})
+
+	It("should cap the interval at MaxInterval", func() {
+		policy := config.RetryPolicy{
+			Interval:          100 * time.Millisecond,
+			BackoffMultiplier: 3,
+			MaxInterval:       500 * time.Millisecond,
+		}
+		Expect(executor.retryDelay(policy, 2)).To(Equal(300 * time.Millisecond))
+		Expect(executor.retryDelay(policy, 5)).To(Equal(500 * time.Millisecond))
+	})
+
+	It("should randomize the delay within ±20% when jitter is enabled", func() {
+		policy := config.RetryPolicy{Interval: time.Second, Jitter: true}
+		for i := 0; i < 50; i++ {
+			delay := executor.retryDelay(policy, 1)
+			Expect(delay).To(BeNumerically(">=", 800*time.Millisecond))
+			Expect(delay).To(BeNumerically("<", 1200*time.Millisecond))
+		}
+	})
+})
+
+var _ = Describe("Retry On Status Codes", func() {
+	var (
+		server   *httptest.Server
+		executor *Executor
+		calls    int
+		status   int
+	)
+
+	BeforeEach(func() {
+		calls = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(status)
+		}))
+
+		var err error
+		executor, err = NewExecutor(&config.TestConfig{BaseURL: server.URL})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newTest := func(retryOn []int) config.APITest {
+		return config.APITest{
+			Name:        "flaky",
+			Request:     config.RequestConfig{Method: "GET", Path: "/flaky"},
+			Response:    config.ResponseExpectation{StatusCode: 200},
+			RetryPolicy: config.RetryPolicy{MaxRetries: 2, RetryOn: retryOn},
+		}
+	}
+
+	It("should retry when the status code is listed", func() {
+		status = http.StatusServiceUnavailable
+		result := executor.executeAPITest(newTest([]int{429, 503}))
+		Expect(result.Passed).To(BeFalse())
+		Expect(calls).To(Equal(3))
+		Expect(result.RetryCount).To(Equal(2))
+	})
+
+	It("should record each attempt's status code and mark a retried pass as flaky", func() {
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		})
+		result := executor.executeAPITest(newTest([]int{503}))
+		Expect(result.Passed).To(BeTrue())
+		Expect(result.Attempts).To(Equal([]int{503, 503, 200}))
+		Expect(result.Flaky()).To(BeTrue())
+	})
+
+	It("should return immediately when the status code is not listed", func() {
+		status = http.StatusBadRequest
+		result := executor.executeAPITest(newTest([]int{429, 503}))
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.StatusCode).To(Equal(http.StatusBadRequest))
+		Expect(calls).To(Equal(1))
+		Expect(result.RetryCount).To(Equal(0))
+		Expect(result.Attempts).To(Equal([]int{http.StatusBadRequest}))
+		Expect(result.Flaky()).To(BeFalse())
+	})
+
+	It("should retry on any validation failure when RetryOn is empty", func() {
+		status = http.StatusBadRequest
+		executor.executeAPITest(newTest(nil))
+		Expect(calls).To(Equal(3))
+	})
+})
+
+var _ = Describe("Hosts File Fan-Out", func() {
+	var (
+		healthy   *httptest.Server
+		unhealthy *httptest.Server
+		executor  *Executor
+		hostsFile string
+	)
+
+	BeforeEach(func() {
+		healthy = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		unhealthy = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+
+		hostsFile = filepath.Join(GinkgoT().TempDir(), "hosts.txt")
+		var err error
+		executor, err = NewExecutor(&config.TestConfig{BaseURL: "http://{{host}}"})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		healthy.Close()
+		unhealthy.Close()
+	})
+
+	writeHosts := func(lines ...string) {
+		Expect(os.WriteFile(hostsFile, []byte(strings.Join(lines, "\n")), 0644)).To(Succeed())
+	}
+
+	newTest := func() config.APITest {
+		return config.APITest{
+			Name:      "health",
+			HostsFile: hostsFile,
+			Request:   config.RequestConfig{Method: "GET", Path: "/health"},
+			Response:  config.ResponseExpectation{StatusCode: 200},
+		}
+	}
+
+	It("should execute once per host and pass when all hosts pass", func() {
+		writeHosts("# replicas", strings.TrimPrefix(healthy.URL, "http://"), "", strings.TrimPrefix(healthy.URL, "http://"))
+		result := executor.executeAPITest(newTest())
+		Expect(result.Passed).To(BeTrue())
+		Expect(result.HostResults).To(HaveLen(2))
+		Expect(result.HostResults[0].StatusCode).To(Equal(http.StatusOK))
+	})
+
+	It("should fail and report each host when one host fails", func() {
+		healthyHost := strings.TrimPrefix(healthy.URL, "http://")
+		unhealthyHost := strings.TrimPrefix(unhealthy.URL, "http://")
+		writeHosts(healthyHost, unhealthyHost)
+
+		result := executor.executeAPITest(newTest())
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.HostResults).To(HaveLen(2))
+		Expect(result.HostResults[0].Host).To(Equal(healthyHost))
+		Expect(result.HostResults[0].Passed).To(BeTrue())
+		Expect(result.HostResults[1].Host).To(Equal(unhealthyHost))
+		Expect(result.HostResults[1].Passed).To(BeFalse())
+		Expect(result.StatusCode).To(Equal(http.StatusInternalServerError))
+	})
+
+	It("should substitute the host into the path", func() {
+		writeHosts("example")
+		var gotPath string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotPath = r.URL.Path
+		}))
+		defer server.Close()
+
+		apiTest := newTest()
+		apiTest.Request.BaseURL = server.URL
+		apiTest.Request.Path = "/hosts/{{host}}"
+		result := executor.executeAPITest(apiTest)
+		Expect(result.Passed).To(BeTrue())
+		Expect(gotPath).To(Equal("/hosts/example"))
+	})
+
+	It("should report an error when the hosts file cannot be read", func() {
+		result := executor.executeAPITest(newTest())
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Error).To(HaveOccurred())
+		Expect(result.HostResults).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Dependency Graph", func() {
+	It("should write nodes in execution order and edges for depends_on", func() {
+		executor, err := NewExecutor(&config.TestConfig{
+			APIs: []config.APITest{
+				{Name: "get profile", DependsOn: "login"},
+				{Name: "login", Weight: 10, Tags: []string{"smoke", "auth"}},
+				{Name: "legacy", Disabled: true},
+				{Name: "orphan", DependsOn: "missing"},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		var sb strings.Builder
+		Expect(executor.WriteDOT(&sb)).To(Succeed())
+		dot := sb.String()
+
+		Expect(dot).To(HavePrefix("digraph plan {"))
+		Expect(dot).To(ContainSubstring(`"login" [label="1. login\nweight: 10\ntags: smoke, auth"];`))
+		Expect(dot).To(ContainSubstring(`"legacy" [label="2. legacy\nweight: 0", style=dashed, fontcolor=gray];`))
+		Expect(dot).To(ContainSubstring(`"login" -> "get profile";`))
+		Expect(dot).To(ContainSubstring(`"missing" [label="missing\n(missing)", color=red, fontcolor=red];`))
+		Expect(dot).To(ContainSubstring(`"missing" -> "orphan";`))
+	})
+})
+
+var _ = Describe("Execution Order", func() {
+	names := func(apis []config.APITest) []string {
+		executor, err := NewExecutor(&config.TestConfig{APIs: apis})
+		Expect(err).NotTo(HaveOccurred())
+		order, err := executor.resolveExecutionOrder(executor.config.APIs)
+		Expect(err).NotTo(HaveOccurred())
+		result := make([]string, 0, len(order))
+		for _, api := range order {
+			result = append(result, api.Name)
+		}
+		return result
+	}
+
+	It("should order by weight and keep config order for equal weights", func() {
+		Expect(names([]config.APITest{
+			{Name: "a"},
+			{Name: "b", Weight: 5},
+			{Name: "c"},
+			{Name: "d", Weight: 5},
+		})).To(Equal([]string{"b", "d", "a", "c"}))
+	})
+
+	It("should run dependencies first even when the dependent has a higher weight", func() {
+		Expect(names([]config.APITest{
+			{Name: "get", DependsOn: "create", Weight: 100},
+			{Name: "create"},
+			{Name: "health", Weight: 1},
+		})).To(Equal([]string{"health", "create", "get"}))
+	})
+
+	It("should use weight only among tests at the same dependency level", func() {
+		Expect(names([]config.APITest{
+			{Name: "login"},
+			{Name: "list", DependsOn: "login"},
+			{Name: "profile", DependsOn: "login", Weight: 10},
+			{Name: "audit", DependsOn: "list", Weight: 99},
+			{Name: "orphan", DependsOn: "missing", Weight: 3},
+		})).To(Equal([]string{"orphan", "login", "profile", "list", "audit"}))
+	})
+
+	It("should match the order of concurrent waves", func() {
+		executor, err := NewExecutor(&config.TestConfig{APIs: []config.APITest{
+			{Name: "c", DependsOn: "a"},
+			{Name: "b", DependsOn: "a", Weight: 2},
+			{Name: "a"},
+			{Name: "d"},
+		}})
+		Expect(err).NotTo(HaveOccurred())
+		order, err := executor.resolveExecutionOrder(executor.config.APIs)
+		Expect(err).NotTo(HaveOccurred())
+
+		flattened := make([]int, 0, len(order))
+		for _, wave := range executionWaves(order) {
+			flattened = append(flattened, wave...)
+		}
+		Expect(flattened).To(Equal([]int{0, 1, 2, 3}))
+	})
+})
+
+var _ = Describe("Circular Dependencies", func() {
+	var (
+		server   *httptest.Server
+		executor *Executor
+		calls    int
+	)
+
+	BeforeEach(func() {
+		calls = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+		}))
+
+		var err error
+		executor, err = NewExecutor(&config.TestConfig{
+			BaseURL: server.URL,
+			Setup:   []config.APITest{{Name: "login", Request: config.RequestConfig{Method: "POST", Path: "/login"}}},
+			APIs: []config.APITest{
+				{Name: "health", Request: config.RequestConfig{Method: "GET", Path: "/health"}},
+				{Name: "a", DependsOn: "c", Request: config.RequestConfig{Method: "GET", Path: "/a"}},
+				{Name: "b", DependsOn: "a", Request: config.RequestConfig{Method: "GET", Path: "/b"}},
+				{Name: "c", DependsOn: "b", Request: config.RequestConfig{Method: "GET", Path: "/c"}},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should name the tests in the cycle", func() {
+		_, err := executor.resolveExecutionOrder(executor.config.APIs)
+		Expect(err).To(MatchError("circular dependency: a -> c -> b -> a"))
+	})
+
+	It("should fail the run without executing any test", func() {
+		report := executor.Execute()
+		Expect(report.Error).To(MatchError("circular dependency: a -> c -> b -> a"))
+		Expect(report.Results).To(BeEmpty())
+		Expect(report.SetupResults).To(BeEmpty())
+		Expect(calls).To(Equal(0))
+	})
+
+	It("should fail concurrent runs the same way", func() {
+		report := executor.ExecuteConcurrent(4)
+		Expect(report.Error).To(MatchError("circular dependency: a -> c -> b -> a"))
+		Expect(calls).To(Equal(0))
+	})
+
+	It("should return the error when writing the dependency graph", func() {
+		var sb strings.Builder
+		Expect(executor.WriteDOT(&sb)).To(MatchError(ContainSubstring("circular dependency")))
+	})
+})
+
+var _ = Describe("Variable Capture", func() {
+	var (
+		server   *httptest.Server
+		requests map[string]*http.Request
+		bodies   map[string]string
+	)
+
+	BeforeEach(func() {
+		requests = make(map[string]*http.Request)
+		bodies = make(map[string]string)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			requests[r.URL.Path] = r
+			bodies[r.URL.Path] = string(data)
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Path == "/login" {
+				w.Write([]byte(`{"data":{"token":"abc","id":7,"first":"Tom","last":"Lee"}}`))
+				return
+			}
+			w.Write([]byte(`{"ok":true}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	run := func(apis ...config.APITest) *TestReport {
+		executor, err := NewExecutor(&config.TestConfig{BaseURL: server.URL, APIs: apis})
+		Expect(err).NotTo(HaveOccurred())
+		return executor.Execute()
+	}
+
+	It("should resolve global variables and let captured values override them", func() {
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL:   server.URL,
+			Variables: map[string]interface{}{"region": "cn", "token": "global"},
+			APIs: []config.APITest{
+				{
+					Name:     "login",
+					Weight:   10,
+					Request:  config.RequestConfig{Method: "POST", Path: "/login"},
+					Response: config.ResponseExpectation{StatusCode: 200},
+					Capture:  map[string]string{"token": "data.token"},
+				},
+				{
+					Name:     "profile",
+					Request:  config.RequestConfig{Method: "GET", Path: "/profile/{{region}}/{{token}}"},
+					Response: config.ResponseExpectation{StatusCode: 200},
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		report := executor.Execute()
+		Expect(report.PassedTests).To(Equal(2))
+		Expect(requests).To(HaveKey("/profile/cn/abc"))
+	})
+
+	It("should resolve imported variables and report them with captured ones", func() {
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL:   server.URL,
+			Variables: map[string]interface{}{"region": "cn", "userId": 1},
+			APIs: []config.APITest{
+				{
+					Name:     "login",
+					Weight:   10,
+					Request:  config.RequestConfig{Method: "POST", Path: "/login"},
+					Response: config.ResponseExpectation{StatusCode: 200},
+					Capture:  map[string]string{"token": "data.token"},
+				},
+				{
+					Name:     "profile",
+					Request:  config.RequestConfig{Method: "GET", Path: "/users/{{userId}}"},
+					Response: config.ResponseExpectation{StatusCode: 200},
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		executor.SetVariables(map[string]interface{}{"userId": float64(42)})
+
+		report := executor.Execute()
+		Expect(report.PassedTests).To(Equal(2))
+		Expect(requests).To(HaveKey("/users/42"))
+		Expect(executor.CapturedVariables()).To(Equal(map[string]interface{}{"userId": float64(42), "token": "abc"}))
+	})
+
+	It("should capture named values and resolve them in later requests", func() {
+		report := run(
+			config.APITest{
+				Name:     "login",
+				Weight:   10,
+				Request:  config.RequestConfig{Method: "POST", Path: "/login"},
+				Response: config.ResponseExpectation{StatusCode: 200},
+				Capture: map[string]string{
+					"token":  "data.token",
+					"userId": "response.data.id",
+				},
+			},
+			config.APITest{
+				Name: "profile",
+				Request: config.RequestConfig{
+					Method:  "PUT",
+					Path:    "/users/{{userId}}",
+					Headers: map[string]string{"Authorization": "Bearer {{token}}"},
+					Body:    map[string]interface{}{"id": "{{userId}}"},
+				},
+				Response: config.ResponseExpectation{StatusCode: 200},
+			},
+		)
+
+		Expect(report.PassedTests).To(Equal(2))
+		Expect(requests).To(HaveKey("/users/7"))
+		Expect(requests["/users/7"].Header.Get("Authorization")).To(Equal("Bearer abc"))
+		Expect(bodies["/users/7"]).To(Equal(`{"id":7}`))
+	})
+
+	It("should resolve templated capture values combining several fields", func() {
+		report := run(
+			config.APITest{
+				Name:     "登录",
+				Weight:   10,
+				Request:  config.RequestConfig{Method: "POST", Path: "/login"},
+				Response: config.ResponseExpectation{StatusCode: 200},
+				Capture: map[string]string{
+					"fullName": "{{登录.response.data.first}} {{登录.response.data.last}}",
+				},
+			},
+			config.APITest{
+				Name:     "greet",
+				Request:  config.RequestConfig{Method: "POST", Path: "/greet", Body: map[string]interface{}{"name": "{{fullName}}"}},
+				Response: config.ResponseExpectation{StatusCode: 200},
+			},
+		)
+
+		Expect(report.PassedTests).To(Equal(2))
+		Expect(bodies["/greet"]).To(Equal(`{"name":"Tom Lee"}`))
+	})
+
+	It("should give captured names precedence over test references", func() {
+		report := run(
+			config.APITest{
+				Name:     "login",
+				Weight:   10,
+				Request:  config.RequestConfig{Method: "POST", Path: "/login"},
+				Response: config.ResponseExpectation{StatusCode: 200},
+				Capture:  map[string]string{"login": "data.token"},
+			},
+			config.APITest{
+				Name:     "echo",
+				Request:  config.RequestConfig{Method: "GET", Path: "/echo/{{login}}"},
+				Response: config.ResponseExpectation{StatusCode: 200},
+			},
+		)
+
+		Expect(report.PassedTests).To(Equal(2))
+		Expect(requests).To(HaveKey("/echo/abc"))
+	})
+
+	It("should not capture values from failed tests", func() {
+		report := run(
+			config.APITest{
+				Name:     "login",
+				Weight:   10,
+				Request:  config.RequestConfig{Method: "POST", Path: "/login"},
+				Response: config.ResponseExpectation{StatusCode: 201},
+				Capture:  map[string]string{"token": "data.token"},
+			},
+			config.APITest{
+				Name:     "echo",
+				Request:  config.RequestConfig{Method: "GET", Path: "/echo/{{token | default: none}}"},
+				Response: config.ResponseExpectation{StatusCode: 200},
+			},
+		)
+
+		Expect(report.FailedTests).To(Equal(1))
+		Expect(requests).To(HaveKey("/echo/none"))
+	})
+
+	// 用 go test -race 运行时可以检测捕获变量的并发读写
+	// depends_on 只能指定一个接口，因此每个读取方都通过依赖边依赖它读取的所有捕获变量的写入方
+	It("should resolve captures from parallel branches in their dependent tests", func() {
+		var (
+			mu    sync.Mutex
+			paths []string
+		)
+		branches := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			paths = append(paths, r.URL.Path)
+			mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"value":%q}`, strings.ReplaceAll(strings.TrimPrefix(r.URL.Path, "/"), "/", "-"))
+		}))
+		defer branches.Close()
+
+		api := func(name, path, dependsOn string, capture map[string]string) config.APITest {
+			return config.APITest{
+				Name:      name,
+				DependsOn: dependsOn,
+				Request:   config.RequestConfig{Method: "GET", Path: path},
+				Response:  config.ResponseExpectation{StatusCode: 200},
+				Capture:   capture,
+			}
+		}
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL: branches.URL,
+			APIs: []config.APITest{
+				api("a", "/a", "", map[string]string{"fromA": "value"}),
+				api("b", "/b/{{fromA}}", "a", map[string]string{"fromB": "value"}),
+				api("c", "/c/{{fromA}}", "a", map[string]string{"fromC": "value"}),
+				api("d", "/d/{{fromA}}/{{fromB}}", "b", map[string]string{"fromD": "value"}),
+				api("e", "/e/{{fromA}}/{{fromC}}", "c", nil),
+				api("f", "/f/{{fromB}}/{{fromD}}", "d", nil),
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		report := executor.ExecuteConcurrent(4)
+		Expect(report.PassedTests).To(Equal(6))
+		Expect(paths).To(ContainElements("/d/a/b-a", "/e/a/c-a", "/f/b-a/d-a-b-a"))
+		Expect(executor.CapturedVariables()).To(Equal(map[string]interface{}{
+			"fromA": "a", "fromB": "b-a", "fromC": "c-a", "fromD": "d-a-b-a",
+		}))
+	})
+})
+
+var _ = Describe("Setup and Teardown", func() {
+	var (
+		server *httptest.Server
+		paths  []string
+	)
+
+	BeforeEach(func() {
+		paths = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.Method+" "+r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/seed":
+				w.Write([]byte(`{"id":42}`))
+			case "/broken":
+				w.WriteHeader(http.StatusInternalServerError)
+			default:
+				w.Write([]byte(`{"ok":true}`))
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	hook := func(name, method, path string) config.APITest {
+		return config.APITest{
+			Name:     name,
+			Request:  config.RequestConfig{Method: method, Path: path},
+			Response: config.ResponseExpectation{StatusCode: 200},
+		}
+	}
+
+	It("should run setup before and teardown after the main tests", func() {
+		cfg := &config.TestConfig{
+			BaseURL:  server.URL,
+			Setup:    []config.APITest{hook("seed", "POST", "/seed")},
+			APIs:     []config.APITest{hook("get", "GET", "/items/{{seed.response.id}}")},
+			Teardown: []config.APITest{hook("cleanup", "DELETE", "/items/{{seed.response.id}}")},
+		}
+		executor, err := NewExecutor(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		report := executor.Execute()
+		Expect(paths).To(Equal([]string{"POST /seed", "GET /items/42", "DELETE /items/42"}))
+		Expect(report.TotalTests).To(Equal(1))
+		Expect(report.PassedTests).To(Equal(1))
+		Expect(report.SetupResults).To(HaveLen(1))
+		Expect(report.TeardownResults).To(HaveLen(1))
+		Expect(report.TeardownResults[0].Passed).To(BeTrue())
+	})
+
+	It("should run teardown even when main tests fail", func() {
+		cfg := &config.TestConfig{
+			BaseURL:  server.URL,
+			APIs:     []config.APITest{hook("broken", "GET", "/broken")},
+			Teardown: []config.APITest{hook("cleanup", "DELETE", "/items")},
+		}
+		executor, err := NewExecutor(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		report := executor.ExecuteConcurrent(2)
+		Expect(report.FailedTests).To(Equal(1))
+		Expect(report.TeardownResults).To(HaveLen(1))
+		Expect(paths).To(Equal([]string{"GET /broken", "DELETE /items"}))
+	})
+
+	It("should skip main tests when a setup hook fails", func() {
+		cfg := &config.TestConfig{
+			BaseURL:  server.URL,
+			Setup:    []config.APITest{hook("seed", "POST", "/broken")},
+			APIs:     []config.APITest{hook("get", "GET", "/items")},
+			Teardown: []config.APITest{hook("cleanup", "DELETE", "/items")},
+		}
+		executor, err := NewExecutor(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		report := executor.Execute()
+		Expect(report.SkippedTests).To(Equal(1))
+		Expect(report.Results[0].SkipReason).To(ContainSubstring("seed"))
+		Expect(paths).To(Equal([]string{"POST /broken", "DELETE /items"}))
+	})
+
+	It("should mark the report as failed when a setup or teardown hook fails", func() {
+		cfg := &config.TestConfig{
+			BaseURL:  server.URL,
+			Setup:    []config.APITest{hook("seed", "POST", "/broken")},
+			APIs:     []config.APITest{hook("get", "GET", "/items")},
+			Teardown: []config.APITest{hook("cleanup", "DELETE", "/broken")},
+		}
+		executor, err := NewExecutor(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, report := range []*TestReport{executor.Execute(), executor.ExecuteConcurrent(2)} {
+			Expect(report.FailedTests).To(Equal(0))
+			Expect(report.FailedHooks).To(Equal(2))
+			Expect(report.Failed()).To(BeTrue())
+		}
+
+		merged := MergeReports("all", []*TestReport{{FailedHooks: 1}, {PassedTests: 1, TotalTests: 1}})
+		Expect(merged.Failed()).To(BeTrue())
+	})
+
+	It("should not count disabled hooks as failures", func() {
+		disabled := hook("seed", "POST", "/broken")
+		disabled.Disabled = true
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL: server.URL,
+			Setup:   []config.APITest{disabled},
+			APIs:    []config.APITest{hook("get", "GET", "/items")},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		report := executor.Execute()
+		Expect(report.FailedHooks).To(BeZero())
+		Expect(report.Failed()).To(BeFalse())
+	})
+})
+
+var _ = Describe("Data-Driven Tests", func() {
+	var (
+		server *httptest.Server
+		bodies []string
+	)
+
+	BeforeEach(func() {
+		bodies = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(data))
+			if strings.Contains(string(data), "invalid") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	signup := config.APITest{
+		Name:   "signup",
+		Weight: 10,
+		Request: config.RequestConfig{
+			Method: "POST",
+			Path:   "/users",
+			Body:   map[string]interface{}{"email": "{{row.email}}", "age": "{{row.age}}"},
+		},
+		Response: config.ResponseExpectation{StatusCode: 200},
+	}
+
+	run := func(apis ...config.APITest) *TestReport {
+		executor, err := NewExecutor(&config.TestConfig{BaseURL: server.URL, APIs: apis})
+		Expect(err).NotTo(HaveOccurred())
+		return executor.Execute()
+	}
+
+	It("should execute once per row and report each row separately", func() {
+		test := signup
+		test.Dataset = []map[string]interface{}{
+			{"email": "a@example.com", "age": 18},
+			{"email": "invalid", "age": 0},
+		}
+
+		report := run(test)
+		Expect(bodies).To(Equal([]string{`{"age":18,"email":"a@example.com"}`, `{"age":0,"email":"invalid"}`}))
+		Expect(report.TotalTests).To(Equal(2))
+		Expect(report.PassedTests).To(Equal(1))
+		Expect(report.FailedTests).To(Equal(1))
+		Expect(report.Results[0].Name).To(Equal("signup[0]"))
+		Expect(report.Results[1].Name).To(Equal("signup[1]"))
+		Expect(report.Results[1].Passed).To(BeFalse())
+	})
+
+	It("should skip dependents unless every row passed", func() {
+		test := signup
+		test.Dataset = []map[string]interface{}{{"email": "a@example.com"}, {"email": "invalid"}}
+		dependent := config.APITest{
+			Name:      "list",
+			DependsOn: "signup",
+			Request:   config.RequestConfig{Method: "GET", Path: "/users"},
+			Response:  config.ResponseExpectation{StatusCode: 200},
+		}
+
+		report := run(test, dependent)
+		Expect(report.Results).To(HaveLen(3))
+		Expect(report.Results[2].Skipped).To(BeTrue())
+	})
+
+	It("should leave row placeholders untouched outside a dataset", func() {
+		executor, err := NewExecutor(&config.TestConfig{BaseURL: server.URL})
+		Expect(err).NotTo(HaveOccurred())
+
+		processed := executor.replaceVariables(config.APITest{Request: config.RequestConfig{Path: "/users/{{row.id}}"}})
+		Expect(processed.Request.Path).To(Equal("/users/{{row.id}}"))
+	})
+})
+
+var _ = Describe("Conditional Execution", func() {
+	var (
+		server *httptest.Server
+		paths  []string
+	)
+
+	BeforeEach(func() {
+		paths = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.Method+" "+r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data":{"id":5,"deletable":false,"status":"draft"}}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newExecutor := func(apis ...config.APITest) *Executor {
+		executor, err := NewExecutor(&config.TestConfig{BaseURL: server.URL, APIs: apis})
+		Expect(err).NotTo(HaveOccurred())
+		return executor
+	}
+
+	create := config.APITest{
+		Name:     "create",
+		Weight:   10,
+		Request:  config.RequestConfig{Method: "POST", Path: "/items"},
+		Response: config.ResponseExpectation{StatusCode: 200},
+	}
+
+	DescribeTable("evaluateCondition",
+		func(expr string, expected bool) {
+			executor := newExecutor(create)
+			executor.Execute()
+
+			met, err := executor.evaluateCondition(expr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(met).To(Equal(expected))
+		},
+		Entry("boolean equality", "{{create.response.data.deletable}} == false", true),
+		Entry("boolean mismatch", "{{create.response.data.deletable}} == true", false),
+		Entry("numeric equality", "{{create.response.data.id}} == 5", true),
+		Entry("string inequality", "{{create.response.data.status}} != 'published'", true),
+		Entry("bare truthy reference", "{{create.response.data.id}}", true),
+		Entry("bare falsy reference", "{{create.response.data.deletable}}", false),
+		Entry("missing variable", "{{create.response.data.missing}} == true", false),
+		Entry("operator inside a quoted literal", "{{create.response.data.status}} == 'a==b'", false),
+		Entry("inequality operator inside a quoted literal", `{{create.response.data.status}} != "x != y"`, true),
+		Entry("quoted literal on the left", "'draft' == {{create.response.data.status}}", true),
+		Entry("operator inside a variable default", "{{create.response.data.missing | default: a==b}} == 'a==b'", true),
+	)
+
+	It("should skip the test when the condition is not met", func() {
+		remove := config.APITest{
+			Name:      "delete",
+			DependsOn: "create",
+			When:      "{{create.response.data.deletable}} == true",
+			Request:   config.RequestConfig{Method: "DELETE", Path: "/items/5"},
+			Response:  config.ResponseExpectation{StatusCode: 200},
+		}
+
+		report := newExecutor(create, remove).Execute()
+		Expect(paths).To(Equal([]string{"POST /items"}))
+		Expect(report.SkippedTests).To(Equal(1))
+		Expect(report.Results[1].SkipReason).To(Equal("condition not met: {{create.response.data.deletable}} == true"))
+	})
+
+	It("should run the test when the condition is met", func() {
+		archive := config.APITest{
+			Name:     "archive",
+			When:     "{{create.response.data.status}} == draft",
+			Request:  config.RequestConfig{Method: "POST", Path: "/items/5/archive"},
+			Response: config.ResponseExpectation{StatusCode: 200},
+		}
+
+		report := newExecutor(create, archive).Execute()
+		Expect(report.PassedTests).To(Equal(2))
+		Expect(paths).To(ContainElement("POST /items/5/archive"))
+	})
+
+	It("should fail the test when the condition is malformed", func() {
+		broken := config.APITest{
+			Name:    "broken",
+			When:    "== true",
+			Request: config.RequestConfig{Method: "GET", Path: "/items"},
+		}
+
+		report := newExecutor(broken).Execute()
+		Expect(report.FailedTests).To(Equal(1))
+		Expect(report.Results[0].Error).To(MatchError(ContainSubstring("invalid when condition")))
+	})
+})
+
+var _ = Describe("Dependency-Aware Concurrent Execution", func() {
+	var (
+		server   *httptest.Server
+		mu       sync.Mutex
+		finished map[string]time.Time
+		started  map[string]time.Time
+	)
+
+	BeforeEach(func() {
+		finished = make(map[string]time.Time)
+		started = make(map[string]time.Time)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			started[r.URL.Path] = time.Now()
+			mu.Unlock()
+
+			time.Sleep(30 * time.Millisecond)
+			if r.URL.Path == "/fail" {
+				w.WriteHeader(http.StatusInternalServerError)
+			} else {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"id":1}`))
+			}
+
+			mu.Lock()
+			finished[r.URL.Path] = time.Now()
+			mu.Unlock()
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	api := func(name, dependsOn, path string) config.APITest {
+		return config.APITest{
+			Name:      name,
+			DependsOn: dependsOn,
+			Request:   config.RequestConfig{Method: "GET", Path: path},
+			Response:  config.ResponseExpectation{StatusCode: 200},
+		}
+	}
+
+	run := func(apis ...config.APITest) *TestReport {
+		executor, err := NewExecutor(&config.TestConfig{BaseURL: server.URL, APIs: apis})
+		Expect(err).NotTo(HaveOccurred())
+		return executor.ExecuteConcurrent(4)
+	}
+
+	It("should run dependents only after their prerequisites in a diamond", func() {
+		report := run(
+			api("d", "b", "/d/{{b.response.id}}"),
+			api("b", "a", "/b"),
+			api("c", "a", "/c"),
+			api("a", "", "/a"),
+		)
+
+		Expect(report.PassedTests).To(Equal(4))
+		Expect(started).To(HaveKey("/d/1"))
+		Expect(started["/b"]).To(BeTemporally(">=", finished["/a"]))
+		Expect(started["/c"]).To(BeTemporally(">=", finished["/a"]))
+		Expect(started["/d/1"]).To(BeTemporally(">=", finished["/b"]))
+	})
+
+	It("should run tests of the same level concurrently", func() {
+		run(api("x", "", "/x"), api("y", "", "/y"))
+		Expect(started["/y"]).To(BeTemporally("<", finished["/x"]))
+		Expect(started["/x"]).To(BeTemporally("<", finished["/y"]))
+	})
+
+	It("should skip dependents of a failed test like sequential execution", func() {
+		report := run(api("root", "", "/fail"), api("child", "root", "/child"))
+		Expect(report.FailedTests).To(Equal(1))
+		Expect(report.SkippedTests).To(Equal(1))
+		Expect(started).NotTo(HaveKey("/child"))
+	})
+})
+
+var _ = Describe("Concurrent and Sequential Parity", func() {
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/fail" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newConfig := func() *config.TestConfig {
+		ok := func(name, dependsOn string) config.APITest {
+			return config.APITest{
+				Name:      name,
+				DependsOn: dependsOn,
+				Request:   config.RequestConfig{Method: "GET", Path: "/" + name},
+				Response:  config.ResponseExpectation{StatusCode: 200},
+			}
+		}
+		failing := ok("broken", "")
+		failing.Request.Path = "/fail"
+		disabled := ok("disabled", "")
+		disabled.Disabled = true
+
+		return &config.TestConfig{
+			BaseURL: server.URL,
+			APIs: []config.APITest{
+				ok("list", "create"),
+				ok("create", ""),
+				failing,
+				ok("after-broken", "broken"),
+				disabled,
+				ok("after-disabled", "disabled"),
+				ok("orphan", "missing"),
+				ok("health", ""),
+			},
+		}
+	}
+
+	summarize := func(report *TestReport) []string {
+		names := make([]string, 0, len(report.Results))
+		for _, result := range report.Results {
+			names = append(names, fmt.Sprintf("%s passed=%t skipped=%t", result.Name, result.Passed, result.Skipped))
+		}
+		return names
+	}
+
+	It("should tally and order results identically to Execute", func() {
+		sequentialExecutor, err := NewExecutor(newConfig())
+		Expect(err).NotTo(HaveOccurred())
+		sequential := sequentialExecutor.Execute()
+
+		for i := 0; i < 5; i++ {
+			concurrentExecutor, err := NewExecutor(newConfig())
+			Expect(err).NotTo(HaveOccurred())
+			concurrent := concurrentExecutor.ExecuteConcurrent(8)
+
+			Expect(concurrent.TotalTests).To(Equal(sequential.TotalTests))
+			Expect(concurrent.PassedTests).To(Equal(sequential.PassedTests))
+			Expect(concurrent.FailedTests).To(Equal(sequential.FailedTests))
+			Expect(concurrent.SkippedTests).To(Equal(sequential.SkippedTests))
+			Expect(summarize(concurrent)).To(Equal(summarize(sequential)))
+		}
+
+		Expect(sequential.TotalTests).To(Equal(8))
+		Expect(sequential.PassedTests).To(Equal(3))
+		Expect(sequential.FailedTests).To(Equal(1))
+		Expect(sequential.SkippedTests).To(Equal(4))
+	})
+})
+
+var _ = Describe("executionWaves", func() {
+	It("should group tests by dependency level", func() {
+		order := []config.APITest{
+			{Name: "a"},
+			{Name: "b", DependsOn: "a"},
+			{Name: "c", DependsOn: "a"},
+			{Name: "d", DependsOn: "b"},
+			{Name: "orphan", DependsOn: "missing"},
+		}
+
+		names := make([][]string, 0)
+		for _, wave := range executionWaves(order) {
+			level := make([]string, 0, len(wave))
+			for _, index := range wave {
+				level = append(level, order[index].Name)
+			}
+			names = append(names, level)
+		}
+		Expect(names).To(Equal([][]string{{"a", "orphan"}, {"b", "c"}, {"d"}}))
+	})
+})
+
+var _ = Describe("Think Time", func() {
+	var (
+		server   *httptest.Server
+		requests []time.Time
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, time.Now())
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newTest := func(name string) config.APITest {
+		return config.APITest{
+			Name:     name,
+			Request:  config.RequestConfig{Method: "GET", Path: "/" + name},
+			Response: config.ResponseExpectation{StatusCode: 200},
+		}
+	}
+
+	It("should wait between sequential requests but not before the first", func() {
+		skipped := newTest("skipped")
+		skipped.Disabled = true
+		slow := newTest("slow")
+		slow.Delay = 60 * time.Millisecond
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL:   server.URL,
+			ThinkTime: 30 * time.Millisecond,
+			APIs:      []config.APITest{newTest("first"), skipped, newTest("second"), slow},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		startTime := time.Now()
+		report := executor.Execute()
+		Expect(report.PassedTests).To(Equal(3))
+		Expect(requests).To(HaveLen(3))
+		Expect(requests[0].Sub(startTime)).To(BeNumerically("<", 30*time.Millisecond))
+		Expect(requests[1].Sub(requests[0])).To(BeNumerically(">=", 30*time.Millisecond))
+		Expect(requests[2].Sub(requests[1])).To(BeNumerically(">=", 60*time.Millisecond))
+		Expect(report.ThinkTime).To(Equal(90 * time.Millisecond))
+		for _, result := range report.Results {
+			Expect(result.Duration).To(BeNumerically("<", 30*time.Millisecond))
+		}
+	})
+
+	It("should not wait when running concurrently", func() {
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL:   server.URL,
+			ThinkTime: time.Second,
+			APIs:      []config.APITest{newTest("first"), newTest("second")},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		report := executor.ExecuteConcurrent(1)
+		Expect(report.PassedTests).To(Equal(2))
+		Expect(report.ThinkTime).To(BeZero())
+		Expect(report.Duration).To(BeNumerically("<", time.Second))
+	})
+})
+
+var _ = Describe("Auth Flow", func() {
+	var (
+		server  *httptest.Server
+		mu      sync.Mutex
+		headers map[string]http.Header
+	)
+
+	BeforeEach(func() {
+		headers = make(map[string]http.Header)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			headers[r.URL.Path] = r.Header.Clone()
+			mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Path == "/login" {
+				w.Write([]byte(`{"data": {"token": "abc123"}}`))
+				return
+			}
+			w.Write([]byte(`{}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newTest := func(name string) config.APITest {
+		return config.APITest{
+			Name:     name,
+			Request:  config.RequestConfig{Method: "GET", Path: "/" + name},
+			Response: config.ResponseExpectation{StatusCode: 200},
+		}
+	}
+
+	It("should inject the login token into every other request", func() {
+		override := newTest("override")
+		override.Request.Headers = map[string]string{"authorization": "Basic xyz"}
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL:  server.URL,
+			AuthFlow: &config.AuthFlow{Login: "login", TokenPath: "response.data.token", Prefix: "Bearer "},
+			Setup:    []config.APITest{newTest("login")},
+			APIs:     []config.APITest{newTest("users"), override},
+			Teardown: []config.APITest{newTest("logout")},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		report := executor.Execute()
+		Expect(report.PassedTests).To(Equal(2))
+		Expect(headers["/login"].Get("Authorization")).To(BeEmpty())
+		Expect(headers["/users"].Get("Authorization")).To(Equal("Bearer abc123"))
+		Expect(headers["/override"].Get("Authorization")).To(Equal("Basic xyz"))
+		Expect(headers["/logout"].Get("Authorization")).To(Equal("Bearer abc123"))
+		// 报告中记录的请求不包含 token
+		Expect(report.Results[0].Request.Headers).To(HaveKeyWithValue("Authorization", "[REDACTED]"))
+		Expect(report.TeardownResults[0].Request.Headers).To(HaveKeyWithValue("Authorization", "[REDACTED]"))
+	})
+
+	It("should use the configured header and send requests unchanged before login", func() {
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL:  server.URL,
+			AuthFlow: &config.AuthFlow{Login: "login", TokenPath: "data.token", Header: "X-Token"},
+			APIs:     []config.APITest{newTest("public"), newTest("login"), newTest("orders")},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		report := executor.Execute()
+		Expect(report.PassedTests).To(Equal(3))
+		Expect(headers["/public"].Get("X-Token")).To(BeEmpty())
+		Expect(headers["/orders"].Get("X-Token")).To(Equal("abc123"))
+		Expect(headers["/orders"].Get("Authorization")).To(BeEmpty())
+		Expect(report.Results[2].Request.Headers).To(HaveKeyWithValue("X-Token", "[REDACTED]"))
+	})
+
+	It("should take precedence over a templated global header with the same name", func() {
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL:   server.URL,
+			Headers:   map[string]string{"Authorization": "Bearer {{apiKey}}"},
+			Variables: map[string]interface{}{"apiKey": "anonymous"},
+			AuthFlow:  &config.AuthFlow{Login: "login", TokenPath: "data.token", Prefix: "Bearer "},
+			APIs:      []config.APITest{newTest("public"), newTest("login"), newTest("orders")},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		report := executor.Execute()
+		Expect(report.PassedTests).To(Equal(3))
+		Expect(headers["/public"].Get("Authorization")).To(Equal("Bearer anonymous"))
+		Expect(headers["/orders"].Get("Authorization")).To(Equal("Bearer abc123"))
+	})
+})
+
+var _ = Describe("Global Header Variables", func() {
+	var (
+		server  *httptest.Server
+		headers map[string]http.Header
+	)
+
+	BeforeEach(func() {
+		headers = make(map[string]http.Header)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			headers[r.URL.Path] = r.Header.Clone()
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"token": "t-` + strings.TrimPrefix(r.URL.Path, "/") + `"}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should substitute variables captured during the run before each request", func() {
+		login := config.APITest{
+			Name:     "login",
+			Request:  config.RequestConfig{Method: "POST", Path: "/login"},
+			Response: config.ResponseExpectation{StatusCode: 200},
+			Capture:  map[string]string{"token": "token"},
+		}
+		refresh := config.APITest{
+			Name:      "refresh",
+			DependsOn: "login",
+			Request:   config.RequestConfig{Method: "POST", Path: "/refresh"},
+			Response:  config.ResponseExpectation{StatusCode: 200},
+			Capture:   map[string]string{"token": "token"},
+		}
+		override := config.APITest{
+			Name:      "override",
+			DependsOn: "refresh",
+			Request:   config.RequestConfig{Method: "GET", Path: "/override", Headers: map[string]string{"x-token": "fixed"}},
+			Response:  config.ResponseExpectation{StatusCode: 200},
+		}
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL:   server.URL,
+			Headers:   map[string]string{"X-Token": "{{token}}", "X-Client": "api-test"},
+			Variables: map[string]interface{}{"token": "anonymous"},
+			APIs:      []config.APITest{login, refresh, override},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		report := executor.Execute()
+		Expect(report.PassedTests).To(Equal(3))
+		Expect(headers["/login"].Get("X-Token")).To(Equal("anonymous"))
+		Expect(headers["/refresh"].Get("X-Token")).To(Equal("t-login"))
+		Expect(headers["/override"].Get("X-Token")).To(Equal("fixed"))
+		Expect(headers["/override"].Get("X-Client")).To(Equal("api-test"))
+		Expect(report.Results[1].Request.Headers).To(Equal(map[string]string{"X-Token": "[REDACTED]"}))
+	})
+
+	It("should keep the global auth in charge of Authorization", func() {
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL: server.URL,
+			Headers: map[string]string{"Authorization": "Bearer {{token}}"},
+			Auth:    &config.AuthConfig{Type: "bearer", Token: "global"},
+			APIs: []config.APITest{{
+				Name:     "users",
+				Request:  config.RequestConfig{Method: "GET", Path: "/users"},
+				Response: config.ResponseExpectation{StatusCode: 200},
+			}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(executor.Execute().PassedTests).To(Equal(1))
+		Expect(headers["/users"].Get("Authorization")).To(Equal("Bearer global"))
+	})
+})
+
+var _ = Describe("Fail-Fast", func() {
+	var (
+		server *httptest.Server
+		mu     sync.Mutex
+		paths  []string
+	)
+
+	BeforeEach(func() {
+		paths = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			paths = append(paths, r.URL.Path)
+			mu.Unlock()
+			if r.URL.Path == "/fail" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newExecutor := func(failFast bool) *Executor {
+		api := func(name, path string, weight int) config.APITest {
+			return config.APITest{
+				Name:     name,
+				Weight:   weight,
+				Request:  config.RequestConfig{Method: "GET", Path: path},
+				Response: config.ResponseExpectation{StatusCode: 200},
+			}
+		}
+		disabled := api("disabled", "/disabled", 40)
+		disabled.Disabled = true
+
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL: server.URL,
+			APIs: []config.APITest{
+				api("first", "/first", 50),
+				disabled,
+				api("broken", "/fail", 30),
+				api("later", "/later", 20),
+				api("last", "/last", 10),
+			},
+			Teardown: []config.APITest{api("cleanup", "/cleanup", 0)},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		executor.SetFailFast(failFast)
+		return executor
+	}
+
+	It("should abort remaining tests after the first failure", func() {
+		report := newExecutor(true).Execute()
+
+		Expect(paths).To(Equal([]string{"/first", "/fail", "/cleanup"}))
+		Expect(report.FailedTests).To(Equal(1))
+		Expect(report.SkippedTests).To(Equal(3))
+		Expect(report.AbortedTests).To(Equal(2))
+
+		Expect(report.Results[1].Aborted).To(BeFalse())
+		Expect(report.Results[3].Aborted).To(BeTrue())
+		Expect(report.Results[3].SkipReason).To(Equal("aborted due to fail-fast"))
+	})
+
+	It("should not schedule later waves in concurrent mode", func() {
+		executor := newExecutor(true)
+		executor.config.APIs[3].DependsOn = "first"
+
+		report := executor.ExecuteConcurrent(4)
+		Expect(paths).NotTo(ContainElement("/later"))
+		Expect(report.AbortedTests).To(Equal(1))
+	})
+
+	It("should abort tests waiting in the same wave in concurrent mode", func() {
+		report := newExecutor(true).ExecuteConcurrent(1)
+
+		Expect(paths).To(Equal([]string{"/first", "/fail", "/cleanup"}))
+		Expect(report.FailedTests).To(Equal(1))
+		Expect(report.AbortedTests).To(Equal(2))
+		Expect(report.Results[3].Aborted).To(BeTrue())
+		Expect(report.Results[4].Aborted).To(BeTrue())
+	})
+
+	It("should run everything when disabled", func() {
+		report := newExecutor(false).Execute()
+		Expect(report.AbortedTests).To(BeZero())
+		Expect(paths).To(ContainElement("/last"))
+	})
+})
+
+var _ = Describe("MergeReports", func() {
+	It("should combine counts and tag results with their config file", func() {
+		start := time.Now()
+		first := &TestReport{
+			ConfigFileName: "users.yaml",
+			BaseURL:        "http://a",
+			TotalTests:     2,
+			PassedTests:    1,
+			FailedTests:    1,
+			Results:        []TestResult{{Name: "list"}, {Name: "create"}},
+			StartTime:      start,
+			EndTime:        start.Add(time.Second),
+		}
+		second := &TestReport{
+			ConfigFileName: "orders.yaml",
+			BaseURL:        "http://a",
+			TotalTests:     1,
+			PassedTests:    1,
+			Results:        []TestResult{{Name: "list"}},
+			StartTime:      start.Add(time.Second),
+			EndTime:        start.Add(3 * time.Second),
+		}
+
+		merged := MergeReports("configs", []*TestReport{first, second})
+
+		Expect(merged.ConfigFileName).To(Equal("configs"))
+		Expect(merged.TotalTests).To(Equal(3))
+		Expect(merged.PassedTests).To(Equal(2))
+		Expect(merged.FailedTests).To(Equal(1))
+		Expect(merged.BaseURL).To(Equal("http://a"))
+		Expect(merged.Duration).To(Equal(3 * time.Second))
+		Expect(merged.Results).To(HaveLen(3))
+		Expect(merged.Results[0].ConfigFile).To(Equal("users.yaml"))
+		Expect(merged.Results[2].ConfigFile).To(Equal("orders.yaml"))
+		Expect(first.Results[0].ConfigFile).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Logging", func() {
+	It("should log test progress at debug level", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL: server.URL,
+			APIs: []config.APITest{
+				{Name: "health", Request: config.RequestConfig{Method: "GET", Path: "/health"}, Response: config.ResponseExpectation{StatusCode: 200}},
+				{Name: "legacy", Disabled: true},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		var output strings.Builder
+		executor.SetLogger(logger.New(&output, logger.LevelDebug))
+		executor.Execute()
+
+		Expect(output.String()).To(ContainSubstring("[DEBUG] running test 'health'"))
+		Expect(output.String()).To(ContainSubstring("[DEBUG] GET " + server.URL + "/health"))
+		Expect(output.String()).To(ContainSubstring("(passed: true)"))
+		Expect(output.String()).To(ContainSubstring("skipping test 'legacy'"))
+	})
+})
+
+var _ = Describe("Seeded Random Values", func() {
+	generate := func(seed int64) []string {
+		executor, err := NewExecutor(&config.TestConfig{RandSeed: seed})
+		Expect(err).NotTo(HaveOccurred())
+		values := []string{}
+		for _, expr := range []string{"$random.string.12", "$random.number.8", "$random.uuid", "$random.email", "$random.name"} {
+			values = append(values, executor.generateRandomValue(expr))
+		}
+		return values
+	}
+
+	It("should produce the same values for the same seed", func() {
+		Expect(generate(42)).To(Equal(generate(42)))
+	})
+
+	It("should produce different values for different seeds", func() {
+		Expect(generate(42)).NotTo(Equal(generate(7)))
+	})
+
+	It("should keep using crypto/rand when no seed is set", func() {
+		executor, err := NewExecutor(&config.TestConfig{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(executor.random).To(BeNil())
+		Expect(generate(0)).NotTo(Equal(generate(0)))
+	})
+})
+
+var _ = Describe("Ranged Random Values", func() {
+	var executor *Executor
+
+	BeforeEach(func() {
+		var err error
+		executor, err = NewExecutor(&config.TestConfig{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should generate integers within the inclusive range", func() {
+		seen := map[int64]bool{}
+		for i := 0; i < 200; i++ {
+			value, ok := executor.lookupVariable("$random.int.1.3", nil)
+			Expect(ok).To(BeTrue())
+			Expect(value).To(BeAssignableToTypeOf(int64(0)))
+			Expect(value).To(BeNumerically(">=", 1))
+			Expect(value).To(BeNumerically("<=", 3))
+			seen[value.(int64)] = true
+		}
+		Expect(seen).To(HaveLen(3))
+	})
+
+	It("should support negative bounds and equal bounds", func() {
+		Expect(executor.generateRandomValue("$random.int.-5.-5")).To(Equal("-5"))
+	})
+
+	It("should generate floats within the range", func() {
+		for i := 0; i < 50; i++ {
+			value, ok := executor.lookupVariable("$random.float.0.1", nil)
+			Expect(ok).To(BeTrue())
+			Expect(value).To(BeAssignableToTypeOf(float64(0)))
+			Expect(value).To(BeNumerically(">=", 0))
+			Expect(value).To(BeNumerically("<", 1))
+		}
+	})
+
+	It("should return an empty string for invalid ranges", func() {
+		Expect(executor.generateRandomValue("$random.int.99.18")).To(BeEmpty())
+		Expect(executor.generateRandomValue("$random.int.a.9")).To(BeEmpty())
+		Expect(executor.generateRandomValue("$random.int.5")).To(BeEmpty())
+		Expect(executor.generateRandomValue("$random.float.1.0")).To(BeEmpty())
+		Expect(executor.generateRandomValue("$random.float.x.y")).To(BeEmpty())
+	})
+
+	It("should pick one of the listed choices", func() {
+		seen := map[string]bool{}
+		for i := 0; i < 200; i++ {
+			value := executor.generateRandomValue("$random.choice(北京, 上海,广州,v1.2)")
+			Expect(value).To(BeElementOf("北京", "上海", "广州", "v1.2"))
+			seen[value] = true
+		}
+		Expect(seen).To(HaveLen(4))
+	})
+
+	It("should return an empty string for an empty choice list", func() {
+		Expect(executor.generateRandomValue("$random.choice()")).To(BeEmpty())
+		Expect(executor.generateRandomValue("$random.choice( , )")).To(BeEmpty())
+		Expect(executor.replaceInString("city={{$random.choice()}}", nil)).To(Equal("city={{$random.choice()}}"))
+	})
+
+	It("should keep the integer type in request bodies", func() {
+		processed := executor.replaceVariables(config.APITest{
+			Request: config.RequestConfig{Body: map[string]interface{}{"age": "{{$random.int.18.18}}"}},
+		})
+		Expect(processed.Request.Body).To(Equal(map[string]interface{}{"age": int64(18)}))
+	})
+})
+
+var _ = Describe("Strict Variables", func() {
+	var (
+		server *httptest.Server
+		paths  []string
+	)
+
+	BeforeEach(func() {
+		paths = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			w.WriteHeader(http.StatusOK)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	run := func(strict bool) *TestReport {
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL: server.URL,
+			APIs: []config.APITest{
+				{
+					Name:     "order",
+					Request:  config.RequestConfig{Method: "GET", Path: "/orders/{{login.response.data.id}}"},
+					Response: config.ResponseExpectation{StatusCode: 200},
+				},
+				{
+					Name:     "fallback",
+					Request:  config.RequestConfig{Method: "GET", Path: "/items/{{missing.id | default: 1}}"},
+					Response: config.ResponseExpectation{StatusCode: 200},
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		executor.SetStrictVars(strict)
+		return executor.Execute()
+	}
+
+	It("should send the literal placeholder when disabled", func() {
+		report := run(false)
+		Expect(report.PassedTests).To(Equal(2))
+		Expect(paths).To(ContainElement("/orders/{{login.response.data.id}}"))
+	})
+
+	It("should fail the test and name the missing variable when enabled", func() {
+		report := run(true)
+		Expect(report.FailedTests).To(Equal(1))
+		Expect(report.PassedTests).To(Equal(1))
+		Expect(paths).To(Equal([]string{"/items/1"}))
+
+		Expect(report.Results[0].Name).To(Equal("order"))
+		Expect(report.Results[0].Error).To(MatchError("unresolved variable 'login.response.data.id'"))
+	})
+
+	It("should list every unresolved variable and ignore the host placeholder", func() {
+		executor, err := NewExecutor(&config.TestConfig{})
+		Expect(err).NotTo(HaveOccurred())
+		executor.SetStrictVars(true)
+
+		err = executor.checkUnresolvedReferences(config.RequestConfig{
+			BaseURL: "http://{{host}}",
+			Path:    "/{{b}}",
+			Headers: map[string]string{"X-Token": "{{a}}"},
+			Body:    map[string]interface{}{"ids": []interface{}{"{{b}}"}},
+		})
+		Expect(err).To(MatchError("unresolved variables: a, b"))
+	})
+})
+
+var _ = Describe("Ordered Query Parameters", func() {
+	It("should replace variables in query_params and keep their order", func() {
+		executor, err := NewExecutor(&config.TestConfig{Variables: map[string]interface{}{"app": "demo"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		processed := executor.replaceVariables(config.APITest{
+			Request: config.RequestConfig{
+				QueryParams: []config.QueryParam{
+					{Name: "z", Value: "{{app}}"},
+					{Name: "a", Value: []interface{}{"{{app}}", 2}},
+				},
+			},
+		})
+		Expect(processed.Request.QueryParams).To(Equal([]config.QueryParam{
+			{Name: "z", Value: "demo"},
+			{Name: "a", Value: []interface{}{"demo", 2}},
+		}))
+	})
+})
+
+var _ = Describe("Body File", func() {
+	var (
+		server   *httptest.Server
+		received map[string]interface{}
+		bodyFile string
+	)
+
+	BeforeEach(func() {
+		received = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(json.NewDecoder(r.Body).Decode(&received)).To(Succeed())
+			w.WriteHeader(http.StatusCreated)
+		}))
+		bodyFile = filepath.Join(GinkgoT().TempDir(), "create_user.json")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	run := func(file string) TestReport {
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL:   server.URL,
+			Variables: map[string]interface{}{"name": "tom"},
+			APIs: []config.APITest{
+				{
+					Name: "create user",
+					Request: config.RequestConfig{
+						Method:     "POST",
+						Path:       "/users",
+						Body:       map[string]interface{}{"ignored": true},
+						BodyFile:   file,
+						BodySchema: map[string]string{"age": "int"},
+					},
+					Response: config.ResponseExpectation{StatusCode: 201},
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return *executor.Execute()
+	}
+
+	It("should send the file content with variables replaced and schema types applied", func() {
+		Expect(os.WriteFile(bodyFile, []byte(`{"name": "{{name}}", "age": "30", "tags": ["a"]}`), 0644)).To(Succeed())
+
+		report := run(bodyFile)
+		Expect(report.PassedTests).To(Equal(1))
+		Expect(received).To(Equal(map[string]interface{}{"name": "tom", "age": float64(30), "tags": []interface{}{"a"}}))
+	})
+
+	It("should fail the test without sending a request when the file is missing", func() {
+		report := run(filepath.Join(filepath.Dir(bodyFile), "missing.json"))
+		Expect(report.FailedTests).To(Equal(1))
+		Expect(report.Results[0].Error.Error()).To(HavePrefix("failed to read body file: "))
+		Expect(received).To(BeNil())
+	})
+
+	It("should fail the test when the file is not valid JSON", func() {
+		Expect(os.WriteFile(bodyFile, []byte(`{"name": `), 0644)).To(Succeed())
+
+		report := run(bodyFile)
+		Expect(report.FailedTests).To(Equal(1))
+		Expect(report.Results[0].Error.Error()).To(ContainSubstring("failed to parse body file"))
+	})
+})
+
+var _ = Describe("Update Golden", func() {
+	It("should rewrite the golden file from the actual response and pass", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":2,"name":"jerry"}`))
+		}))
+		defer server.Close()
+
+		goldenFile := filepath.Join(GinkgoT().TempDir(), "user.json")
+		Expect(os.WriteFile(goldenFile, []byte(`{"id":1,"name":"tom"}`), 0644)).To(Succeed())
+
+		newExecutor := func() *Executor {
+			executor, err := NewExecutor(&config.TestConfig{
+				BaseURL: server.URL,
+				APIs: []config.APITest{{
+					Name:     "get user",
+					Request:  config.RequestConfig{Method: "GET", Path: "/users/2"},
+					Response: config.ResponseExpectation{StatusCode: 200, BodyGolden: goldenFile},
+				}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			return executor
+		}
+
+		Expect(newExecutor().Execute().FailedTests).To(Equal(1))
+
+		executor := newExecutor()
+		executor.SetUpdateGolden(true)
+		Expect(executor.Execute().PassedTests).To(Equal(1))
+
+		data, err := os.ReadFile(goldenFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(MatchJSON(`{"id":2,"name":"jerry"}`))
+		Expect(newExecutor().Execute().PassedTests).To(Equal(1))
+	})
+
+	It("should not rewrite the golden file when other assertions fail", func() {
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"boom"}`))
+		}))
+		defer server.Close()
+
+		goldenFile := filepath.Join(GinkgoT().TempDir(), "user.json")
+		Expect(os.WriteFile(goldenFile, []byte(`{"id":1,"name":"tom"}`), 0644)).To(Succeed())
+
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL: server.URL,
+			APIs: []config.APITest{{
+				Name:        "get user",
+				Request:     config.RequestConfig{Method: "GET", Path: "/users/1"},
+				Response:    config.ResponseExpectation{StatusCode: 200, BodyGolden: goldenFile},
+				RetryPolicy: config.RetryPolicy{MaxRetries: 2},
+			}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		executor.SetUpdateGolden(true)
+
+		Expect(executor.Execute().FailedTests).To(Equal(1))
+		Expect(requests).To(Equal(3))
+
+		data, err := os.ReadFile(goldenFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(MatchJSON(`{"id":1,"name":"tom"}`))
+	})
+})
+
+var _ = Describe("GraphQL Request", func() {
+	It("should POST the query and variables with variables replaced", func() {
+		var method string
+		var received map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method = r.Method
+			Expect(json.NewDecoder(r.Body).Decode(&received)).To(Succeed())
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data":{"user":{"name":"tom"}}}`))
+		}))
+		defer server.Close()
+
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL:   server.URL,
+			Variables: map[string]interface{}{"user_id": float64(42), "field": "name"},
+			APIs: []config.APITest{
+				{
+					Name: "get user",
+					Request: config.RequestConfig{
+						Path: "/graphql",
+						GraphQL: &config.GraphQLRequest{
+							Query:     "query GetUser($id: ID!) { user(id: $id) { {{field}} } }",
+							Variables: map[string]interface{}{"id": "{{user_id}}"},
+						},
+					},
+					Response: config.ResponseExpectation{StatusCode: 200, Body: map[string]interface{}{"data.user.name": "tom"}},
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		report := executor.Execute()
+		Expect(report.PassedTests).To(Equal(1))
+		Expect(method).To(Equal(http.MethodPost))
+		Expect(received).To(Equal(map[string]interface{}{
+			"query":     "query GetUser($id: ID!) { user(id: $id) { name } }",
+			"variables": map[string]interface{}{"id": float64(42)},
+		}))
+	})
+})
+
+var _ = Describe("Resolved Request In Results", func() {
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":7}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should store the substituted request for executed tests", func() {
+		GinkgoT().Setenv("API_AUTO_TEST_BASE", server.URL)
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL: "http://unused.invalid",
+			APIs: []config.APITest{
+				{
+					Name:     "create",
+					Request:  config.RequestConfig{Method: "POST", BaseURL: server.URL, Path: "/users"},
+					Response: config.ResponseExpectation{StatusCode: 200},
+				},
+				{
+					Name:      "get",
+					DependsOn: "create",
+					Request: config.RequestConfig{
+						Method:  "GET",
+						BaseURL: "{{$env.API_AUTO_TEST_BASE}}",
+						Path:    "/users/{{create.response.id}}",
+						Query:   map[string]interface{}{"ref": "{{create.response.id}}"},
+						Headers: map[string]string{"X-User": "{{create.response.id}}"},
+					},
+					Response: config.ResponseExpectation{StatusCode: 200},
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		report := executor.Execute()
+		Expect(report.PassedTests).To(Equal(2))
+		request := report.Results[1].Request
+		Expect(request.BaseURL).To(Equal(server.URL))
+		Expect(request.Path).To(Equal("/users/7"))
+		Expect(request.Query).To(HaveKeyWithValue("ref", int64(7)))
+		Expect(request.Headers).To(HaveKeyWithValue("X-User", "7"))
+	})
+
+	It("should keep the {{host}} placeholder in base_url for fan-out", func() {
+		executor, err := NewExecutor(&config.TestConfig{Variables: map[string]interface{}{"port": "8080"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		processed := executor.replaceVariables(config.APITest{
+			Request: config.RequestConfig{BaseURL: "http://{{host}}:{{port}}"},
+		})
+		Expect(processed.Request.BaseURL).To(Equal("http://{{host}}:8080"))
+	})
+})
+
+var _ = Describe("Dry Run", func() {
+	var (
+		server *httptest.Server
+		hits   int
+	)
+
+	BeforeEach(func() {
+		hits = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits++
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should prepare every request without sending and mark the tests as skipped", func() {
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL:   server.URL,
+			Variables: map[string]interface{}{"userId": 7},
+			APIs: []config.APITest{
+				{
+					Name:     "login",
+					Weight:   10,
+					Request:  config.RequestConfig{Method: "POST", Path: "/login", Body: map[string]interface{}{"user": "tom"}},
+					Response: config.ResponseExpectation{StatusCode: 200},
+				},
+				{
+					Name:      "profile",
+					DependsOn: "login",
+					Request:   config.RequestConfig{Method: "GET", Path: "/users/{{userId}}"},
+					Response:  config.ResponseExpectation{StatusCode: 200},
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		executor.SetDryRun(true)
+
+		report := executor.Execute()
+		Expect(hits).To(BeZero())
+		Expect(report.SkippedTests).To(Equal(2))
+
+		login := report.Results[0]
+		Expect(login.SkipReason).To(Equal("dry-run"))
+		Expect(login.Prepared.URL).To(Equal(server.URL + "/login"))
+		Expect(login.Prepared.Body).To(Equal(`{"user":"tom"}`))
+
+		profile := report.Results[1]
+		Expect(profile.SkipReason).To(Equal("dry-run"))
+		Expect(profile.Prepared.URL).To(Equal(server.URL + "/users/7"))
+	})
+
+	It("should fail tests whose request cannot be built", func() {
+		executor, err := NewExecutor(&config.TestConfig{
+			BaseURL: server.URL,
+			APIs: []config.APITest{
+				{
+					Name: "create",
+					Request: config.RequestConfig{
+						Method:     "POST",
+						Path:       "/users",
+						Body:       map[string]interface{}{"age": "abc"},
+						BodySchema: map[string]string{"age": "int"},
+					},
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		executor.SetDryRun(true)
+
+		report := executor.Execute()
+		Expect(hits).To(BeZero())
+		Expect(report.FailedTests).To(Equal(1))
+		Expect(report.Results[0].Error).To(MatchError(ContainSubstring("body schema validation failed")))
+	})
+})
+
+var _ = Describe("MergeIterations", func() {
+	It("should combine iterations, tag results and list flaky tests", func() {
+		start := time.Now()
+		iteration := func(offset time.Duration, loginPassed bool) *TestReport {
+			report := &TestReport{
+				ConfigFileName: "users",
+				StartTime:      start.Add(offset),
+				EndTime:        start.Add(offset + time.Second),
+				SetupResults:   []TestResult{{Name: "seed", Passed: true}},
+			}
+			report.addResult(TestResult{Name: "login", Passed: loginPassed})
+			report.addResult(TestResult{Name: "list", Passed: true})
+			report.addResult(TestResult{Name: "legacy", Skipped: true})
+			return report
+		}
+
+		merged := MergeIterations([]*TestReport{
+			iteration(0, true),
+			iteration(time.Second, false),
+			iteration(2*time.Second, true),
+		})
+
+		Expect(merged.Iterations).To(Equal(3))
+		Expect(merged.ConfigFileName).To(Equal("users"))
+		Expect(merged.TotalTests).To(Equal(9))
+		Expect(merged.PassedTests).To(Equal(5))
+		Expect(merged.FailedTests).To(Equal(1))
+		Expect(merged.SkippedTests).To(Equal(3))
+		Expect(merged.Duration).To(Equal(3 * time.Second))
+		Expect(merged.Results[3].Iteration).To(Equal(2))
+		Expect(merged.SetupResults).To(HaveLen(3))
+		Expect(merged.SetupResults[2].Iteration).To(Equal(3))
+		Expect(merged.FlakyTests).To(Equal([]FlakyTest{{Name: "login", Passed: 2, Failed: 1}}))
+
+		combined := MergeReports("all", []*TestReport{merged})
+		Expect(combined.Iterations).To(Equal(3))
+		Expect(combined.FlakyTests).To(Equal([]FlakyTest{{Name: "login", ConfigFile: "users", Passed: 2, Failed: 1}}))
+	})
+})
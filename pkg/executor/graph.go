@@ -0,0 +1,64 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDOT 将测试依赖关系图以 Graphviz DOT 格式写出
+// 节点按实际执行顺序排列并标注执行序号、权重和标签，边由被依赖的接口指向依赖它的接口
+func (e *Executor) WriteDOT(w io.Writer) error {
+	order, err := e.resolveExecutionOrder(e.config.APIs)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]bool, len(order))
+	for _, api := range order {
+		known[api.Name] = true
+	}
+
+	var sb strings.Builder
+	sb.WriteString("digraph plan {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box];\n")
+
+	for i, api := range order {
+		label := fmt.Sprintf("%d. %s\nweight: %d", i+1, api.Name, api.Weight)
+		if len(api.Tags) > 0 {
+			label += "\ntags: " + strings.Join(api.Tags, ", ")
+		}
+		attrs := []string{"label=" + dotQuote(label)}
+		if isDisabled(api) {
+			attrs = append(attrs, "style=dashed", "fontcolor=gray")
+		}
+		sb.WriteString(fmt.Sprintf("  %s [%s];\n", dotQuote(api.Name), strings.Join(attrs, ", ")))
+	}
+
+	for _, api := range order {
+		if api.DependsOn == "" {
+			continue
+		}
+		// 依赖的接口不存在时单独标出，便于排查配置错误
+		if !known[api.DependsOn] {
+			sb.WriteString(fmt.Sprintf("  %s [label=%s, color=red, fontcolor=red];\n",
+				dotQuote(api.DependsOn), dotQuote(api.DependsOn+"\n(missing)")))
+			known[api.DependsOn] = true
+		}
+		sb.WriteString(fmt.Sprintf("  %s -> %s;\n", dotQuote(api.DependsOn), dotQuote(api.Name)))
+	}
+
+	sb.WriteString("}\n")
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("failed to write graph: %w", err)
+	}
+	return nil
+}
+
+// dotQuote 将字符串转为 DOT 语言的带引号标识符，换行转为 DOT 的 \n
+func dotQuote(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + replacer.Replace(s) + `"`
+}
@@ -0,0 +1,90 @@
+package executor
+
+// FlakyTest 重复执行时结果不稳定的测试
+type FlakyTest struct {
+	Name       string
+	ConfigFile string // 合并多个配置文件的报告时，测试所属的配置文件名称
+	Passed     int    // 通过的轮数
+	Failed     int    // 失败的轮数（不包括跳过）
+}
+
+// MergeIterations 合并重复执行同一配置得到的多轮测试报告
+// 每个结果的 Iteration 记录其所属轮次（从 1 开始），部分轮次通过、部分轮次失败的测试记录在 FlakyTests 中
+func MergeIterations(reports []*TestReport) *TestReport {
+	merged := &TestReport{
+		Results:    make([]TestResult, 0),
+		Iterations: len(reports),
+	}
+	if len(reports) == 0 {
+		return merged
+	}
+	merged.Version = reports[0].Version
+	merged.BaseURL = reports[0].BaseURL
+	merged.ConfigFileName = reports[0].ConfigFileName
+
+	for i, report := range reports {
+		iteration := i + 1
+		merged.TotalTests += report.TotalTests
+		merged.PassedTests += report.PassedTests
+		merged.FailedTests += report.FailedTests
+		merged.SkippedTests += report.SkippedTests
+		merged.AbortedTests += report.AbortedTests
+		merged.FailedHooks += report.FailedHooks
+		merged.ThinkTime += report.ThinkTime
+
+		merged.Results = append(merged.Results, withIteration(report.Results, iteration)...)
+		merged.SetupResults = append(merged.SetupResults, withIteration(report.SetupResults, iteration)...)
+		merged.TeardownResults = append(merged.TeardownResults, withIteration(report.TeardownResults, iteration)...)
+
+		if merged.StartTime.IsZero() || report.StartTime.Before(merged.StartTime) {
+			merged.StartTime = report.StartTime
+		}
+		if report.EndTime.After(merged.EndTime) {
+			merged.EndTime = report.EndTime
+		}
+	}
+
+	merged.Duration = merged.EndTime.Sub(merged.StartTime)
+	merged.FlakyTests = findFlakyTests(merged.Results)
+	return merged
+}
+
+// withIteration 复制结果并标记所属的轮次
+func withIteration(results []TestResult, iteration int) []TestResult {
+	tagged := make([]TestResult, len(results))
+	for i, result := range results {
+		result.Iteration = iteration
+		tagged[i] = result
+	}
+	return tagged
+}
+
+// findFlakyTests 按首次出现的顺序统计每个测试通过和失败的轮数，返回既有通过又有失败的测试
+func findFlakyTests(results []TestResult) []FlakyTest {
+	counts := make(map[string]*FlakyTest)
+	order := make([]string, 0)
+	for _, result := range results {
+		if result.Skipped {
+			continue
+		}
+		count, ok := counts[result.Name]
+		if !ok {
+			count = &FlakyTest{Name: result.Name}
+			counts[result.Name] = count
+			order = append(order, result.Name)
+		}
+		if result.Passed {
+			count.Passed++
+		} else {
+			count.Failed++
+		}
+	}
+
+	flaky := make([]FlakyTest, 0)
+	for _, name := range order {
+		if count := counts[name]; count.Passed > 0 && count.Failed > 0 {
+			flaky = append(flaky, *count)
+		}
+	}
+	return flaky
+}
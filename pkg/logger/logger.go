@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level 日志级别
+type Level int
+
+const (
+	LevelDebug Level = iota // 调试信息，如实际发送的请求
+	LevelInfo               // 进度信息，如开始执行测试、报告保存位置
+	LevelWarn               // 不影响结果的问题，如通知发送失败
+	LevelError              // 错误，如请求发送失败
+)
+
+// levelNames 日志级别名称
+var levelNames = map[Level]string{
+	LevelDebug: "DEBUG",
+	LevelInfo:  "INFO",
+	LevelWarn:  "WARN",
+	LevelError: "ERROR",
+}
+
+// String 返回日志级别名称
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("LEVEL(%d)", int(l))
+}
+
+// ParseLevel 解析日志级别名称（不区分大小写）：debug, info, warn, error
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info", "":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level '%s' (expected debug, info, warn or error)", name)
+}
+
+// Logger 分级日志接口
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// Enabled 判断 l 是否输出 level 级别的日志，用于在构造开销较大的日志内容之前提前返回
+// 没有实现 Enabled(Level) bool 方法的记录器视为输出所有级别
+func Enabled(l Logger, level Level) bool {
+	if checker, ok := l.(interface{ Enabled(Level) bool }); ok {
+		return checker.Enabled(level)
+	}
+	return true
+}
+
+// writerLogger 将不低于指定级别的日志逐行写入 io.Writer
+type writerLogger struct {
+	out   io.Writer
+	level Level
+	now   func() time.Time
+	mu    sync.Mutex
+}
+
+// New 创建日志记录器，只输出不低于 level 的日志
+// 每行格式为 "2006-01-02 15:04:05 [LEVEL] message"
+func New(out io.Writer, level Level) Logger {
+	return &writerLogger{out: out, level: level, now: time.Now}
+}
+
+// Nop 返回丢弃所有日志的记录器
+func Nop() Logger {
+	return New(io.Discard, LevelError+1)
+}
+
+// Enabled 判断是否输出 level 级别的日志
+func (l *writerLogger) Enabled(level Level) bool {
+	return level >= l.level
+}
+
+// Debugf 输出调试日志
+func (l *writerLogger) Debugf(format string, args ...interface{}) {
+	l.log(LevelDebug, format, args...)
+}
+
+// Infof 输出进度信息
+func (l *writerLogger) Infof(format string, args ...interface{}) {
+	l.log(LevelInfo, format, args...)
+}
+
+// Warnf 输出警告
+func (l *writerLogger) Warnf(format string, args ...interface{}) {
+	l.log(LevelWarn, format, args...)
+}
+
+// Errorf 输出错误
+func (l *writerLogger) Errorf(format string, args ...interface{}) {
+	l.log(LevelError, format, args...)
+}
+
+// log 按级别过滤并写入一行日志，并发调用时保证每行完整
+func (l *writerLogger) log(level Level, format string, args ...interface{}) {
+	if level < l.level {
+		return
+	}
+	message := strings.TrimRight(fmt.Sprintf(format, args...), "\n")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintf(l.out, "%s [%s] %s\n", l.now().Format("2006-01-02 15:04:05"), level, message)
+}
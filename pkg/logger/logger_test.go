@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Logger", func() {
+	var output bytes.Buffer
+
+	newLogger := func(level Level) Logger {
+		output.Reset()
+		l := New(&output, level).(*writerLogger)
+		l.now = func() time.Time { return time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC) }
+		return l
+	}
+
+	It("should write messages at or above the configured level", func() {
+		l := newLogger(LevelWarn)
+		l.Debugf("debug %d", 1)
+		l.Infof("info")
+		l.Warnf("disk %s", "low")
+		l.Errorf("failed\n")
+
+		Expect(output.String()).To(Equal(
+			"2024-05-01 08:30:00 [WARN] disk low\n" +
+				"2024-05-01 08:30:00 [ERROR] failed\n"))
+	})
+
+	It("should write everything at debug level", func() {
+		l := newLogger(LevelDebug)
+		l.Debugf("request")
+		Expect(output.String()).To(ContainSubstring("[DEBUG] request"))
+	})
+
+	Describe("Enabled", func() {
+		It("should report whether a level is written", func() {
+			l := newLogger(LevelInfo)
+			Expect(Enabled(l, LevelDebug)).To(BeFalse())
+			Expect(Enabled(l, LevelInfo)).To(BeTrue())
+			Expect(Enabled(Nop(), LevelError)).To(BeFalse())
+		})
+	})
+
+	Describe("ParseLevel", func() {
+		It("should parse level names case-insensitively", func() {
+			Expect(ParseLevel("DEBUG")).To(Equal(LevelDebug))
+			Expect(ParseLevel("info")).To(Equal(LevelInfo))
+			Expect(ParseLevel("")).To(Equal(LevelInfo))
+			Expect(ParseLevel("Warning")).To(Equal(LevelWarn))
+			Expect(ParseLevel("error")).To(Equal(LevelError))
+		})
+
+		It("should reject unknown levels", func() {
+			_, err := ParseLevel("trace")
+			Expect(err).To(MatchError(ContainSubstring("unknown log level 'trace'")))
+		})
+	})
+})
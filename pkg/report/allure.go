@@ -0,0 +1,263 @@
+package report
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"api_auto_test/pkg/executor"
+)
+
+// allureResult Allure 测试结果（*-result.json）
+type allureResult struct {
+	UUID          string              `json:"uuid"`
+	HistoryID     string              `json:"historyId"`
+	Name          string              `json:"name"`
+	FullName      string              `json:"fullName"`
+	Description   string              `json:"description,omitempty"`
+	Status        string              `json:"status"`
+	StatusDetails *allureStatusDetail `json:"statusDetails,omitempty"`
+	Stage         string              `json:"stage"`
+	Start         int64               `json:"start"`
+	Stop          int64               `json:"stop"`
+	Steps         []allureStep        `json:"steps"`
+	Attachments   []allureAttachment  `json:"attachments"`
+	Labels        []allureLabel       `json:"labels"`
+}
+
+// allureStep Allure 测试步骤
+type allureStep struct {
+	Name          string              `json:"name"`
+	Status        string              `json:"status"`
+	StatusDetails *allureStatusDetail `json:"statusDetails,omitempty"`
+	Stage         string              `json:"stage"`
+	Start         int64               `json:"start"`
+	Stop          int64               `json:"stop"`
+}
+
+// allureStatusDetail Allure 状态详情
+type allureStatusDetail struct {
+	Message string `json:"message,omitempty"`
+	Trace   string `json:"trace,omitempty"`
+}
+
+// allureAttachment Allure 附件
+type allureAttachment struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Type   string `json:"type"`
+}
+
+// allureLabel Allure 标签
+type allureLabel struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// SaveAllure 保存为 Allure 结果目录，每个测试生成一个 *-result.json 文件
+func (r *Reporter) SaveAllure(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create allure results directory: %w", err)
+	}
+
+	for _, result := range r.report.Results {
+		if err := r.saveAllureResult(dir, result); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveAllureResult 保存单个测试的 Allure 结果及其附件
+func (r *Reporter) saveAllureResult(dir string, result executor.TestResult) error {
+	start := result.ExecutedAt.UnixMilli()
+	stop := result.ExecutedAt.Add(result.Duration).UnixMilli()
+
+	suite := r.report.ConfigFileName
+	if result.ConfigFile != "" {
+		suite = result.ConfigFile
+	}
+	if suite == "" {
+		suite = "API Test"
+	}
+
+	allure := allureResult{
+		UUID:        newAllureUUID(),
+		HistoryID:   suite + "#" + result.Name,
+		Name:        result.Name,
+		FullName:    suite + "." + result.Name,
+		Description: result.Description,
+		Status:      allureStatus(result),
+		Stage:       "finished",
+		Start:       start,
+		Stop:        stop,
+		Steps:       make([]allureStep, 0),
+		Attachments: make([]allureAttachment, 0),
+		Labels: []allureLabel{
+			{Name: "suite", Value: suite},
+			{Name: "framework", Value: "api_auto_test"},
+		},
+	}
+	if result.Version != "" {
+		allure.Labels = append(allure.Labels, allureLabel{Name: "tag", Value: result.Version})
+	}
+
+	if message := allureMessage(result); message != "" {
+		allure.StatusDetails = &allureStatusDetail{Message: message}
+	}
+
+	if !result.Skipped {
+		allure.Steps = r.allureSteps(result, start, stop)
+
+		// 请求和响应作为附件保存
+		if result.Request.Body != nil {
+			data, _ := json.MarshalIndent(result.Request.Body, "", "  ")
+			attachment, err := saveAllureAttachment(dir, "Request Body", "application/json", data)
+			if err != nil {
+				return err
+			}
+			allure.Attachments = append(allure.Attachments, attachment)
+		}
+		if result.Response != nil && len(result.Response.Body) > 0 {
+			contentType := strings.TrimSpace(strings.Split(result.Response.Headers.Get("Content-Type"), ";")[0])
+			if contentType == "" {
+				contentType = "text/plain"
+			}
+			attachment, err := saveAllureAttachment(dir, "Response Body", contentType, result.Response.Body)
+			if err != nil {
+				return err
+			}
+			allure.Attachments = append(allure.Attachments, attachment)
+		}
+	}
+
+	data, err := json.MarshalIndent(allure, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal allure result: %w", err)
+	}
+
+	filename := filepath.Join(dir, allure.UUID+"-result.json")
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("failed to write allure result file: %w", err)
+	}
+
+	return nil
+}
+
+// allureSteps 生成测试步骤：发送请求，以及每个验证断言
+func (r *Reporter) allureSteps(result executor.TestResult, start, stop int64) []allureStep {
+	requestStep := allureStep{
+		Name:   fmt.Sprintf("%s %s", result.Request.Method, result.Request.Path),
+		Status: "passed",
+		Stage:  "finished",
+		Start:  start,
+		Stop:   stop,
+	}
+	if result.Error != nil {
+		requestStep.Status = "broken"
+		requestStep.StatusDetails = &allureStatusDetail{Message: result.Error.Error()}
+	}
+	steps := []allureStep{requestStep}
+
+	if result.Validation == nil {
+		return steps
+	}
+
+	if result.Validation.Passed {
+		steps = append(steps, allureStep{
+			Name:   "Validate response",
+			Status: "passed",
+			Stage:  "finished",
+			Start:  stop,
+			Stop:   stop,
+		})
+		return steps
+	}
+
+	for _, err := range result.Validation.Errors {
+		steps = append(steps, allureStep{
+			Name:          "Validate " + err.Field,
+			Status:        "failed",
+			StatusDetails: &allureStatusDetail{Message: err.Message},
+			Stage:         "finished",
+			Start:         stop,
+			Stop:          stop,
+		})
+	}
+	return steps
+}
+
+// allureStatus 将测试结果映射为 Allure 状态
+func allureStatus(result executor.TestResult) string {
+	switch {
+	case result.Skipped:
+		return "skipped"
+	case result.Passed:
+		return "passed"
+	case result.Error != nil:
+		return "broken"
+	default:
+		return "failed"
+	}
+}
+
+// allureMessage 生成 Allure 状态详情中的消息
+func allureMessage(result executor.TestResult) string {
+	if result.Skipped {
+		return result.SkipReason
+	}
+
+	messages := make([]string, 0)
+	if result.Error != nil {
+		messages = append(messages, result.Error.Error())
+	}
+	if result.Validation != nil {
+		if !result.Validation.Passed {
+			for _, err := range result.Validation.Errors {
+				messages = append(messages, fmt.Sprintf("%s: %s", err.Field, err.Message))
+			}
+		}
+		for _, warning := range result.Validation.Warnings {
+			messages = append(messages, "warning: "+warning)
+		}
+	}
+	return strings.Join(messages, "\n")
+}
+
+// saveAllureAttachment 保存附件文件并返回附件描述
+func saveAllureAttachment(dir, name, contentType string, data []byte) (allureAttachment, error) {
+	ext := "txt"
+	if strings.Contains(contentType, "json") {
+		ext = "json"
+	} else if strings.Contains(contentType, "xml") {
+		ext = "xml"
+	} else if strings.Contains(contentType, "html") {
+		ext = "html"
+	}
+
+	source := fmt.Sprintf("%s-attachment.%s", newAllureUUID(), ext)
+	if err := os.WriteFile(filepath.Join(dir, source), data, 0644); err != nil {
+		return allureAttachment{}, fmt.Errorf("failed to write allure attachment: %w", err)
+	}
+
+	return allureAttachment{Name: name, Source: source, Type: contentType}, nil
+}
+
+// newAllureUUID 生成 Allure 结果文件使用的 UUID
+func newAllureUUID() string {
+	uuid := make([]byte, 16)
+	rand.Read(uuid)
+	uuid[6] = (uuid[6] & 0x0f) | 0x40
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%s-%s-%s-%s-%s",
+		hex.EncodeToString(uuid[0:4]),
+		hex.EncodeToString(uuid[4:6]),
+		hex.EncodeToString(uuid[6:8]),
+		hex.EncodeToString(uuid[8:10]),
+		hex.EncodeToString(uuid[10:16]))
+}
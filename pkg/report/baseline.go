@@ -0,0 +1,180 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"api_auto_test/pkg/executor"
+)
+
+// 与基线相比的变化类型
+const (
+	ChangeNewlyFailed = "newly failed"
+	ChangeNewlyPassed = "newly passed"
+	ChangeSlower      = "slower"
+)
+
+// slowdownRatio 耗时超过基线的比例达到该值时视为明显变慢
+const slowdownRatio = 0.5
+
+// minSlowdown 耗时增加的最小绝对值，避免毫秒级接口的抖动被误报
+const minSlowdown = 50 * time.Millisecond
+
+// BaselineChange 与基线相比发生变化的测试
+type BaselineChange struct {
+	Name             string
+	ConfigFile       string // 合并多个配置文件的报告时，测试所属的配置文件名称
+	Kind             string
+	BaselineDuration time.Duration
+	CurrentDuration  time.Duration
+}
+
+// baselineResult 基线报告中用于比较的测试结果字段
+// 只解析需要的字段，避免 Error 等接口类型字段无法反序列化
+type baselineResult struct {
+	Name       string
+	ConfigFile string
+	Passed     bool
+	Skipped    bool
+	Duration   time.Duration
+}
+
+// baselineKey 按配置文件和测试名称匹配基线中的结果，不同配置文件中的同名测试分别比较
+type baselineKey struct {
+	configFile string
+	name       string
+}
+
+// baselineReport 通过 -format json 保存的基线报告
+type baselineReport struct {
+	Results []baselineResult
+}
+
+// CompareWithBaseline 加载之前保存的 JSON 报告作为基线，与本次运行进行比较
+// 比较结果会在控制台和 HTML 报告中以 "changed since baseline" 区块展示
+func (r *Reporter) CompareWithBaseline(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read baseline report: %w", err)
+	}
+
+	var baseline baselineReport
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return fmt.Errorf("failed to parse baseline report: %w", err)
+	}
+
+	r.baselineFile = filename
+	r.baselineChanges = compareResults(baseline.Results, r.report.Results)
+	return nil
+}
+
+// compareResults 按配置文件和测试名称比较基线与本次运行的结果，跳过的测试不参与比较
+func compareResults(baseline []baselineResult, current []executor.TestResult) []BaselineChange {
+	previous := make(map[baselineKey]baselineResult, len(baseline))
+	for _, result := range baseline {
+		previous[baselineKey{result.ConfigFile, result.Name}] = result
+	}
+
+	changes := make([]BaselineChange, 0)
+	for _, result := range current {
+		old, ok := previous[baselineKey{result.ConfigFile, result.Name}]
+		if !ok || old.Skipped || result.Skipped {
+			continue
+		}
+
+		change := BaselineChange{
+			Name:             result.Name,
+			ConfigFile:       result.ConfigFile,
+			BaselineDuration: old.Duration,
+			CurrentDuration:  result.Duration,
+		}
+		switch {
+		case old.Passed && !result.Passed:
+			change.Kind = ChangeNewlyFailed
+		case !old.Passed && result.Passed:
+			change.Kind = ChangeNewlyPassed
+		case isSignificantlySlower(old.Duration, result.Duration):
+			change.Kind = ChangeSlower
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// isSignificantlySlower 判断本次耗时是否比基线明显变慢
+func isSignificantlySlower(baseline, current time.Duration) bool {
+	if baseline <= 0 {
+		return false
+	}
+	increase := current - baseline
+	return increase >= minSlowdown && float64(increase) > float64(baseline)*slowdownRatio
+}
+
+// describeChange 生成变化的描述文字
+func describeChange(change BaselineChange) string {
+	name := change.Name
+	if change.ConfigFile != "" {
+		name = change.ConfigFile + " / " + name
+	}
+	if change.Kind == ChangeSlower {
+		ratio := float64(change.CurrentDuration-change.BaselineDuration) / float64(change.BaselineDuration) * 100
+		return fmt.Sprintf("%s: %s (%s → %s, +%.0f%%)", name, change.Kind,
+			change.BaselineDuration, change.CurrentDuration, ratio)
+	}
+	return fmt.Sprintf("%s: %s", name, change.Kind)
+}
+
+// printBaselineChanges 在控制台打印与基线相比的变化
+func (r *Reporter) printBaselineChanges() {
+	if r.baselineFile == "" {
+		return
+	}
+
+	fmt.Printf("\n  Changed since baseline (%s):\n", r.baselineFile)
+	if len(r.baselineChanges) == 0 {
+		fmt.Println("    (no changes)")
+		return
+	}
+	for _, change := range r.baselineChanges {
+		color := colorYellow
+		switch change.Kind {
+		case ChangeNewlyFailed:
+			color = colorRed
+		case ChangeNewlyPassed:
+			color = colorGreen
+		}
+		fmt.Printf("    %s- %s%s\n", color, describeChange(change), colorReset)
+	}
+}
+
+// writeBaselineChangesHTML 生成与基线相比的变化区块
+func (r *Reporter) writeBaselineChangesHTML(sb *strings.Builder) {
+	if r.baselineFile == "" {
+		return
+	}
+
+	sb.WriteString(fmt.Sprintf(`
+                <h2 style="margin-top: 30px; color: #333;">Changed since baseline <small>%s</small></h2>`, r.escapeHTML(r.baselineFile)))
+	if len(r.baselineChanges) == 0 {
+		sb.WriteString(`<p>(no changes)</p>`)
+		return
+	}
+
+	sb.WriteString(`<div class="warning"><ul>`)
+	for _, change := range r.baselineChanges {
+		color := "#FF9800"
+		switch change.Kind {
+		case ChangeNewlyFailed:
+			color = "#f44336"
+		case ChangeNewlyPassed:
+			color = "#4CAF50"
+		}
+		sb.WriteString(fmt.Sprintf(`<li style="color: %s;">%s</li>`, color, r.escapeHTML(describeChange(change))))
+	}
+	sb.WriteString(`</ul></div>`)
+}
@@ -0,0 +1,101 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"api_auto_test/pkg/executor"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Baseline", func() {
+	ginkgo.Describe("compareResults", func() {
+		baseline := []baselineResult{
+			{Name: "login", Passed: true, Duration: 100 * time.Millisecond},
+			{Name: "list", Passed: true, Duration: 100 * time.Millisecond},
+			{Name: "export", Passed: false, Duration: 100 * time.Millisecond},
+			{Name: "search", Passed: true, Duration: 100 * time.Millisecond},
+			{Name: "legacy", Passed: true, Skipped: true},
+			{Name: "removed", Passed: true, Duration: 100 * time.Millisecond},
+		}
+
+		ginkgo.It("should report regressions, fixes and slowdowns", func() {
+			changes := compareResults(baseline, []executor.TestResult{
+				{Name: "login", Passed: false, Duration: 100 * time.Millisecond},
+				{Name: "list", Passed: true, Duration: 200 * time.Millisecond},
+				{Name: "export", Passed: true, Duration: 100 * time.Millisecond},
+				{Name: "search", Passed: true, Duration: 140 * time.Millisecond},
+				{Name: "legacy", Passed: false},
+				{Name: "added", Passed: false, Duration: time.Second},
+			})
+
+			Expect(changes).To(Equal([]BaselineChange{
+				{Name: "login", Kind: ChangeNewlyFailed, BaselineDuration: 100 * time.Millisecond, CurrentDuration: 100 * time.Millisecond},
+				{Name: "list", Kind: ChangeSlower, BaselineDuration: 100 * time.Millisecond, CurrentDuration: 200 * time.Millisecond},
+				{Name: "export", Kind: ChangeNewlyPassed, BaselineDuration: 100 * time.Millisecond, CurrentDuration: 100 * time.Millisecond},
+			}))
+			Expect(describeChange(changes[1])).To(Equal("list: slower (100ms → 200ms, +100%)"))
+		})
+
+		ginkgo.It("should ignore added and removed tests", func() {
+			changes := compareResults(baseline, []executor.TestResult{
+				{Name: "added", Passed: false},
+			})
+			Expect(changes).To(BeEmpty())
+		})
+
+		ginkgo.It("should match tests by config file and name", func() {
+			changes := compareResults([]baselineResult{
+				{Name: "list", ConfigFile: "a/users", Passed: true},
+				{Name: "list", ConfigFile: "b/users", Passed: false},
+			}, []executor.TestResult{
+				{Name: "list", ConfigFile: "a/users", Passed: true},
+				{Name: "list", ConfigFile: "b/users", Passed: false},
+			})
+			Expect(changes).To(BeEmpty())
+
+			changes = compareResults([]baselineResult{
+				{Name: "list", ConfigFile: "a/users", Passed: true},
+				{Name: "list", ConfigFile: "b/users", Passed: true},
+			}, []executor.TestResult{
+				{Name: "list", ConfigFile: "a/users", Passed: true},
+				{Name: "list", ConfigFile: "b/users", Passed: false},
+			})
+			Expect(changes).To(HaveLen(1))
+			Expect(describeChange(changes[0])).To(Equal("b/users / list: newly failed"))
+		})
+
+		ginkgo.It("should ignore small absolute slowdowns", func() {
+			Expect(isSignificantlySlower(10*time.Millisecond, 40*time.Millisecond)).To(BeFalse())
+			Expect(isSignificantlySlower(0, time.Second)).To(BeFalse())
+			Expect(isSignificantlySlower(100*time.Millisecond, 151*time.Millisecond)).To(BeTrue())
+		})
+	})
+
+	ginkgo.Describe("CompareWithBaseline", func() {
+		ginkgo.It("should load a saved JSON report", func() {
+			file := filepath.Join(ginkgo.GinkgoT().TempDir(), "baseline.json")
+			Expect(NewReporter(&executor.TestReport{
+				Results: []executor.TestResult{{Name: "login", Passed: true}},
+			}).SaveJSON(file)).To(Succeed())
+
+			reporter := NewReporter(&executor.TestReport{
+				Results: []executor.TestResult{{Name: "login", Passed: false}},
+			})
+			Expect(reporter.CompareWithBaseline(file)).To(Succeed())
+			Expect(reporter.baselineChanges).To(HaveLen(1))
+			Expect(reporter.baselineChanges[0].Kind).To(Equal(ChangeNewlyFailed))
+		})
+
+		ginkgo.It("should report an invalid baseline file", func() {
+			file := filepath.Join(ginkgo.GinkgoT().TempDir(), "baseline.json")
+			Expect(os.WriteFile(file, []byte("not json"), 0644)).To(Succeed())
+
+			err := NewReporter(&executor.TestReport{}).CompareWithBaseline(file)
+			Expect(err).To(MatchError(ContainSubstring("failed to parse baseline report")))
+		})
+	})
+})
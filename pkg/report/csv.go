@@ -0,0 +1,52 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// csvHeader CSV 报告的表头
+var csvHeader = []string{
+	"name", "method", "path", "status_code", "passed", "skipped", "duration_ms", "retry_count", "executed_at",
+}
+
+// SaveCSV 保存为CSV格式，每个测试结果一行，便于导入表格做趋势分析
+func (r *Reporter) SaveCSV(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, result := range r.report.Results {
+		record := []string{
+			result.Name,
+			result.Request.Method,
+			result.Request.Path,
+			strconv.Itoa(result.StatusCode),
+			strconv.FormatBool(result.Passed),
+			strconv.FormatBool(result.Skipped),
+			strconv.FormatInt(result.Duration.Milliseconds(), 10),
+			strconv.Itoa(result.RetryCount),
+			result.ExecutedAt.Format(time.RFC3339),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package report
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"time"
+
+	"api_auto_test/pkg/config"
+	"api_auto_test/pkg/executor"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("CSV", func() {
+	ginkgo.It("should quote fields containing commas, quotes and newlines", func() {
+		file := filepath.Join(ginkgo.GinkgoT().TempDir(), "report.csv")
+		executedAt := time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)
+		reporter := NewReporter(&executor.TestReport{
+			Results: []executor.TestResult{{
+				Name:       "search \"users\", page 1\nretry",
+				Request:    config.RequestConfig{Method: "GET", Path: "/users?q=a,b"},
+				StatusCode: 200,
+				Passed:     true,
+				Duration:   1500 * time.Microsecond,
+				RetryCount: 1,
+				ExecutedAt: executedAt,
+			}},
+		})
+		Expect(reporter.SaveCSV(file)).To(Succeed())
+
+		data, err := os.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring(`"search ""users"", page 1` + "\n" + `retry",GET,"/users?q=a,b"`))
+
+		f, err := os.Open(file)
+		Expect(err).NotTo(HaveOccurred())
+		defer f.Close()
+		records, err := csv.NewReader(f).ReadAll()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(Equal([][]string{
+			csvHeader,
+			{"search \"users\", page 1\nretry", "GET", "/users?q=a,b", "200", "true", "false", "1", "1", "2024-05-01T08:30:00Z"},
+		}))
+	})
+})
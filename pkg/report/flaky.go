@@ -0,0 +1,53 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"api_auto_test/pkg/executor"
+)
+
+// describeFlakyTest 生成不稳定测试的描述
+func describeFlakyTest(flaky executor.FlakyTest) string {
+	name := flaky.Name
+	if flaky.ConfigFile != "" {
+		name = flaky.ConfigFile + " / " + name
+	}
+	return fmt.Sprintf("%s: passed %d, failed %d of %d iterations", name, flaky.Passed, flaky.Failed, flaky.Passed+flaky.Failed)
+}
+
+// printFlakyTests 在控制台打印重复执行时结果不稳定的测试
+func (r *Reporter) printFlakyTests() {
+	if r.report.Iterations == 0 {
+		return
+	}
+
+	fmt.Printf("\n  Flaky tests across %d iterations:\n", r.report.Iterations)
+	if len(r.report.FlakyTests) == 0 {
+		fmt.Println("    (none)")
+		return
+	}
+	for _, flaky := range r.report.FlakyTests {
+		fmt.Printf("    %s- %s%s\n", colorYellow, describeFlakyTest(flaky), colorReset)
+	}
+}
+
+// writeFlakyTestsHTML 生成重复执行时结果不稳定的测试区块
+func (r *Reporter) writeFlakyTestsHTML(sb *strings.Builder) {
+	if r.report.Iterations == 0 {
+		return
+	}
+
+	sb.WriteString(fmt.Sprintf(`
+                <h2 style="margin-top: 30px; color: #333;">Flaky tests <small>across %d iterations</small></h2>`, r.report.Iterations))
+	if len(r.report.FlakyTests) == 0 {
+		sb.WriteString(`<p>(none)</p>`)
+		return
+	}
+
+	sb.WriteString(`<div class="warning"><ul>`)
+	for _, flaky := range r.report.FlakyTests {
+		sb.WriteString(fmt.Sprintf(`<li>%s</li>`, r.escapeHTML(describeFlakyTest(flaky))))
+	}
+	sb.WriteString(`</ul></div>`)
+}
@@ -0,0 +1,58 @@
+package report
+
+import (
+	"api_auto_test/pkg/executor"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Flaky Tests", func() {
+	iteration := func(results ...executor.TestResult) *executor.TestReport {
+		return &executor.TestReport{Results: results}
+	}
+
+	ginkgo.It("should list tests that both passed and failed across iterations", func() {
+		merged := executor.MergeIterations([]*executor.TestReport{
+			iteration(
+				executor.TestResult{Name: "login", Passed: true},
+				executor.TestResult{Name: "list", Passed: false},
+				executor.TestResult{Name: "export", Skipped: true},
+				executor.TestResult{Name: "search", Passed: false},
+			),
+			iteration(
+				executor.TestResult{Name: "login", Passed: false},
+				executor.TestResult{Name: "list", Passed: false},
+				executor.TestResult{Name: "export", Passed: true},
+				executor.TestResult{Name: "search", Passed: true},
+			),
+			iteration(
+				executor.TestResult{Name: "login", Passed: true},
+				executor.TestResult{Name: "list", Passed: false},
+				executor.TestResult{Name: "export", Passed: true},
+				executor.TestResult{Name: "search", Passed: true},
+			),
+		})
+
+		Expect(merged.Iterations).To(Equal(3))
+		Expect(merged.FlakyTests).To(Equal([]executor.FlakyTest{
+			{Name: "login", Passed: 2, Failed: 1},
+			{Name: "search", Passed: 2, Failed: 1},
+		}))
+		Expect(merged.Results[4].Iteration).To(Equal(2))
+	})
+
+	ginkgo.It("should return no flaky tests for a single iteration", func() {
+		merged := executor.MergeIterations([]*executor.TestReport{
+			iteration(executor.TestResult{Name: "login", Passed: false}),
+		})
+		Expect(merged.FlakyTests).To(BeEmpty())
+	})
+
+	ginkgo.It("should describe flaky tests with their config file", func() {
+		Expect(describeFlakyTest(executor.FlakyTest{Name: "login", Passed: 2, Failed: 1})).
+			To(Equal("login: passed 2, failed 1 of 3 iterations"))
+		Expect(describeFlakyTest(executor.FlakyTest{Name: "login", ConfigFile: "users.yaml", Passed: 1, Failed: 1})).
+			To(Equal("users.yaml / login: passed 1, failed 1 of 2 iterations"))
+	})
+})
@@ -0,0 +1,132 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+
+	"api_auto_test/pkg/executor"
+)
+
+// junitTestSuite JUnit 测试套件
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase JUnit 测试用例
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitMessage JUnit 失败、错误或跳过信息
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Content string `xml:",chardata"`
+}
+
+// SaveJUnit 保存为 JUnit XML 格式，供 Jenkins、GitLab 等 CI 系统展示
+// 请求执行出错记为 <error>，验证失败记为 <failure>，跳过的测试记为 <skipped>
+func (r *Reporter) SaveJUnit(filename string) error {
+	suiteName := r.report.ConfigFileName
+	if suiteName == "" {
+		suiteName = "API Test"
+	}
+
+	suite := junitTestSuite{
+		Name:      suiteName,
+		Tests:     r.report.TotalTests,
+		Skipped:   r.report.SkippedTests,
+		Time:      junitSeconds(r.report.Duration.Seconds()),
+		Timestamp: r.report.StartTime.Format("2006-01-02T15:04:05"),
+		TestCases: make([]junitTestCase, 0, len(r.report.Results)),
+	}
+
+	for _, result := range r.report.Results {
+		className := suiteName
+		if result.ConfigFile != "" {
+			className = result.ConfigFile
+		}
+		testCase := junitTestCase{
+			Name:      result.Name,
+			ClassName: className,
+			Time:      junitSeconds(result.Duration.Seconds()),
+		}
+
+		switch {
+		case result.Skipped:
+			testCase.Skipped = &junitMessage{Message: result.SkipReason}
+		case result.Error != nil:
+			suite.Errors++
+			testCase.Error = &junitMessage{
+				Message: result.Error.Error(),
+				Type:    "RequestError",
+				Content: result.Error.Error(),
+			}
+		case !result.Passed:
+			suite.Failures++
+			message, details := junitFailure(result)
+			testCase.Failure = &junitMessage{
+				Message: message,
+				Type:    "ValidationError",
+				Content: details,
+			}
+		}
+
+		if result.Validation != nil && len(result.Validation.Warnings) > 0 {
+			testCase.SystemOut = "warning: " + strings.Join(result.Validation.Warnings, "\nwarning: ")
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	data, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JUnit report: %w", err)
+	}
+
+	content := append([]byte(xml.Header), data...)
+	if err := os.WriteFile(filename, content, 0644); err != nil {
+		return fmt.Errorf("failed to write JUnit file: %w", err)
+	}
+
+	return nil
+}
+
+// junitFailure 生成验证失败的摘要消息和包含预期值、实际值的详细信息
+func junitFailure(result executor.TestResult) (string, string) {
+	if result.Validation == nil || len(result.Validation.Errors) == 0 {
+		return "test failed", ""
+	}
+
+	messages := make([]string, 0, len(result.Validation.Errors))
+	var details strings.Builder
+	for _, err := range result.Validation.Errors {
+		messages = append(messages, fmt.Sprintf("%s: %s", err.Field, err.Message))
+		details.WriteString(fmt.Sprintf("%s: %s\n", err.Field, err.Message))
+		if err.Expected != nil || err.Actual != nil {
+			details.WriteString(fmt.Sprintf("  Expected: %v\n  Actual:   %v\n", err.Expected, err.Actual))
+		}
+	}
+	return strings.Join(messages, "; "), details.String()
+}
+
+// junitSeconds 将秒数格式化为 JUnit 的 time 属性
+func junitSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
@@ -0,0 +1,58 @@
+package report
+
+import (
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"api_auto_test/pkg/executor"
+	"api_auto_test/pkg/validator"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("JUnit", func() {
+	ginkgo.It("should escape XML special characters and keep results parseable", func() {
+		file := filepath.Join(ginkgo.GinkgoT().TempDir(), "junit.xml")
+		reporter := NewReporter(&executor.TestReport{
+			ConfigFileName: "users.yaml",
+			TotalTests:     3,
+			SkippedTests:   1,
+			Duration:       1500 * time.Millisecond,
+			Results: []executor.TestResult{
+				{
+					Name:     `get "users" <a & b>`,
+					Duration: 250 * time.Millisecond,
+					Validation: &validator.ValidationResult{Errors: []validator.ValidationError{
+						{Field: "Body.name", Expected: "<tom>", Actual: "jerry & co", Message: "mismatch"},
+					}},
+				},
+				{Name: "send", Error: errors.New(`dial "tcp": <refused>`)},
+				{Name: "legacy", Skipped: true, SkipReason: "depends on 'login' & failed"},
+			},
+		})
+		Expect(reporter.SaveJUnit(file)).To(Succeed())
+
+		data, err := os.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring(`name="get &#34;users&#34; &lt;a &amp; b&gt;"`))
+
+		var suite junitTestSuite
+		Expect(xml.Unmarshal(data, &suite)).To(Succeed())
+		Expect(suite.Name).To(Equal("users.yaml"))
+		Expect(suite.Time).To(Equal("1.500"))
+		Expect(suite.Failures).To(Equal(1))
+		Expect(suite.Errors).To(Equal(1))
+		Expect(suite.TestCases).To(HaveLen(3))
+
+		Expect(suite.TestCases[0].Name).To(Equal(`get "users" <a & b>`))
+		Expect(suite.TestCases[0].Time).To(Equal("0.250"))
+		Expect(suite.TestCases[0].Failure.Message).To(Equal("Body.name: mismatch"))
+		Expect(suite.TestCases[0].Failure.Content).To(ContainSubstring("Expected: <tom>\n  Actual:   jerry & co"))
+		Expect(suite.TestCases[1].Error.Message).To(Equal(`dial "tcp": <refused>`))
+		Expect(suite.TestCases[2].Skipped.Message).To(Equal("depends on 'login' & failed"))
+	})
+})
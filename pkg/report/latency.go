@@ -0,0 +1,97 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"api_auto_test/pkg/executor"
+)
+
+// LatencyStats 所有已执行测试的响应时间统计
+type LatencyStats struct {
+	Count int // 参与统计的测试数量（不包括跳过的测试）
+	P50   time.Duration
+	P90   time.Duration
+	P99   time.Duration
+	Max   time.Duration
+}
+
+// NewLatencyStats 根据测试结果的耗时计算响应时间百分位，跳过的测试不参与统计
+func NewLatencyStats(results []executor.TestResult) LatencyStats {
+	durations := make([]time.Duration, 0, len(results))
+	for _, result := range results {
+		if result.Skipped {
+			continue
+		}
+		durations = append(durations, result.Duration)
+	}
+
+	stats := LatencyStats{Count: len(durations)}
+	if len(durations) == 0 {
+		return stats
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	stats.P50 = percentile(durations, 50)
+	stats.P90 = percentile(durations, 90)
+	stats.P99 = percentile(durations, 99)
+	stats.Max = durations[len(durations)-1]
+	return stats
+}
+
+// percentile 使用最近秩法计算已排序耗时的第 p 百分位
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// String 格式化为 "p50=120ms p90=340ms p99=1.2s max=1.5s"，精确到微秒
+func (s LatencyStats) String() string {
+	return fmt.Sprintf("p50=%s p90=%s p99=%s max=%s",
+		roundLatency(s.P50), roundLatency(s.P90), roundLatency(s.P99), roundLatency(s.Max))
+}
+
+// roundLatency 将耗时舍入到微秒，便于阅读
+func roundLatency(d time.Duration) time.Duration {
+	return d.Round(time.Microsecond)
+}
+
+// printLatencyStats 在控制台打印响应时间统计，没有已执行的测试时不打印
+func (r *Reporter) printLatencyStats() {
+	stats := NewLatencyStats(r.report.Results)
+	if stats.Count == 0 {
+		return
+	}
+	fmt.Printf("  Latency:      %s\n", stats)
+}
+
+// writeLatencyStatsHTML 生成响应时间统计的摘要项，没有已执行的测试时为空
+func (r *Reporter) writeLatencyStatsHTML(sb *strings.Builder) {
+	stats := NewLatencyStats(r.report.Results)
+	if stats.Count == 0 {
+		return
+	}
+
+	items := []struct {
+		title string
+		value time.Duration
+	}{
+		{"Latency p50", stats.P50},
+		{"Latency p90", stats.P90},
+		{"Latency p99", stats.P99},
+		{"Latency Max", stats.Max},
+	}
+	for _, item := range items {
+		sb.WriteString(fmt.Sprintf(`
+                    <div class="summary-item">
+                        <h3>%s</h3>
+                        <div class="value">%s</div>
+                    </div>`, item.title, roundLatency(item.value)))
+	}
+}
@@ -0,0 +1,54 @@
+package report
+
+import (
+	"time"
+
+	"api_auto_test/pkg/executor"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Latency", func() {
+	ms := func(values ...int) []time.Duration {
+		durations := make([]time.Duration, len(values))
+		for i, value := range values {
+			durations[i] = time.Duration(value) * time.Millisecond
+		}
+		return durations
+	}
+
+	ginkgo.DescribeTable("percentile uses the nearest rank",
+		func(sorted []time.Duration, p float64, expected time.Duration) {
+			Expect(percentile(sorted, p)).To(Equal(expected))
+		},
+		ginkgo.Entry("single sample p50", ms(7), 50.0, 7*time.Millisecond),
+		ginkgo.Entry("single sample p99", ms(7), 99.0, 7*time.Millisecond),
+		ginkgo.Entry("p0 clamps to the first sample", ms(1, 2, 3), 0.0, time.Millisecond),
+		ginkgo.Entry("p50 of 5", ms(1, 2, 3, 4, 5), 50.0, 3*time.Millisecond),
+		ginkgo.Entry("p90 of 5", ms(1, 2, 3, 4, 5), 90.0, 5*time.Millisecond),
+		ginkgo.Entry("p99 of 5", ms(1, 2, 3, 4, 5), 99.0, 5*time.Millisecond),
+		ginkgo.Entry("p50 of 4", ms(1, 2, 3, 4), 50.0, 2*time.Millisecond),
+		ginkgo.Entry("p90 of 10", ms(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), 90.0, 9*time.Millisecond),
+	)
+
+	ginkgo.It("should sort durations and ignore skipped tests", func() {
+		stats := NewLatencyStats([]executor.TestResult{
+			{Duration: 30 * time.Millisecond},
+			{Duration: 10 * time.Millisecond},
+			{Duration: time.Hour, Skipped: true},
+			{Duration: 20 * time.Millisecond},
+		})
+
+		Expect(stats.Count).To(Equal(3))
+		Expect(stats.P50).To(Equal(20 * time.Millisecond))
+		Expect(stats.P99).To(Equal(30 * time.Millisecond))
+		Expect(stats.Max).To(Equal(30 * time.Millisecond))
+		Expect(stats.String()).To(Equal("p50=20ms p90=30ms p99=30ms max=30ms"))
+	})
+
+	ginkgo.It("should return empty stats without executed tests", func() {
+		stats := NewLatencyStats([]executor.TestResult{{Skipped: true}})
+		Expect(stats).To(Equal(LatencyStats{}))
+	})
+})
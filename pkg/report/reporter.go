@@ -1,17 +1,24 @@
 package report
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 
+	"api_auto_test/pkg/client"
 	"api_auto_test/pkg/executor"
+	"api_auto_test/pkg/validator"
 )
 
 // Reporter 报告生成器
 type Reporter struct {
-	report *executor.TestReport
+	report          *executor.TestReport
+	baselineFile    string           // 基线报告文件，为空时不做比较
+	baselineChanges []BaselineChange // 与基线相比发生变化的测试
 }
 
 // NewReporter 创建报告生成器
@@ -36,19 +43,51 @@ func (r *Reporter) PrintConsole() {
 	fmt.Printf("  Version:      %s\n", r.report.Version)
 	fmt.Printf("  Start Time:   %s\n", r.report.StartTime.Format("2006-01-02 15:04:05"))
 	fmt.Printf("  Duration:     %s\n", r.report.Duration)
+	if r.report.ThinkTime > 0 {
+		fmt.Printf("  Think Time:   %s\n", r.report.ThinkTime)
+	}
+	if r.report.Iterations > 0 {
+		fmt.Printf("  Iterations:   %d\n", r.report.Iterations)
+	}
 	fmt.Printf("  Total Tests:  %d\n", r.report.TotalTests)
 	fmt.Printf("  Passed:       %s%d%s\n", colorGreen, r.report.PassedTests, colorReset)
 	fmt.Printf("  Failed:       %s%d%s\n", colorRed, r.report.FailedTests, colorReset)
 	fmt.Printf("  Skipped:      %s%d%s\n", colorYellow, r.report.SkippedTests, colorReset)
+	if r.report.AbortedTests > 0 {
+		fmt.Printf("  Aborted:      %s%d%s (fail-fast)\n", colorYellow, r.report.AbortedTests, colorReset)
+	}
+	if r.report.FailedHooks > 0 {
+		fmt.Printf("  Failed Hooks: %s%d%s (setup/teardown)\n", colorRed, r.report.FailedHooks, colorReset)
+	}
+	if flaky := r.countFlakyTests(); flaky > 0 {
+		fmt.Printf("  Flaky:        %s%d%s (passed after retries)\n", colorPurple, flaky, colorReset)
+	}
+	if warned := r.countWarnedTests(); warned > 0 {
+		fmt.Printf("  Warnings:     %s%d%s\n", colorYellow, warned, colorReset)
+	}
 	fmt.Printf("  Success Rate: %.2f%%\n", r.getSuccessRate())
+	r.printLatencyStats()
+	r.printBaselineChanges()
+	r.printFlakyTests()
 	fmt.Println(strings.Repeat("=", 80))
 
+	r.printHookResults("Setup", r.report.SetupResults)
+
+	currentFile := ""
 	for i, result := range r.report.Results {
+		// 合并多个配置文件的报告时按文件分组
+		if result.ConfigFile != "" && result.ConfigFile != currentFile {
+			currentFile = result.ConfigFile
+			passed, total := r.countFileResults(currentFile)
+			fmt.Printf("\n%s── %s (%d/%d passed) ──%s\n", colorYellow, currentFile, passed, total, colorReset)
+		}
 		r.printTestResult(i+1, result)
 	}
 
+	r.printHookResults("Teardown", r.report.TeardownResults)
+
 	fmt.Println(strings.Repeat("=", 80))
-	if r.report.PassedTests == r.report.TotalTests {
+	if !r.report.Failed() && r.report.PassedTests == r.report.TotalTests {
 		fmt.Printf("%s  All tests passed! ✓%s\n", colorGreen, colorReset)
 	} else {
 		fmt.Printf("%s  Some tests failed! ✗%s\n", colorRed, colorReset)
@@ -59,37 +98,92 @@ func (r *Reporter) PrintConsole() {
 // printTestResult 打印单个测试结果
 func (r *Reporter) printTestResult(index int, result executor.TestResult) {
 	status := colorGreen + "✓ PASS" + colorReset
-	if result.Skipped {
+	if result.Aborted {
+		status = colorYellow + "⊘ ABORTED" + colorReset
+	} else if result.Skipped {
 		status = colorYellow + "⊘ SKIP" + colorReset
 	} else if !result.Passed {
 		status = colorRed + "✗ FAIL" + colorReset
+	} else if result.Flaky() {
+		status = colorPurple + "~ FLAKY" + colorReset
 	}
 
 	fmt.Printf("\n%s [%d/%d] %s\n", status, index, r.report.TotalTests, result.Name)
 	if result.Description != "" {
 		fmt.Printf("    Description: %s\n", result.Description)
 	}
+	if result.Iteration > 0 {
+		fmt.Printf("    Iteration:   %d\n", result.Iteration)
+	}
 	fmt.Printf("    Method:      %s %s\n", result.Request.Method, result.Request.Path)
+	if result.Response != nil && result.Response.URL != "" {
+		fmt.Printf("    URL:         %s\n", result.Response.URL)
+	}
 
 	// 如果是跳过状态，显示跳过原因
 	if result.Skipped {
 		fmt.Printf("    %sReason: %s%s\n", colorYellow, result.SkipReason, colorReset)
+		if result.Prepared != nil {
+			fmt.Println("    Prepared Request:")
+			for _, line := range strings.Split(formatPreparedRequest(result.Prepared), "\n") {
+				if line == "" {
+					fmt.Println()
+					continue
+				}
+				fmt.Printf("      %s\n", line)
+			}
+		}
 	} else {
 		fmt.Printf("    Status:      %d\n", result.StatusCode)
 		fmt.Printf("    Duration:    %s\n", result.Duration)
 		if result.RetryCount > 0 {
 			fmt.Printf("    Retries:     %d\n", result.RetryCount)
+			if len(result.Attempts) > 0 {
+				fmt.Printf("    Attempts:    %s\n", formatAttempts(result.Attempts))
+			}
+		}
+
+		if len(result.HostResults) > 0 {
+			fmt.Printf("    Hosts:       %d/%d passed\n", countPassedHosts(result.HostResults), len(result.HostResults))
+			for _, host := range result.HostResults {
+				hostStatus := colorGreen + "✓" + colorReset
+				if !host.Passed {
+					hostStatus = colorRed + "✗" + colorReset
+				}
+				fmt.Printf("      %s %s  status=%d  duration=%s\n", hostStatus, host.Host, host.StatusCode, host.Duration)
+				if host.Error != nil {
+					fmt.Printf("        %sError: %s%s\n", colorRed, host.Error.Error(), colorReset)
+				}
+				if host.Validation != nil && !host.Validation.Passed {
+					for _, err := range host.Validation.Errors {
+						fmt.Printf("        - %s: %s\n", err.Field, err.Message)
+					}
+				}
+			}
 		}
 
 		if result.Error != nil {
 			fmt.Printf("    %sError: %s%s\n", colorRed, result.Error.Error(), colorReset)
 		}
 
+		if result.Validation != nil && len(result.Validation.Warnings) > 0 {
+			fmt.Printf("    %sWarnings:%s\n", colorYellow, colorReset)
+			for _, warning := range result.Validation.Warnings {
+				fmt.Printf("      %s- %s%s\n", colorYellow, warning, colorReset)
+			}
+		}
+
 		if result.Validation != nil && !result.Validation.Passed {
 			fmt.Printf("    %sValidation Errors:%s\n", colorYellow, colorReset)
 			for _, err := range result.Validation.Errors {
 				fmt.Printf("      - %s: %s\n", err.Field, err.Message)
-				if err.Expected != nil && err.Actual != nil {
+				if len(err.Diffs) > 0 {
+					for _, diff := range err.Diffs {
+						fmt.Printf("        %s: expected %s%s%s, got %s%s%s\n", diff.Path,
+							colorGreen, formatDiffValue(diff.Expected), colorReset,
+							colorRed, formatDiffValue(diff.Actual), colorReset)
+					}
+				} else if err.Expected != nil && err.Actual != nil {
 					fmt.Printf("        Expected: %v\n", err.Expected)
 					fmt.Printf("        Actual:   %v\n", err.Actual)
 				}
@@ -98,6 +192,50 @@ func (r *Reporter) printTestResult(index int, result executor.TestResult) {
 	}
 }
 
+// formatPreparedRequest 将 dry-run 组装的请求格式化为请求行、按名称排序的请求头和请求体
+func formatPreparedRequest(prepared *client.PreparedRequest) string {
+	lines := []string{prepared.Method + " " + prepared.URL}
+	names := make([]string, 0, len(prepared.Headers))
+	for name := range prepared.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		lines = append(lines, name+": "+prepared.Headers[name])
+	}
+	if prepared.Body != "" {
+		lines = append(lines, "", prepared.Body)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// printHookResults 打印 setup/teardown 接口的执行结果，不计入测试统计
+func (r *Reporter) printHookResults(title string, results []executor.TestResult) {
+	if len(results) == 0 {
+		return
+	}
+
+	fmt.Printf("\n  %s (%d/%d passed)\n", title, countPassedResults(results), len(results))
+	for _, result := range results {
+		status := colorGreen + "✓" + colorReset
+		if result.Skipped {
+			status = colorYellow + "⊘" + colorReset
+		} else if !result.Passed {
+			status = colorRed + "✗" + colorReset
+		}
+		fmt.Printf("    %s %s  %s %s  status=%d  duration=%s\n",
+			status, result.Name, result.Request.Method, result.Request.Path, result.StatusCode, result.Duration)
+		if result.Error != nil {
+			fmt.Printf("      %sError: %s%s\n", colorRed, result.Error.Error(), colorReset)
+		}
+		if result.Validation != nil && !result.Validation.Passed {
+			for _, err := range result.Validation.Errors {
+				fmt.Printf("      - %s: %s\n", err.Field, err.Message)
+			}
+		}
+	}
+}
+
 // SaveJSON 保存为JSON格式
 func (r *Reporter) SaveJSON(filename string) error {
 	data, err := json.MarshalIndent(r.report, "", "  ")
@@ -170,6 +308,27 @@ func (r *Reporter) generateHTML() string {
             font-size: 12px;
             color: #ecf0f1;
         }
+        .status-filter {
+            display: flex;
+            gap: 6px;
+            margin-top: 12px;
+        }
+        .filter-btn {
+            flex: 1;
+            background: #2c3e50;
+            color: #ecf0f1;
+            border: 1px solid #4a6278;
+            padding: 4px 0;
+            border-radius: 3px;
+            cursor: pointer;
+            font-size: 12px;
+        }
+        .filter-btn:hover { background: #3d566e; }
+        .filter-btn.active {
+            background: #4CAF50;
+            border-color: #4CAF50;
+        }
+        .filtered-out { display: none !important; }
         .nav-list {
             list-style: none;
             padding: 10px 0;
@@ -203,6 +362,7 @@ func (r *Reporter) generateHTML() string {
         .nav-status.pass { background: #4CAF50; }
         .nav-status.fail { background: #f44336; }
         .nav-status.skip { background: #FF9800; }
+        .nav-status.flaky { background: #9C27B0; }
         .nav-number {
             color: #95a5a6;
             margin-right: 8px;
@@ -274,6 +434,7 @@ func (r *Reporter) generateHTML() string {
         }
         .test-result.failed { border-left-color: #f44336; }
         .test-result.skipped { border-left-color: #FF9800; }
+        .test-result.flaky { border-left-color: #9C27B0; }
         .test-result h3 {
             margin: 0 0 10px 0;
             color: #333;
@@ -290,6 +451,7 @@ func (r *Reporter) generateHTML() string {
         .test-result .status.pass { background: #4CAF50; }
         .test-result .status.fail { background: #f44336; }
         .test-result .status.skip { background: #FF9800; }
+        .test-result .status.flaky { background: #9C27B0; }
         .test-details {
             margin: 15px 0;
             font-size: 14px;
@@ -310,6 +472,27 @@ func (r *Reporter) generateHTML() string {
             color: #856404;
             border: 1px solid #ffeeba;
         }
+        .warning {
+            background: #fff8e1;
+            padding: 12px;
+            border-radius: 3px;
+            margin: 10px 0;
+            color: #8a6d3b;
+            border: 1px solid #ffe0b2;
+        }
+        .diff-table {
+            border-collapse: collapse;
+            margin: 8px 0;
+            font-family: 'Courier New', monospace;
+            font-size: 13px;
+        }
+        .diff-table th, .diff-table td {
+            border: 1px solid #ffeeba;
+            padding: 4px 10px;
+            text-align: left;
+        }
+        .diff-table td.expected { color: #2e7d32; }
+        .diff-table td.actual { color: #c62828; }
         .success-rate { font-size: 20px; font-weight: bold; }
         .success-rate.high { color: #4CAF50; }
         .success-rate.low { color: #f44336; }
@@ -364,6 +547,16 @@ func (r *Reporter) generateHTML() string {
             }
         }
 
+        // 按状态筛选测试结果和导航项，status 为 all 时显示全部
+        function filterByStatus(status) {
+            document.querySelectorAll('[data-status]').forEach(el => {
+                el.classList.toggle('filtered-out', status !== 'all' && el.dataset.status !== status);
+            });
+            document.querySelectorAll('.filter-btn').forEach(btn => {
+                btn.classList.toggle('active', btn.dataset.filter === status);
+            });
+        }
+
         // 高亮当前激活的导航项
         document.addEventListener('DOMContentLoaded', function() {
             const navLinks = document.querySelectorAll('.nav-link');
@@ -381,6 +574,10 @@ func (r *Reporter) generateHTML() string {
             window.addEventListener('scroll', function() {
                 let current = '';
                 testResults.forEach(result => {
+                    // 被筛选隐藏的结果没有位置信息
+                    if (result.classList.contains('filtered-out')) {
+                        return;
+                    }
                     const rect = result.getBoundingClientRect();
                     if (rect.top <= 100) {
                         current = result.id;
@@ -406,9 +603,15 @@ func (r *Reporter) generateHTML() string {
                 <div class="sidebar-stats">
                     <div>✓ 通过: ` + fmt.Sprintf("%d", r.report.PassedTests) + `</div>
                     <div>✗ 失败: ` + fmt.Sprintf("%d", r.report.FailedTests) + `</div>
-                    <div>⊘ 跳过: ` + fmt.Sprintf("%d", r.report.SkippedTests) + `</div>
+                    <div>⊘ 跳过: ` + fmt.Sprintf("%d", r.report.SkippedTests) + `</div>` + r.abortedStatHTML() + r.flakyStatHTML() + `
                     <div>⏱ 耗时: ` + r.report.Duration.String() + `</div>
                 </div>
+                <div class="status-filter">
+                    <button class="filter-btn active" data-filter="all" onclick="filterByStatus('all')">全部</button>
+                    <button class="filter-btn" data-filter="pass" onclick="filterByStatus('pass')">通过</button>
+                    <button class="filter-btn" data-filter="fail" onclick="filterByStatus('fail')">失败</button>
+                    <button class="filter-btn" data-filter="skip" onclick="filterByStatus('skip')">跳过</button>
+                </div>
             </div>
             <ul class="nav-list">`)
 
@@ -419,17 +622,19 @@ func (r *Reporter) generateHTML() string {
 			statusClass = "skip"
 		} else if !result.Passed {
 			statusClass = "fail"
+		} else if result.Flaky() {
+			statusClass = "flaky"
 		}
 		testID := fmt.Sprintf("test-%d", i)
 		sb.WriteString(fmt.Sprintf(`
-                <li class="nav-item">
+                <li class="nav-item" data-status="%s">
                     <a href="#%s" class="nav-link">
                         <span class="nav-number">#%d</span>
                         <span class="nav-status %s"></span>
                         <span class="nav-text" title="%s">%s</span>
                     </a>
                 </li>`,
-			testID, i+1, statusClass, result.Name, result.Name))
+			filterStatus(result), testID, i+1, statusClass, result.Name, result.Name))
 	}
 
 	sb.WriteString(`
@@ -476,15 +681,45 @@ func (r *Reporter) generateHTML() string {
                     <div class="summary-item">
                         <h3>Start Time</h3>
                         <div class="value" style="font-size: 13px;">` + r.report.StartTime.Format("2006-01-02 15:04:05") + `</div>
-                    </div>
+                    </div>`)
+	if r.report.ThinkTime > 0 {
+		sb.WriteString(`
+                    <div class="summary-item">
+                        <h3>Think Time</h3>
+                        <div class="value">` + r.report.ThinkTime.String() + `</div>
+                    </div>`)
+	}
+	r.writeLatencyStatsHTML(&sb)
+	sb.WriteString(`
                 </div>
+`)
+
+	r.writeBaselineChangesHTML(&sb)
+	r.writeFlakyTestsHTML(&sb)
+	r.writeHookResultsHTML(&sb, "准备接口（Setup）", r.report.SetupResults)
+
+	sb.WriteString(`
                 <h2 style="margin-top: 30px; color: #333;">测试结果详情</h2>`)
 
+	currentFile := ""
 	for i, result := range r.report.Results {
+		// 合并多个配置文件的报告时按文件分组
+		if result.ConfigFile != "" && result.ConfigFile != currentFile {
+			currentFile = result.ConfigFile
+			passed, total := r.countFileResults(currentFile)
+			sb.WriteString(fmt.Sprintf(`
+                <h3 style="margin-top: 24px; color: #555;">📄 %s <small>%d/%d passed</small></h3>`,
+				r.escapeHTML(currentFile), passed, total))
+		}
+
 		statusClass := "pass"
 		statusText := "PASS"
 		resultClass := ""
-		if result.Skipped {
+		if result.Aborted {
+			statusClass = "skip"
+			statusText = "ABORTED"
+			resultClass = "skipped"
+		} else if result.Skipped {
 			statusClass = "skip"
 			statusText = "SKIP"
 			resultClass = "skipped"
@@ -492,29 +727,67 @@ func (r *Reporter) generateHTML() string {
 			statusClass = "fail"
 			statusText = "FAIL"
 			resultClass = "failed"
+		} else if result.Flaky() {
+			statusClass = "flaky"
+			statusText = "FLAKY"
+			resultClass = "flaky"
 		}
 
 		testID := fmt.Sprintf("test-%d", i)
 		sb.WriteString(fmt.Sprintf(`
-        <div id="%s" class="test-result %s">
+        <div id="%s" class="test-result %s" data-status="%s">
             <h3>[%d/%d] %s <span class="status %s">%s</span></h3>`,
-			testID, resultClass, i+1, r.report.TotalTests, result.Name, statusClass, statusText))
+			testID, resultClass, filterStatus(result), i+1, r.report.TotalTests, result.Name, statusClass, statusText))
 
 		if result.Description != "" {
 			sb.WriteString(fmt.Sprintf(`<p>%s</p>`, result.Description))
 		}
 
 		sb.WriteString(`<dl class="test-details">`)
+		if result.Iteration > 0 {
+			sb.WriteString(fmt.Sprintf(`<dt>Iteration:</dt><dd>%d</dd>`, result.Iteration))
+		}
 		sb.WriteString(fmt.Sprintf(`<dt>Request:</dt><dd>%s %s</dd>`, result.Request.Method, result.Request.Path))
+		if result.Response != nil && result.Response.URL != "" {
+			sb.WriteString(fmt.Sprintf(`<dt>URL:</dt><dd>%s</dd>`, r.escapeHTML(result.Response.URL)))
+		}
 
 		// 如果是跳过状态，显示跳过原因
 		if result.Skipped {
 			sb.WriteString(fmt.Sprintf(`<dt>Skip Reason:</dt><dd style="color: #FF9800; font-weight: bold;">%s</dd>`, result.SkipReason))
+			if result.Prepared != nil {
+				sb.WriteString(`<dt>Prepared Request:</dt><dd><pre class="code-block">`)
+				sb.WriteString(r.escapeHTML(formatPreparedRequest(result.Prepared)))
+				sb.WriteString(`</pre></dd>`)
+			}
 		} else {
 			sb.WriteString(fmt.Sprintf(`<dt>Status Code:</dt><dd>%d</dd>`, result.StatusCode))
 			sb.WriteString(fmt.Sprintf(`<dt>Duration:</dt><dd>%s</dd>`, result.Duration))
 			if result.RetryCount > 0 {
 				sb.WriteString(fmt.Sprintf(`<dt>Retries:</dt><dd>%d</dd>`, result.RetryCount))
+				if len(result.Attempts) > 0 {
+					sb.WriteString(fmt.Sprintf(`<dt>Attempts:</dt><dd>%s</dd>`, formatAttempts(result.Attempts)))
+				}
+			}
+			if len(result.HostResults) > 0 {
+				sb.WriteString(fmt.Sprintf(`<dt>Hosts:</dt><dd>%d/%d passed<ul>`, countPassedHosts(result.HostResults), len(result.HostResults)))
+				for _, host := range result.HostResults {
+					hostStatus := `<span style="color: #4CAF50;">✓</span>`
+					if !host.Passed {
+						hostStatus = `<span style="color: #f44336;">✗</span>`
+					}
+					sb.WriteString(fmt.Sprintf(`<li>%s %s — status %d, %s`, hostStatus, r.escapeHTML(host.Host), host.StatusCode, host.Duration))
+					if host.Error != nil {
+						sb.WriteString(fmt.Sprintf(` — %s`, r.escapeHTML(host.Error.Error())))
+					}
+					if host.Validation != nil && !host.Validation.Passed {
+						for _, err := range host.Validation.Errors {
+							sb.WriteString(fmt.Sprintf(`<br>%s: %s`, err.Field, r.escapeHTML(err.Message)))
+						}
+					}
+					sb.WriteString(`</li>`)
+				}
+				sb.WriteString(`</ul></dd>`)
 			}
 		}
 		sb.WriteString(`</dl>`)
@@ -523,10 +796,22 @@ func (r *Reporter) generateHTML() string {
 			sb.WriteString(fmt.Sprintf(`<div class="error">Error: %s</div>`, result.Error.Error()))
 		}
 
+		if result.Validation != nil && len(result.Validation.Warnings) > 0 {
+			sb.WriteString(`<div class="warning"><strong>Warnings:</strong><ul>`)
+			for _, warning := range result.Validation.Warnings {
+				sb.WriteString(fmt.Sprintf(`<li>%s</li>`, r.escapeHTML(warning)))
+			}
+			sb.WriteString(`</ul></div>`)
+		}
+
 		if result.Validation != nil && !result.Validation.Passed {
 			sb.WriteString(`<div class="error"><strong>Validation Errors:</strong><ul>`)
 			for _, err := range result.Validation.Errors {
-				sb.WriteString(fmt.Sprintf(`<li>%s: %s</li>`, err.Field, err.Message))
+				sb.WriteString(fmt.Sprintf(`<li>%s: %s`, err.Field, err.Message))
+				if len(err.Diffs) > 0 {
+					r.writeDiffTableHTML(&sb, err.Diffs)
+				}
+				sb.WriteString(`</li>`)
 			}
 			sb.WriteString(`</ul></div>`)
 		}
@@ -563,6 +848,12 @@ func (r *Reporter) generateHTML() string {
 				sb.WriteString(r.escapeHTML(string(queryJSON)))
 				sb.WriteString(`</pre>`)
 			}
+			if len(result.Request.QueryParams) > 0 {
+				sb.WriteString(`<h4>Ordered Query Parameters:</h4><pre class="code-block">`)
+				paramsJSON, _ := json.MarshalIndent(result.Request.QueryParams, "", "  ")
+				sb.WriteString(r.escapeHTML(string(paramsJSON)))
+				sb.WriteString(`</pre>`)
+			}
 
 			sb.WriteString(`</div></div>`)
 
@@ -588,7 +879,7 @@ func (r *Reporter) generateHTML() string {
 
 				// 响应Body - 默认展开
 				sb.WriteString(`<h4>Body:</h4><pre class="code-block">`)
-				if result.Response.BodyJSON != nil {
+				if result.Response.BodyJSON != nil && !client.IsXMLContentType(result.Response.Headers.Get("Content-Type")) {
 					// 如果是JSON，格式化输出
 					bodyJSON, _ := json.MarshalIndent(result.Response.BodyJSON, "", "  ")
 					sb.WriteString(r.escapeHTML(string(bodyJSON)))
@@ -607,6 +898,8 @@ func (r *Reporter) generateHTML() string {
 		sb.WriteString(`</div>`)
 	}
 
+	r.writeHookResultsHTML(&sb, "清理接口（Teardown）", r.report.TeardownResults)
+
 	sb.WriteString(`
             </div>
         </div>
@@ -617,6 +910,61 @@ func (r *Reporter) generateHTML() string {
 	return sb.String()
 }
 
+// writeHookResultsHTML 生成 setup/teardown 接口的结果区块，不计入测试统计
+func (r *Reporter) writeHookResultsHTML(sb *strings.Builder, title string, results []executor.TestResult) {
+	if len(results) == 0 {
+		return
+	}
+
+	sb.WriteString(fmt.Sprintf(`
+                <h2 style="margin-top: 30px; color: #333;">%s <small>%d/%d passed</small></h2>`,
+		title, countPassedResults(results), len(results)))
+
+	for _, result := range results {
+		statusClass := "pass"
+		statusText := "PASS"
+		resultClass := ""
+		if result.Skipped {
+			statusClass = "skip"
+			statusText = "SKIP"
+			resultClass = "skipped"
+		} else if !result.Passed {
+			statusClass = "fail"
+			statusText = "FAIL"
+			resultClass = "failed"
+		}
+
+		sb.WriteString(fmt.Sprintf(`
+        <div class="test-result %s">
+            <h3>%s <span class="status %s">%s</span></h3>`,
+			resultClass, r.escapeHTML(result.Name), statusClass, statusText))
+		sb.WriteString(`<dl class="test-details">`)
+		sb.WriteString(fmt.Sprintf(`<dt>Request:</dt><dd>%s %s</dd>`, result.Request.Method, r.escapeHTML(result.Request.Path)))
+		if result.Response != nil && result.Response.URL != "" {
+			sb.WriteString(fmt.Sprintf(`<dt>URL:</dt><dd>%s</dd>`, r.escapeHTML(result.Response.URL)))
+		}
+		if result.Skipped {
+			sb.WriteString(fmt.Sprintf(`<dt>Skip Reason:</dt><dd style="color: #FF9800; font-weight: bold;">%s</dd>`, result.SkipReason))
+		} else {
+			sb.WriteString(fmt.Sprintf(`<dt>Status Code:</dt><dd>%d</dd>`, result.StatusCode))
+			sb.WriteString(fmt.Sprintf(`<dt>Duration:</dt><dd>%s</dd>`, result.Duration))
+		}
+		sb.WriteString(`</dl>`)
+
+		if result.Error != nil {
+			sb.WriteString(fmt.Sprintf(`<div class="error">Error: %s</div>`, r.escapeHTML(result.Error.Error())))
+		}
+		if result.Validation != nil && !result.Validation.Passed {
+			sb.WriteString(`<div class="error"><strong>Validation Errors:</strong><ul>`)
+			for _, err := range result.Validation.Errors {
+				sb.WriteString(fmt.Sprintf(`<li>%s: %s</li>`, err.Field, r.escapeHTML(err.Message)))
+			}
+			sb.WriteString(`</ul></div>`)
+		}
+		sb.WriteString(`</div>`)
+	}
+}
+
 // escapeHTML 转义HTML特殊字符
 func (r *Reporter) escapeHTML(s string) string {
 	s = strings.ReplaceAll(s, "&", "&amp;")
@@ -627,6 +975,131 @@ func (r *Reporter) escapeHTML(s string) string {
 	return s
 }
 
+// countWarnedTests 统计带有警告的测试数量
+func (r *Reporter) countWarnedTests() int {
+	warned := 0
+	for _, result := range r.report.Results {
+		if result.Validation != nil && len(result.Validation.Warnings) > 0 {
+			warned++
+		}
+	}
+	return warned
+}
+
+// countFlakyTests 统计经过重试后才通过的测试数量
+func (r *Reporter) countFlakyTests() int {
+	flaky := 0
+	for _, result := range r.report.Results {
+		if result.Flaky() {
+			flaky++
+		}
+	}
+	return flaky
+}
+
+// flakyStatHTML 生成侧边栏中经过重试后才通过的测试数量，没有时为空
+func (r *Reporter) flakyStatHTML() string {
+	flaky := r.countFlakyTests()
+	if flaky == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`
+                    <div>~ 不稳定: %d</div>`, flaky)
+}
+
+// filterStatus 返回 HTML 报告按状态筛选时使用的分类：pass（包括 flaky）、fail、skip（包括 aborted）
+func filterStatus(result executor.TestResult) string {
+	switch {
+	case result.Skipped:
+		return "skip"
+	case !result.Passed:
+		return "fail"
+	default:
+		return "pass"
+	}
+}
+
+// formatDiffValue 将差异值格式化为 JSON，字符串带引号以区分 "1" 和 1
+// 不转义 HTML 字符，HTML 报告输出时统一转义
+func formatDiffValue(value interface{}) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// writeDiffTableHTML 以表格形式列出不一致的字段及其期望值和实际值
+func (r *Reporter) writeDiffTableHTML(sb *strings.Builder, diffs []validator.FieldDiff) {
+	sb.WriteString(`<table class="diff-table"><tr><th>Field</th><th>Expected</th><th>Actual</th></tr>`)
+	for _, diff := range diffs {
+		sb.WriteString(fmt.Sprintf(`<tr><td>%s</td><td class="expected">%s</td><td class="actual">%s</td></tr>`,
+			r.escapeHTML(diff.Path), r.escapeHTML(formatDiffValue(diff.Expected)), r.escapeHTML(formatDiffValue(diff.Actual))))
+	}
+	sb.WriteString(`</table>`)
+}
+
+// formatAttempts 将每次尝试的状态码格式化为 "503 → 503 → 200"，请求发送失败的尝试显示为 error
+func formatAttempts(attempts []int) string {
+	codes := make([]string, len(attempts))
+	for i, code := range attempts {
+		if code == 0 {
+			codes[i] = "error"
+		} else {
+			codes[i] = strconv.Itoa(code)
+		}
+	}
+	return strings.Join(codes, " → ")
+}
+
+// abortedStatHTML 生成侧边栏中因 fail-fast 中止的测试数量，没有中止时为空
+func (r *Reporter) abortedStatHTML() string {
+	if r.report.AbortedTests == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`
+                    <div>⏹ 中止: %d</div>`, r.report.AbortedTests)
+}
+
+// countFileResults 统计指定配置文件中通过的测试数量和测试总数
+func (r *Reporter) countFileResults(file string) (int, int) {
+	passed, total := 0, 0
+	for _, result := range r.report.Results {
+		if result.ConfigFile != file {
+			continue
+		}
+		total++
+		if result.Passed {
+			passed++
+		}
+	}
+	return passed, total
+}
+
+// countPassedResults 统计通过的结果数量
+func countPassedResults(results []executor.TestResult) int {
+	passed := 0
+	for _, result := range results {
+		if result.Passed {
+			passed++
+		}
+	}
+	return passed
+}
+
+// countPassedHosts 统计通过的主机数量
+func countPassedHosts(hosts []executor.HostResult) int {
+	passed := 0
+	for _, host := range hosts {
+		if host.Passed {
+			passed++
+		}
+	}
+	return passed
+}
+
 // getSuccessRate 计算成功率
 func (r *Reporter) getSuccessRate() float64 {
 	if r.report.TotalTests == 0 {
@@ -650,4 +1123,5 @@ const (
 	colorRed    = "\033[31m"
 	colorGreen  = "\033[32m"
 	colorYellow = "\033[33m"
+	colorPurple = "\033[35m"
 )
@@ -0,0 +1,15 @@
+package report
+
+// ginkgo 的 Reporter 与本包的 Reporter 重名，因此不使用点导入
+
+import (
+	"testing"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestReport(t *testing.T) {
+	RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Report Suite")
+}
@@ -0,0 +1,184 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// webhookTimeout 发送通知的超时时间
+const webhookTimeout = 10 * time.Second
+
+// webhookFormatSlack Slack 消息格式
+const webhookFormatSlack = "slack"
+
+// slackSectionLimit Slack section 文本的最大字符数，超过时 Slack 拒绝整条消息
+const slackSectionLimit = 3000
+
+// webhookSummary 通用 JSON 格式的测试摘要
+type webhookSummary struct {
+	ConfigFile   string   `json:"config_file"`
+	BaseURL      string   `json:"base_url"`
+	Version      string   `json:"version"`
+	TotalTests   int      `json:"total_tests"`
+	PassedTests  int      `json:"passed_tests"`
+	FailedTests  int      `json:"failed_tests"`
+	SkippedTests int      `json:"skipped_tests"`
+	SuccessRate  float64  `json:"success_rate"`
+	Duration     string   `json:"duration"`
+	Failed       []string `json:"failed"`
+}
+
+// slackMessage Slack 消息（text 作为通知预览，blocks 为正文）
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+// slackBlock Slack 消息块
+type slackBlock struct {
+	Type string     `json:"type"`
+	Text *slackText `json:"text,omitempty"`
+}
+
+// slackText Slack 文本对象
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// SendWebhook 将测试摘要以 JSON 形式 POST 到指定地址
+// format 为空时发送通用摘要，为 slack 时发送 Slack 的 text/blocks 格式；非 2xx 响应返回错误
+// webhook 地址（如 Slack incoming webhook）本身就是凭据，返回的错误中只包含其协议和主机
+func (r *Reporter) SendWebhook(target, format string) error {
+	var payload interface{}
+	switch format {
+	case "", "json":
+		payload = r.webhookSummary()
+	case webhookFormatSlack:
+		payload = r.slackMessage()
+	default:
+		return fmt.Errorf("unknown webhook format: %s", format)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	httpClient := &http.Client{Timeout: webhookTimeout}
+	resp, err := httpClient.Post(target, "application/json", bytes.NewReader(data))
+	if err != nil {
+		// *url.Error 的错误信息包含完整地址，只保留底层错误
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to send webhook to %s: %w", WebhookHost(target), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook %s returned status %d: %s", WebhookHost(target), resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+// WebhookHost 返回 webhook 地址的协议和主机部分，用于日志和错误信息，避免泄露路径中的令牌
+func WebhookHost(target string) string {
+	parsed, err := url.Parse(target)
+	if err != nil || parsed.Host == "" {
+		return "<invalid webhook URL>"
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
+
+// webhookSummary 生成通用 JSON 摘要
+func (r *Reporter) webhookSummary() webhookSummary {
+	return webhookSummary{
+		ConfigFile:   r.report.ConfigFileName,
+		BaseURL:      r.report.BaseURL,
+		Version:      r.report.Version,
+		TotalTests:   r.report.TotalTests,
+		PassedTests:  r.report.PassedTests,
+		FailedTests:  r.report.FailedTests,
+		SkippedTests: r.report.SkippedTests,
+		SuccessRate:  r.getSuccessRate(),
+		Duration:     r.report.Duration.String(),
+		Failed:       r.failedTestNames(),
+	}
+}
+
+// slackMessage 生成 Slack 格式的消息
+func (r *Reporter) slackMessage() slackMessage {
+	title := "API Test Report"
+	if r.report.ConfigFileName != "" {
+		title = r.report.ConfigFileName + " " + title
+	}
+
+	status := "✅ All tests passed"
+	if r.report.FailedTests > 0 {
+		status = fmt.Sprintf("❌ %d test(s) failed", r.report.FailedTests)
+	} else if r.report.FailedHooks > 0 {
+		status = fmt.Sprintf("❌ %d setup/teardown hook(s) failed", r.report.FailedHooks)
+	}
+
+	text := fmt.Sprintf("%s: %s", title, status)
+	summary := fmt.Sprintf("*%s*\n%s\nTotal: %d  Passed: %d  Failed: %d  Skipped: %d  Success Rate: %.2f%%  Duration: %s",
+		title, status, r.report.TotalTests, r.report.PassedTests, r.report.FailedTests,
+		r.report.SkippedTests, r.getSuccessRate(), r.report.Duration)
+
+	message := slackMessage{
+		Text: text,
+		Blocks: []slackBlock{
+			{Type: "section", Text: &slackText{Type: "mrkdwn", Text: summary}},
+		},
+	}
+
+	if failed := r.failedTestNames(); len(failed) > 0 {
+		message.Blocks = append(message.Blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: slackFailedList(failed)},
+		})
+	}
+
+	return message
+}
+
+// slackFailedList 生成失败测试列表，超过 Slack section 的长度限制时截断并注明剩余的数量
+func slackFailedList(names []string) string {
+	text := "*Failed tests:*"
+	for i, name := range names {
+		line := "\n• " + name
+		// 不是最后一个名称时需要为 "…and N more" 预留空间
+		reserve := 0
+		if i < len(names)-1 {
+			reserve = utf8.RuneCountInString(fmt.Sprintf("\n…and %d more", len(names)-i-1))
+		}
+		if utf8.RuneCountInString(text)+utf8.RuneCountInString(line)+reserve > slackSectionLimit {
+			return text + fmt.Sprintf("\n…and %d more", len(names)-i)
+		}
+		text += line
+	}
+	return text
+}
+
+// failedTestNames 返回失败测试的名称（不包括跳过的测试）
+func (r *Reporter) failedTestNames() []string {
+	names := make([]string, 0)
+	for _, result := range r.report.Results {
+		if !result.Passed && !result.Skipped {
+			names = append(names, result.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,72 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"unicode/utf8"
+
+	"api_auto_test/pkg/executor"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Webhook", func() {
+	ginkgo.Describe("WebhookHost", func() {
+		ginkgo.It("should keep only the scheme and host", func() {
+			Expect(WebhookHost("https://hooks.slack.com/services/T000/B000/secret")).To(Equal("https://hooks.slack.com"))
+		})
+
+		ginkgo.It("should not echo an unparsable URL", func() {
+			Expect(WebhookHost("://secret")).To(Equal("<invalid webhook URL>"))
+		})
+	})
+
+	ginkgo.Describe("SendWebhook", func() {
+		ginkgo.It("should not include the URL path in the error", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			target := server.URL + "/services/secret-token"
+			server.Close()
+
+			reporter := NewReporter(&executor.TestReport{})
+			err := reporter.SendWebhook(target, "")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(server.URL))
+			Expect(err.Error()).NotTo(ContainSubstring("secret-token"))
+		})
+
+		ginkgo.It("should truncate the Slack failed test list", func() {
+			var message slackMessage
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(json.NewDecoder(r.Body).Decode(&message)).To(Succeed())
+			}))
+			defer server.Close()
+
+			results := make([]executor.TestResult, 500)
+			for i := range results {
+				results[i] = executor.TestResult{Name: fmt.Sprintf("failing test number %d", i)}
+			}
+			reporter := NewReporter(&executor.TestReport{TotalTests: 500, FailedTests: 500, Results: results})
+			Expect(reporter.SendWebhook(server.URL, "slack")).To(Succeed())
+
+			section := message.Blocks[len(message.Blocks)-1].Text.Text
+			Expect(utf8.RuneCountInString(section)).To(BeNumerically("<=", slackSectionLimit))
+			Expect(section).To(ContainSubstring("failing test number 0"))
+			Expect(section).To(MatchRegexp(`…and \d+ more$`))
+		})
+	})
+
+	ginkgo.Describe("slackFailedList", func() {
+		ginkgo.It("should list every name when the text fits", func() {
+			Expect(slackFailedList([]string{"a", "b"})).To(Equal("*Failed tests:*\n• a\n• b"))
+		})
+
+		ginkgo.It("should count the names left out", func() {
+			long := strings.Repeat("x", 2000)
+			Expect(slackFailedList([]string{long, long, long})).To(Equal("*Failed tests:*\n• " + long + "\n…and 2 more"))
+		})
+	})
+})
@@ -0,0 +1,118 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FieldDiff 字段差异
+type FieldDiff struct {
+	Path     string
+	Expected interface{}
+	Actual   interface{}
+}
+
+// DiffValues 递归比较两个 JSON 值，返回所有不一致的字段路径
+// ignoreFields 中的路径（及其子路径）不参与比较，如 "data.created_at"
+func DiffValues(expected, actual interface{}, ignoreFields []string) []FieldDiff {
+	diffs := make([]FieldDiff, 0)
+	diffValues("", expected, actual, ignoreFields, &diffs)
+	return diffs
+}
+
+// diffNested 比较同为对象或同为数组的期望值和实际值，差异路径以 path 为前缀
+// 类型不同或不是对象、数组时返回 nil
+func diffNested(path string, expected, actual interface{}) []FieldDiff {
+	switch expected.(type) {
+	case map[string]interface{}:
+		if _, ok := actual.(map[string]interface{}); !ok {
+			return nil
+		}
+	case []interface{}:
+		if _, ok := actual.([]interface{}); !ok {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	diffs := make([]FieldDiff, 0)
+	diffValues(path, expected, actual, nil, &diffs)
+	return diffs
+}
+
+// diffValues 递归比较并收集差异
+func diffValues(path string, expected, actual interface{}, ignoreFields []string, diffs *[]FieldDiff) {
+	if isIgnoredPath(path, ignoreFields) {
+		return
+	}
+
+	switch exp := expected.(type) {
+	case map[string]interface{}:
+		act, ok := actual.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		// 合并两侧的键并排序，保证差异输出顺序稳定
+		keySet := make(map[string]struct{})
+		for k := range exp {
+			keySet[k] = struct{}{}
+		}
+		for k := range act {
+			keySet[k] = struct{}{}
+		}
+		keys := make([]string, 0, len(keySet))
+		for k := range keySet {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			childPath := k
+			if path != "" {
+				childPath = path + "." + k
+			}
+			expValue, expExists := exp[k]
+			actValue, actExists := act[k]
+			if expExists != actExists {
+				if !isIgnoredPath(childPath, ignoreFields) {
+					*diffs = append(*diffs, FieldDiff{Path: childPath, Expected: expValue, Actual: actValue})
+				}
+				continue
+			}
+			diffValues(childPath, expValue, actValue, ignoreFields, diffs)
+		}
+		return
+
+	case []interface{}:
+		act, ok := actual.([]interface{})
+		if !ok || len(exp) != len(act) {
+			break
+		}
+		for i := range exp {
+			diffValues(fmt.Sprintf("%s[%d]", path, i), exp[i], act[i], ignoreFields, diffs)
+		}
+		return
+	}
+
+	if !compareValues(expected, actual) {
+		*diffs = append(*diffs, FieldDiff{Path: path, Expected: expected, Actual: actual})
+	}
+}
+
+// isIgnoredPath 判断路径是否在忽略列表中（包括其子路径）
+func isIgnoredPath(path string, ignoreFields []string) bool {
+	if path == "" {
+		return false
+	}
+	for _, ignore := range ignoreFields {
+		if path == ignore ||
+			strings.HasPrefix(path, ignore+".") ||
+			strings.HasPrefix(path, ignore+"[") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"api_auto_test/pkg/client"
+)
+
+// validateBodyGolden 将响应体与 body_golden 文件中的 JSON 比较，ignore_fields 中的路径不参与比较
+// 不一致时报告每个字段的差异
+func (v *Validator) validateBodyGolden(resp *client.Response, result *ValidationResult) {
+	path := v.expectation.BodyGolden
+	if path == "" {
+		return
+	}
+
+	golden, err := loadGolden(path)
+	if err != nil {
+		result.Passed = false
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "BodyGolden",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	actual, err := parseResponseJSON(resp)
+	if err != nil {
+		result.Passed = false
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "Body",
+			Message: "Expected JSON response for golden file comparison, but got non-JSON content",
+		})
+		return
+	}
+
+	diffs := DiffValues(golden, actual, v.expectation.IgnoreFields)
+	if len(diffs) == 0 {
+		return
+	}
+	result.Passed = false
+	result.Errors = append(result.Errors, ValidationError{
+		Field:   "BodyGolden",
+		Message: fmt.Sprintf("Response body differs from golden file '%s': %d field(s) differ", path, len(diffs)),
+		Diffs:   diffs,
+	})
+}
+
+// loadGolden 读取并解析 golden 文件
+func loadGolden(path string) (interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read golden file: %w", err)
+	}
+	var golden interface{}
+	if err := json.Unmarshal(data, &golden); err != nil {
+		return nil, fmt.Errorf("failed to parse golden file '%s': %w", path, err)
+	}
+	return golden, nil
+}
+
+// parseResponseJSON 将响应体解析为任意 JSON 值（对象、数组或标量），忽略开头的 BOM 和空白
+func parseResponseJSON(resp *client.Response) (interface{}, error) {
+	var body interface{}
+	if err := json.Unmarshal(bytes.TrimLeft(resp.Body, "\ufeff \t\r\n"), &body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// UpdateGolden 将响应体格式化后写入 golden 文件，用于 -update-golden
+func UpdateGolden(path string, resp *client.Response) error {
+	body, err := parseResponseJSON(resp)
+	if err != nil {
+		return fmt.Errorf("failed to update golden file '%s': response body is not valid JSON: %w", path, err)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(body); err != nil {
+		return fmt.Errorf("failed to update golden file '%s': %w", path, err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to update golden file: %w", err)
+	}
+	return nil
+}
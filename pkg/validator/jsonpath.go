@@ -0,0 +1,392 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPathSegmentKind JSONPath 路径段类型
+type jsonPathSegmentKind int
+
+const (
+	segmentKey       jsonPathSegmentKind = iota // .key 或 ['key']
+	segmentIndex                                // [0]、[-1]
+	segmentWildcard                             // .* 或 [*]
+	segmentRecursive                            // ..key 或 ..*
+	segmentFilter                               // [?(@.field == value)]
+)
+
+// jsonPathSegment JSONPath 中的一个路径段
+type jsonPathSegment struct {
+	kind   jsonPathSegmentKind
+	key    string
+	index  int
+	filter *jsonPathFilter
+}
+
+// jsonPathFilter 过滤表达式，如 @.status == 'active'，operator 为空时只判断字段是否存在
+type jsonPathFilter struct {
+	path     string
+	operator string
+	value    interface{}
+}
+
+// jsonPathFilterOperators 过滤表达式支持的运算符（较长的运算符需排在前面）
+var jsonPathFilterOperators = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+// isJSONPath 判断字段是否为 JSONPath 表达式（以 $ 开头）
+func isJSONPath(field string) bool {
+	return strings.HasPrefix(field, "$")
+}
+
+// queryJSONPath 按 JSONPath 表达式查询 JSON 数据
+// 只包含键和下标的确定路径返回单个值；包含通配符、递归或过滤的路径返回所有匹配值组成的数组
+// 没有任何匹配时返回 nil
+func queryJSONPath(data interface{}, path string) (interface{}, error) {
+	matches, definite, err := matchJSONPath(data, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	if definite {
+		return matches[0], nil
+	}
+	return matches, nil
+}
+
+// matchJSONPath 返回 JSONPath 表达式的所有匹配值，以及路径是否只包含键和下标
+// 值为 null 的字段也算作匹配，可用于区分字段缺失和字段值为 null
+func matchJSONPath(data interface{}, path string) ([]interface{}, bool, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	definite := true
+	current := []interface{}{data}
+	for _, segment := range segments {
+		if segment.kind != segmentKey && segment.kind != segmentIndex {
+			definite = false
+		}
+		current = applyJSONPathSegment(segment, current)
+	}
+	return current, definite, nil
+}
+
+// parseJSONPath 将 JSONPath 表达式解析为路径段
+func parseJSONPath(path string) ([]jsonPathSegment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("JSONPath must start with '$'")
+	}
+
+	segments := make([]jsonPathSegment, 0)
+	rest := path[1:]
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			name, remaining := readJSONPathName(rest[2:])
+			if name == "" {
+				return nil, fmt.Errorf("missing name after '..' in '%s'", path)
+			}
+			segments = append(segments, jsonPathSegment{kind: segmentRecursive, key: name})
+			rest = remaining
+
+		case rest[0] == '.':
+			name, remaining := readJSONPathName(rest[1:])
+			if name == "" {
+				return nil, fmt.Errorf("missing name after '.' in '%s'", path)
+			}
+			if name == "*" {
+				segments = append(segments, jsonPathSegment{kind: segmentWildcard})
+			} else {
+				segments = append(segments, jsonPathSegment{kind: segmentKey, key: name})
+			}
+			rest = remaining
+
+		case rest[0] == '[':
+			end := findClosingBracket(rest)
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '[' in '%s'", path)
+			}
+			segment, err := parseJSONPathBracket(strings.TrimSpace(rest[1:end]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid segment '%s' in '%s': %w", rest[:end+1], path, err)
+			}
+			segments = append(segments, segment)
+			rest = rest[end+1:]
+
+		default:
+			return nil, fmt.Errorf("unexpected character '%c' in '%s'", rest[0], path)
+		}
+	}
+
+	return segments, nil
+}
+
+// readJSONPathName 读取点号后的字段名，直到下一个 '.' 或 '['
+func readJSONPathName(s string) (string, string) {
+	end := strings.IndexAny(s, ".[")
+	if end < 0 {
+		return s, ""
+	}
+	return s[:end], s[end:]
+}
+
+// findClosingBracket 查找与开头 '[' 匹配的 ']'，忽略引号内的字符
+func findClosingBracket(s string) int {
+	var quote byte
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"':
+			quote = c
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// parseJSONPathBracket 解析方括号内的内容：*、下标、带引号的键名或过滤表达式
+func parseJSONPathBracket(content string) (jsonPathSegment, error) {
+	switch {
+	case content == "*":
+		return jsonPathSegment{kind: segmentWildcard}, nil
+
+	case strings.HasPrefix(content, "?(") && strings.HasSuffix(content, ")"):
+		filter, err := parseJSONPathFilter(strings.TrimSpace(content[2 : len(content)-1]))
+		if err != nil {
+			return jsonPathSegment{}, err
+		}
+		return jsonPathSegment{kind: segmentFilter, filter: filter}, nil
+
+	case isQuoted(content):
+		return jsonPathSegment{kind: segmentKey, key: content[1 : len(content)-1]}, nil
+	}
+
+	index, err := strconv.Atoi(content)
+	if err != nil {
+		return jsonPathSegment{}, fmt.Errorf("expected index, quoted key, '*' or filter")
+	}
+	return jsonPathSegment{kind: segmentIndex, index: index}, nil
+}
+
+// parseJSONPathFilter 解析过滤表达式，如 @.price > 10、@.name == 'foo'、@.id
+func parseJSONPathFilter(expr string) (*jsonPathFilter, error) {
+	if idx, op := findFilterOperator(expr); idx >= 0 {
+		left := strings.TrimSpace(expr[:idx])
+		right := strings.TrimSpace(expr[idx+len(op):])
+		path, err := parseFilterPath(left)
+		if err != nil {
+			return nil, err
+		}
+		value, err := parseFilterLiteral(right)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathFilter{path: path, operator: op, value: value}, nil
+	}
+
+	path, err := parseFilterPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonPathFilter{path: path}, nil
+}
+
+// findFilterOperator 从左向右查找第一个不在引号内的运算符，返回其位置和运算符，未找到时返回 -1
+func findFilterOperator(expr string) (int, string) {
+	var quote byte
+	for i := 0; i < len(expr); i++ {
+		switch {
+		case quote != 0:
+			if expr[i] == quote {
+				quote = 0
+			}
+			continue
+		case expr[i] == '\'' || expr[i] == '"':
+			quote = expr[i]
+			continue
+		}
+		for _, op := range jsonPathFilterOperators {
+			if strings.HasPrefix(expr[i:], op) {
+				return i, op
+			}
+		}
+	}
+	return -1, ""
+}
+
+// parseFilterPath 解析过滤表达式中以 @ 开头的相对路径
+func parseFilterPath(expr string) (string, error) {
+	if expr == "@" {
+		return "", nil
+	}
+	if !strings.HasPrefix(expr, "@.") {
+		return "", fmt.Errorf("filter must reference the current element with '@', got '%s'", expr)
+	}
+	return expr[2:], nil
+}
+
+// parseFilterLiteral 解析过滤表达式右侧的字面量
+func parseFilterLiteral(expr string) (interface{}, error) {
+	if isQuoted(expr) {
+		return expr[1 : len(expr)-1], nil
+	}
+	switch expr {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	number, err := strconv.ParseFloat(expr, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter value '%s'", expr)
+	}
+	return number, nil
+}
+
+// isQuoted 判断字符串是否被单引号或双引号包围
+func isQuoted(s string) bool {
+	return len(s) >= 2 &&
+		((s[0] == '\'' && s[len(s)-1] == '\'') || (s[0] == '"' && s[len(s)-1] == '"'))
+}
+
+// applyJSONPathSegment 对当前所有匹配值应用一个路径段
+func applyJSONPathSegment(segment jsonPathSegment, current []interface{}) []interface{} {
+	next := make([]interface{}, 0)
+	for _, node := range current {
+		switch segment.kind {
+		case segmentKey:
+			if obj, ok := node.(map[string]interface{}); ok {
+				if value, exists := obj[segment.key]; exists {
+					next = append(next, value)
+				}
+			}
+
+		case segmentIndex:
+			if arr, ok := node.([]interface{}); ok {
+				index := segment.index
+				if index < 0 {
+					index += len(arr)
+				}
+				if index >= 0 && index < len(arr) {
+					next = append(next, arr[index])
+				}
+			}
+
+		case segmentWildcard:
+			next = append(next, jsonPathChildren(node)...)
+
+		case segmentRecursive:
+			for _, descendant := range jsonPathDescendants(node) {
+				if segment.key == "*" {
+					next = append(next, jsonPathChildren(descendant)...)
+				} else if obj, ok := descendant.(map[string]interface{}); ok {
+					if value, exists := obj[segment.key]; exists {
+						next = append(next, value)
+					}
+				}
+			}
+
+		case segmentFilter:
+			for _, child := range jsonPathChildren(node) {
+				if segment.filter.matches(child) {
+					next = append(next, child)
+				}
+			}
+		}
+	}
+	return next
+}
+
+// jsonPathChildren 返回对象或数组的直接子元素（对象按键名排序，保证结果稳定）
+func jsonPathChildren(node interface{}) []interface{} {
+	switch v := node.(type) {
+	case []interface{}:
+		return v
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		children := make([]interface{}, 0, len(v))
+		for _, k := range keys {
+			children = append(children, v[k])
+		}
+		return children
+	}
+	return nil
+}
+
+// jsonPathDescendants 返回节点自身及其所有后代节点
+func jsonPathDescendants(node interface{}) []interface{} {
+	result := []interface{}{node}
+	for _, child := range jsonPathChildren(node) {
+		result = append(result, jsonPathDescendants(child)...)
+	}
+	return result
+}
+
+// matches 判断元素是否满足过滤条件
+func (f *jsonPathFilter) matches(item interface{}) bool {
+	actual := item
+	if f.path != "" {
+		var exists bool
+		actual, exists = lookupRelativePath(item, f.path)
+		if !exists {
+			return false
+		}
+	}
+
+	switch f.operator {
+	case "":
+		return true
+	case "==":
+		return compareValues(f.value, actual)
+	case "!=":
+		return !compareValues(f.value, actual)
+	}
+
+	threshold, ok := toFloat64(f.value)
+	if !ok {
+		return false
+	}
+	return compareNumber(f.operator, threshold, actual) == nil
+}
+
+// lookupRelativePath 按点号路径查找元素中的字段，返回值及其是否存在
+func lookupRelativePath(item interface{}, path string) (interface{}, bool) {
+	current := item
+	for _, part := range strings.Split(path, ".") {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = obj[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,410 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"api_auto_test/pkg/client"
+)
+
+// schemaViolation JSON Schema 校验失败项，Pointer 为出错位置的 JSON Pointer
+type schemaViolation struct {
+	Pointer string
+	Message string
+}
+
+// validateJSONSchema 按 JSON Schema 校验响应体，每个违反项生成一个验证错误
+func (v *Validator) validateJSONSchema(resp *client.Response, result *ValidationResult) {
+	if v.expectation.JSONSchema == "" {
+		return
+	}
+
+	schema, err := loadJSONSchema(v.expectation.JSONSchema)
+	if err != nil {
+		result.Passed = false
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "JSONSchema",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var body interface{}
+	if resp.BodyJSON != nil {
+		body = resp.BodyJSON
+	} else if err := json.Unmarshal(bytes.TrimLeft(resp.Body, "\ufeff \t\r\n"), &body); err != nil {
+		result.Passed = false
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "Body",
+			Message: "Expected JSON response for schema validation, but got non-JSON content",
+		})
+		return
+	}
+
+	checker := &schemaChecker{root: schema}
+	checker.check(schema, body, "")
+	for _, violation := range checker.violations {
+		pointer := violation.Pointer
+		if pointer == "" {
+			pointer = "/"
+		}
+		result.Passed = false
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   pointer,
+			Message: violation.Message,
+		})
+	}
+}
+
+// loadJSONSchema 加载 JSON Schema，支持内联 JSON 或文件路径
+func loadJSONSchema(source string) (interface{}, error) {
+	data := []byte(source)
+	trimmed := strings.TrimSpace(source)
+	if !strings.HasPrefix(trimmed, "{") {
+		content, err := os.ReadFile(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JSON schema file: %w", err)
+		}
+		data = content
+	}
+
+	var schema interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON schema: %w", err)
+	}
+	return schema, nil
+}
+
+// schemaChecker JSON Schema 校验器，支持 draft-07 中常用的关键字
+type schemaChecker struct {
+	root       interface{}
+	violations []schemaViolation
+}
+
+// fail 记录一个违反项
+func (c *schemaChecker) fail(pointer, format string, args ...interface{}) {
+	c.violations = append(c.violations, schemaViolation{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+// matches 判断值是否满足子 Schema，不记录违反项
+func (c *schemaChecker) matches(schema, value interface{}) bool {
+	sub := &schemaChecker{root: c.root}
+	sub.check(schema, value, "")
+	return len(sub.violations) == 0
+}
+
+// check 按 Schema 校验值，pointer 为当前值的 JSON Pointer
+func (c *schemaChecker) check(schemaValue, value interface{}, pointer string) {
+	// 布尔 Schema：true 接受任何值，false 拒绝任何值
+	if allowed, ok := schemaValue.(bool); ok {
+		if !allowed {
+			c.fail(pointer, "value is not allowed by schema")
+		}
+		return
+	}
+	schema, ok := schemaValue.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, err := c.resolveRef(ref)
+		if err != nil {
+			c.fail(pointer, "%s", err.Error())
+			return
+		}
+		c.check(resolved, value, pointer)
+		return
+	}
+
+	if expected, ok := schema["type"]; ok && !matchesSchemaType(expected, value) {
+		c.fail(pointer, "expected type %s, got %s", describeSchemaType(expected), jsonTypeOf(value))
+		return
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, item := range enum {
+			if compareValues(item, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			c.fail(pointer, "value %v is not one of %v", value, enum)
+		}
+	}
+	if constant, ok := schema["const"]; ok && !compareValues(constant, value) {
+		c.fail(pointer, "expected constant %v, got %v", constant, value)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		c.checkObject(schema, v, pointer)
+	case []interface{}:
+		c.checkArray(schema, v, pointer)
+	case string:
+		c.checkString(schema, v, pointer)
+	case float64:
+		c.checkNumber(schema, v, pointer)
+	}
+
+	c.checkCombinators(schema, value, pointer)
+}
+
+// checkObject 校验对象相关关键字
+func (c *schemaChecker) checkObject(schema map[string]interface{}, obj map[string]interface{}, pointer string) {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			key := fmt.Sprintf("%v", name)
+			if _, exists := obj[key]; !exists {
+				c.fail(joinPointer(pointer, key), "required property '%s' is missing", key)
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childPointer := joinPointer(pointer, key)
+		if propertySchema, ok := properties[key]; ok {
+			c.check(propertySchema, obj[key], childPointer)
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				c.fail(childPointer, "additional property '%s' is not allowed", key)
+			}
+		case map[string]interface{}:
+			c.check(additional, obj[key], childPointer)
+		}
+	}
+
+	if limit, ok := schemaNumber(schema, "minProperties"); ok && float64(len(obj)) < limit {
+		c.fail(pointer, "expected at least %v properties, got %d", limit, len(obj))
+	}
+	if limit, ok := schemaNumber(schema, "maxProperties"); ok && float64(len(obj)) > limit {
+		c.fail(pointer, "expected at most %v properties, got %d", limit, len(obj))
+	}
+}
+
+// checkArray 校验数组相关关键字
+func (c *schemaChecker) checkArray(schema map[string]interface{}, arr []interface{}, pointer string) {
+	switch items := schema["items"].(type) {
+	case map[string]interface{}, bool:
+		for i, item := range arr {
+			c.check(items, item, joinPointer(pointer, strconv.Itoa(i)))
+		}
+	case []interface{}:
+		// 元组形式：按位置校验
+		for i, item := range arr {
+			if i < len(items) {
+				c.check(items[i], item, joinPointer(pointer, strconv.Itoa(i)))
+			}
+		}
+	}
+
+	if limit, ok := schemaNumber(schema, "minItems"); ok && float64(len(arr)) < limit {
+		c.fail(pointer, "expected at least %v items, got %d", limit, len(arr))
+	}
+	if limit, ok := schemaNumber(schema, "maxItems"); ok && float64(len(arr)) > limit {
+		c.fail(pointer, "expected at most %v items, got %d", limit, len(arr))
+	}
+	if unique, ok := schema["uniqueItems"].(bool); ok && unique {
+		for i := 0; i < len(arr); i++ {
+			for j := i + 1; j < len(arr); j++ {
+				if compareValues(arr[i], arr[j]) {
+					c.fail(pointer, "items at index %d and %d are not unique", i, j)
+					return
+				}
+			}
+		}
+	}
+}
+
+// checkString 校验字符串相关关键字
+func (c *schemaChecker) checkString(schema map[string]interface{}, str string, pointer string) {
+	length := utf8.RuneCountInString(str)
+	if limit, ok := schemaNumber(schema, "minLength"); ok && float64(length) < limit {
+		c.fail(pointer, "expected length >= %v, got %d", limit, length)
+	}
+	if limit, ok := schemaNumber(schema, "maxLength"); ok && float64(length) > limit {
+		c.fail(pointer, "expected length <= %v, got %d", limit, length)
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		matched, err := regexp.MatchString(pattern, str)
+		if err != nil {
+			c.fail(pointer, "invalid pattern '%s': %v", pattern, err)
+		} else if !matched {
+			c.fail(pointer, "value '%s' does not match pattern '%s'", str, pattern)
+		}
+	}
+}
+
+// checkNumber 校验数值相关关键字
+func (c *schemaChecker) checkNumber(schema map[string]interface{}, num float64, pointer string) {
+	if limit, ok := schemaNumber(schema, "minimum"); ok && num < limit {
+		c.fail(pointer, "expected >= %v, got %v", limit, num)
+	}
+	if limit, ok := schemaNumber(schema, "maximum"); ok && num > limit {
+		c.fail(pointer, "expected <= %v, got %v", limit, num)
+	}
+	if limit, ok := schemaNumber(schema, "exclusiveMinimum"); ok && num <= limit {
+		c.fail(pointer, "expected > %v, got %v", limit, num)
+	}
+	if limit, ok := schemaNumber(schema, "exclusiveMaximum"); ok && num >= limit {
+		c.fail(pointer, "expected < %v, got %v", limit, num)
+	}
+	if multiple, ok := schemaNumber(schema, "multipleOf"); ok && multiple > 0 {
+		quotient := num / multiple
+		if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			c.fail(pointer, "expected a multiple of %v, got %v", multiple, num)
+		}
+	}
+}
+
+// checkCombinators 校验 allOf、anyOf、oneOf、not 组合关键字
+func (c *schemaChecker) checkCombinators(schema map[string]interface{}, value interface{}, pointer string) {
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range allOf {
+			c.check(sub, value, pointer)
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		matched := false
+		for _, sub := range anyOf {
+			if c.matches(sub, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			c.fail(pointer, "value does not match any schema in anyOf")
+		}
+	}
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		count := 0
+		for _, sub := range oneOf {
+			if c.matches(sub, value) {
+				count++
+			}
+		}
+		if count != 1 {
+			c.fail(pointer, "value must match exactly one schema in oneOf, matched %d", count)
+		}
+	}
+	if not, ok := schema["not"]; ok && c.matches(not, value) {
+		c.fail(pointer, "value must not match the schema in not")
+	}
+}
+
+// resolveRef 解析文档内的 $ref 引用，如 "#/definitions/user"
+func (c *schemaChecker) resolveRef(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported $ref '%s': only local references are supported", ref)
+	}
+
+	current := c.root
+	pointer := strings.TrimPrefix(ref, "#")
+	if pointer == "" {
+		return current, nil
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot resolve $ref '%s'", ref)
+		}
+		current, ok = obj[token]
+		if !ok {
+			return nil, fmt.Errorf("cannot resolve $ref '%s'", ref)
+		}
+	}
+	return current, nil
+}
+
+// matchesSchemaType 判断值是否满足 type 关键字（支持单个类型或类型数组）
+func matchesSchemaType(expected, value interface{}) bool {
+	switch t := expected.(type) {
+	case string:
+		return matchesSingleType(t, value)
+	case []interface{}:
+		for _, item := range t {
+			if name, ok := item.(string); ok && matchesSingleType(name, value) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// matchesSingleType 判断值是否为指定的 JSON 类型
+func matchesSingleType(name string, value interface{}) bool {
+	actual := jsonTypeOf(value)
+	if name == "integer" {
+		num, ok := value.(float64)
+		return ok && num == math.Trunc(num)
+	}
+	if name == "number" {
+		return actual == "number"
+	}
+	return actual == name
+}
+
+// describeSchemaType 将 type 关键字格式化为可读文本
+func describeSchemaType(expected interface{}) string {
+	if types, ok := expected.([]interface{}); ok {
+		names := make([]string, 0, len(types))
+		for _, t := range types {
+			names = append(names, fmt.Sprintf("%v", t))
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprintf("%v", expected)
+}
+
+// jsonTypeOf 返回值对应的 JSON 类型名称
+func jsonTypeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// schemaNumber 读取 Schema 中的数值关键字
+func schemaNumber(schema map[string]interface{}, key string) (float64, bool) {
+	num, ok := schema[key].(float64)
+	return num, ok
+}
+
+// joinPointer 拼接 JSON Pointer，按 RFC 6901 转义 ~ 和 /
+func joinPointer(pointer, token string) string {
+	token = strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+	return pointer + "/" + token
+}
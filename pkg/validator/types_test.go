@@ -0,0 +1,100 @@
+package validator_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"api_auto_test/pkg/config"
+)
+
+// handledTypes 从 validator.go 中解析指定函数处理的验证器类型：case 分支和 == 比较中的字符串字面量
+func handledTypes(funcName string) []string {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "validator.go", nil, 0)
+	Expect(err).NotTo(HaveOccurred())
+
+	types := make([]string, 0)
+	addLiteral := func(expr ast.Expr) {
+		if lit, ok := expr.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			value, err := strconv.Unquote(lit.Value)
+			Expect(err).NotTo(HaveOccurred())
+			types = append(types, value)
+		}
+	}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != funcName {
+			continue
+		}
+		ast.Inspect(fn.Body, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.CaseClause:
+				for _, expr := range n.List {
+					addLiteral(expr)
+				}
+			case *ast.BinaryExpr:
+				// 只取与类型比较的字面量，如 t == "sha256" 或 strings.ToLower(validator.Type) == "compression_ratio"
+				if n.Op == token.EQL && isTypeExpr(n.X) {
+					addLiteral(n.Y)
+				}
+			}
+			return true
+		})
+	}
+	Expect(types).NotTo(BeEmpty(), "no validator types found in %s", funcName)
+	return types
+}
+
+// isTypeExpr 判断表达式是否为验证器类型：变量 t 或 strings.ToLower(validator.Type)
+func isTypeExpr(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name == "t"
+	case *ast.CallExpr:
+		if len(e.Args) != 1 {
+			return false
+		}
+		selector, ok := e.Args[0].(*ast.SelectorExpr)
+		return ok && selector.Sel.Name == "Type"
+	}
+	return false
+}
+
+// 配置校验中的验证器类型列表是手工维护的，这里确保 validator 包支持的每种类型都能通过配置校验
+var _ = Describe("验证器类型", func() {
+	validate := func(response config.ResponseExpectation) error {
+		cfg := &config.TestConfig{APIs: []config.APITest{{
+			Name:     "test",
+			Request:  config.RequestConfig{Method: "GET", Path: "/"},
+			Response: response,
+		}}}
+		return cfg.Validate()
+	}
+
+	It("响应体验证器支持的每种类型都应该通过配置校验", func() {
+		for _, validatorType := range handledTypes("evaluateValidator") {
+			err := validate(config.ResponseExpectation{
+				Validators: []config.Validator{{Type: validatorType, Field: "data"}},
+			})
+			if err != nil {
+				Expect(err.Error()).NotTo(ContainSubstring("unknown validator type"), "validator type '%s'", validatorType)
+			}
+		}
+	})
+
+	It("响应头验证器支持的每种类型都应该通过配置校验", func() {
+		for _, validatorType := range handledTypes("validateHeaderValues") {
+			err := validate(config.ResponseExpectation{
+				HeaderValidators: []config.Validator{{Type: validatorType, Field: "X-Id"}},
+			})
+			if err != nil {
+				Expect(err.Error()).NotTo(ContainSubstring("unsupported header validator type"), "header validator type '%s'", validatorType)
+			}
+		}
+	})
+})
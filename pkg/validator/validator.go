@@ -1,16 +1,28 @@
 package validator
 
 import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"api_auto_test/pkg/client"
 	"api_auto_test/pkg/config"
 )
 
+// severityWarning 验证器的警告级别，验证不通过时只记录警告
+const severityWarning = "warning"
+
 // ValidationResult 验证结果
 type ValidationResult struct {
 	Passed   bool
@@ -24,6 +36,7 @@ type ValidationError struct {
 	Expected interface{}
 	Actual   interface{}
 	Message  string
+	Diffs    []FieldDiff // 对象或数组不一致时逐个列出不一致的字段
 }
 
 // Validator 验证器
@@ -47,36 +60,175 @@ func (v *Validator) Validate(resp *client.Response) *ValidationResult {
 	}
 
 	// 验证状态码
-	if v.expectation.StatusCode != 0 {
-		if resp.StatusCode != v.expectation.StatusCode {
-			result.Passed = false
-			result.Errors = append(result.Errors, ValidationError{
-				Field:    "StatusCode",
-				Expected: v.expectation.StatusCode,
-				Actual:   resp.StatusCode,
-				Message:  fmt.Sprintf("Expected status code %d, got %d", v.expectation.StatusCode, resp.StatusCode),
-			})
-		}
-	}
+	v.validateStatusCode(resp, result)
 
 	// 验证Headers
 	v.validateHeaders(resp, result)
 
+	// 验证响应体长度
+	v.validateContentLength(resp, result)
+
+	// 检查响应时间告警阈值
+	v.checkResponseTime(resp, result)
+
+	// HEAD 请求没有响应体，跳过所有 Body 相关的断言
+	if resp.Method == http.MethodHead {
+		if v.hasBodyAssertions() {
+			result.Warnings = append(result.Warnings, "HEAD has no body: body assertions skipped")
+		}
+		return result
+	}
+
 	// 验证Body包含内容
 	v.validateBodyContains(resp, result)
 
 	// 验证Body不包含内容
 	v.validateBodyExcludes(resp, result)
 
+	// 验证原始响应体文本
+	v.validateBodyText(resp, result)
+
+	// 验证响应体是合法的 JSON
+	v.validateBodyIsJSON(resp, result)
+
 	// 验证Body字段
 	v.validateBodyFields(resp, result)
 
+	// 与 golden 文件比较完整的响应体
+	v.validateBodyGolden(resp, result)
+
+	// 按 JSON Schema 验证响应体结构
+	v.validateJSONSchema(resp, result)
+
+	// 验证业务成功字段
+	v.validateSuccessField(resp, result)
+
+	// 检查已废弃的字段
+	v.checkDeprecatedFields(resp, result)
+
 	// 执行自定义验证器
 	v.executeCustomValidators(resp, result)
 
 	return result
 }
 
+// validateStatusCode 验证状态码，status_code、status_code_in、status_code_range 任一匹配即通过
+func (v *Validator) validateStatusCode(resp *client.Response, result *ValidationResult) {
+	exact := v.expectation.StatusCode
+	codes := v.expectation.StatusCodeIn
+	codeRange := strings.TrimSpace(v.expectation.StatusCodeRange)
+	if exact == 0 && len(codes) == 0 && codeRange == "" {
+		return
+	}
+
+	// 只配置了精确状态码时保持原有的错误格式
+	if len(codes) == 0 && codeRange == "" {
+		if resp.StatusCode != exact {
+			result.Passed = false
+			result.Errors = append(result.Errors, ValidationError{
+				Field:    "StatusCode",
+				Expected: exact,
+				Actual:   resp.StatusCode,
+				Message:  fmt.Sprintf("Expected status code %d, got %d", exact, resp.StatusCode),
+			})
+		}
+		return
+	}
+
+	expected := make([]string, 0, 3)
+	if exact != 0 {
+		if resp.StatusCode == exact {
+			return
+		}
+		expected = append(expected, strconv.Itoa(exact))
+	}
+	for _, code := range codes {
+		if resp.StatusCode == code {
+			return
+		}
+	}
+	if len(codes) > 0 {
+		expected = append(expected, fmt.Sprintf("one of %v", codes))
+	}
+	if codeRange != "" {
+		low, high, err := parseStatusCodeRange(codeRange)
+		if err != nil {
+			result.Passed = false
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   "StatusCodeRange",
+				Message: err.Error(),
+			})
+			return
+		}
+		if resp.StatusCode >= low && resp.StatusCode <= high {
+			return
+		}
+		expected = append(expected, "in range "+codeRange)
+	}
+
+	description := strings.Join(expected, " or ")
+	result.Passed = false
+	result.Errors = append(result.Errors, ValidationError{
+		Field:    "StatusCode",
+		Expected: description,
+		Actual:   resp.StatusCode,
+		Message:  fmt.Sprintf("Expected status code %s, got %d", description, resp.StatusCode),
+	})
+}
+
+// parseStatusCodeRange 解析状态码范围，支持 "200-299" 和 "2xx" 两种写法
+func parseStatusCodeRange(codeRange string) (int, int, error) {
+	lower := strings.ToLower(codeRange)
+	if len(lower) == 3 && strings.HasSuffix(lower, "xx") && lower[0] >= '1' && lower[0] <= '5' {
+		base := int(lower[0]-'0') * 100
+		return base, base + 99, nil
+	}
+
+	invalid := fmt.Errorf("invalid status code range '%s': expected format like 200-299 or 2xx", codeRange)
+	parts := strings.Split(codeRange, "-")
+	if len(parts) != 2 {
+		return 0, 0, invalid
+	}
+	low, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
+	high, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err1 != nil || err2 != nil || low > high {
+		return 0, 0, invalid
+	}
+	return low, high, nil
+}
+
+// checkResponseTime 响应时间超过告警阈值时记录警告，不影响验证结果
+func (v *Validator) checkResponseTime(resp *client.Response, result *ValidationResult) {
+	threshold := v.expectation.WarnResponseTime
+	if threshold <= 0 || resp.Duration <= threshold {
+		return
+	}
+	result.Warnings = append(result.Warnings,
+		fmt.Sprintf("response time %s exceeded warning threshold %s", resp.Duration, threshold))
+}
+
+// checkDeprecatedFields 响应中仍包含已废弃的字段时记录警告，不影响验证结果
+func (v *Validator) checkDeprecatedFields(resp *client.Response, result *ValidationResult) {
+	for _, field := range v.expectation.DeprecatedFields {
+		if getJSONField(resp.BodyJSON, field) != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("deprecated field '%s' is present in response", field))
+		}
+	}
+}
+
+// hasBodyAssertions 判断是否配置了针对响应体的断言
+func (v *Validator) hasBodyAssertions() bool {
+	return len(v.expectation.Body) > 0 ||
+		len(v.expectation.BodyContains) > 0 ||
+		len(v.expectation.BodyExcludes) > 0 ||
+		v.expectation.BodyEquals != "" ||
+		v.expectation.BodyRegex != "" ||
+		v.expectation.BodyIsJSON ||
+		v.expectation.BodyGolden != "" ||
+		v.expectation.JSONSchema != "" ||
+		len(v.expectation.Validators) > 0
+}
+
 // validateHeaders 验证响应头
 func (v *Validator) validateHeaders(resp *client.Response, result *ValidationResult) {
 	for key, expectedValue := range v.expectation.Headers {
@@ -91,6 +243,158 @@ func (v *Validator) validateHeaders(resp *client.Response, result *ValidationRes
 			})
 		}
 	}
+
+	for _, headerValidator := range v.expectation.HeaderValidators {
+		if err := validateHeaderValues(headerValidator, resp.Headers.Values(headerValidator.Field)); err != nil {
+			field := fmt.Sprintf("Header[%s]", headerValidator.Field)
+			// 警告级别的验证器只记录警告，不影响验证结果
+			if strings.EqualFold(headerValidator.Severity, severityWarning) {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %s", field, err.Error()))
+				continue
+			}
+			result.Passed = false
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   field,
+				Actual:  resp.Headers.Values(headerValidator.Field),
+				Message: err.Error(),
+			})
+		}
+	}
+}
+
+// validateHeaderValues 对响应头的所有值执行验证，任一值满足即通过，失败时报告所有实际值
+func validateHeaderValues(headerValidator config.Validator, values []string) error {
+	if headerValidator.Field == "" {
+		return fmt.Errorf("header validator requires a header name in 'field'")
+	}
+
+	// 存在性验证只检查响应头是否出现，不比较值
+	switch strings.ToLower(headerValidator.Type) {
+	case "exists":
+		if len(values) == 0 {
+			return fmt.Errorf("header %s is missing", headerValidator.Field)
+		}
+		return nil
+	case "not_exists":
+		if len(values) > 0 {
+			return fmt.Errorf("expected header %s to be absent, got [%s]", headerValidator.Field, strings.Join(values, "; "))
+		}
+		return nil
+	}
+
+	expectedValue := headerValidator.Value
+	if expectedValue == nil {
+		expectedValue = headerValidator.Expect
+	}
+	expected := fmt.Sprintf("%v", expectedValue)
+
+	var match func(string) bool
+	description := ""
+	switch t := strings.ToLower(headerValidator.Type); t {
+	case "equals", "equal", "eq", "":
+		match = func(value string) bool {
+			if headerValidator.Trim {
+				value = strings.TrimSpace(value)
+			}
+			if headerValidator.IgnoreCase {
+				return strings.EqualFold(value, expected)
+			}
+			return value == expected
+		}
+		description = fmt.Sprintf("equal '%s'", expected)
+	case "contains", "icontains":
+		ignoreCase := headerValidator.IgnoreCase || t == "icontains"
+		match = func(value string) bool {
+			return containsText(value, expected, ignoreCase, headerValidator.Trim)
+		}
+		description = fmt.Sprintf("contain '%s'%s", expected, describeContainsOptions(ignoreCase, headerValidator.Trim))
+	case "regex", "regexp":
+		re, err := regexp.Compile(expected)
+		if err != nil {
+			return fmt.Errorf("invalid regex pattern: %w", err)
+		}
+		match = re.MatchString
+		description = fmt.Sprintf("match pattern '%s'", expected)
+	default:
+		return fmt.Errorf("unsupported header validator type: %s", headerValidator.Type)
+	}
+
+	if len(values) == 0 {
+		return fmt.Errorf("header %s is missing, expected a value to %s", headerValidator.Field, description)
+	}
+	for _, value := range values {
+		if match(value) {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected a %s header value to %s, got [%s]",
+		headerValidator.Field, description, strings.Join(values, "; "))
+}
+
+// validateContentLength 验证响应体长度
+func (v *Validator) validateContentLength(resp *client.Response, result *ValidationResult) {
+	expectation := v.expectation.ContentLength
+	if expectation == nil {
+		return
+	}
+
+	bodyLength := int64(len(resp.Body))
+	length := bodyLength
+	source := "body length"
+
+	if header := resp.Headers.Get("Content-Length"); header != "" {
+		headerLength, err := strconv.ParseInt(header, 10, 64)
+		if err != nil {
+			result.Passed = false
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   "ContentLength",
+				Actual:  header,
+				Message: fmt.Sprintf("Invalid Content-Length header: %s", header),
+			})
+			return
+		}
+
+		// HEAD 请求没有响应体，只检查响应头
+		if resp.Method != http.MethodHead && headerLength != bodyLength {
+			result.Passed = false
+			result.Errors = append(result.Errors, ValidationError{
+				Field:    "ContentLength",
+				Expected: headerLength,
+				Actual:   bodyLength,
+				Message:  fmt.Sprintf("Content-Length header is %d but actual body length is %d", headerLength, bodyLength),
+			})
+		}
+		length = headerLength
+		source = "Content-Length"
+	}
+
+	if expectation.Equals != nil && length != *expectation.Equals {
+		result.Passed = false
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    "ContentLength",
+			Expected: *expectation.Equals,
+			Actual:   length,
+			Message:  fmt.Sprintf("Expected content length %d, got %d (%s)", *expectation.Equals, length, source),
+		})
+	}
+	if expectation.Min != nil && length < *expectation.Min {
+		result.Passed = false
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    "ContentLength",
+			Expected: fmt.Sprintf(">= %d", *expectation.Min),
+			Actual:   length,
+			Message:  fmt.Sprintf("Expected content length >= %d, got %d (%s)", *expectation.Min, length, source),
+		})
+	}
+	if expectation.Max != nil && length > *expectation.Max {
+		result.Passed = false
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    "ContentLength",
+			Expected: fmt.Sprintf("<= %d", *expectation.Max),
+			Actual:   length,
+			Message:  fmt.Sprintf("Expected content length <= %d, got %d (%s)", *expectation.Max, length, source),
+		})
+	}
 }
 
 // validateBodyContains 验证响应体包含指定内容
@@ -125,6 +429,61 @@ func (v *Validator) validateBodyExcludes(resp *client.Response, result *Validati
 	}
 }
 
+// validateBodyText 按原始文本验证响应体，适用于 XML、纯文本等非 JSON 响应
+func (v *Validator) validateBodyText(resp *client.Response, result *ValidationResult) {
+	bodyStr := string(resp.Body)
+
+	if v.expectation.BodyEquals != "" && bodyStr != v.expectation.BodyEquals {
+		result.Passed = false
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    "Body",
+			Expected: v.expectation.BodyEquals,
+			Actual:   bodyStr,
+			Message:  "Response body does not equal expected text",
+		})
+	}
+
+	if v.expectation.BodyRegex != "" {
+		matched, err := regexp.MatchString(v.expectation.BodyRegex, bodyStr)
+		if err != nil {
+			result.Passed = false
+			result.Errors = append(result.Errors, ValidationError{
+				Field:    "BodyRegex",
+				Expected: v.expectation.BodyRegex,
+				Message:  fmt.Sprintf("invalid regex pattern: %v", err),
+			})
+		} else if !matched {
+			result.Passed = false
+			result.Errors = append(result.Errors, ValidationError{
+				Field:    "Body",
+				Expected: fmt.Sprintf("matches '%s'", v.expectation.BodyRegex),
+				Actual:   bodyStr,
+				Message:  fmt.Sprintf("Response body does not match pattern '%s'", v.expectation.BodyRegex),
+			})
+		}
+	}
+}
+
+// validateBodyIsJSON 验证非空响应体是合法的 JSON，避免截断或格式错误的响应体使字段验证静默得到 nil
+func (v *Validator) validateBodyIsJSON(resp *client.Response, result *ValidationResult) {
+	if !v.expectation.BodyIsJSON {
+		return
+	}
+
+	body := bytes.TrimLeft(resp.Body, "\ufeff \t\r\n")
+	if len(body) == 0 {
+		return
+	}
+	var parsed interface{}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		result.Passed = false
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "Body",
+			Message: fmt.Sprintf("Response body is not valid JSON: %v", err),
+		})
+	}
+}
+
 // validateBodyFields 验证响应体字段
 func (v *Validator) validateBodyFields(resp *client.Response, result *ValidationResult) {
 	if len(v.expectation.Body) == 0 {
@@ -142,22 +501,67 @@ func (v *Validator) validateBodyFields(resp *client.Response, result *Validation
 
 	for field, expectedValue := range v.expectation.Body {
 		actualValue := getJSONField(resp.BodyJSON, field)
+
+		// 支持数值比较运算符，如 ">5"、"<=100"
+		if op, threshold, ok := parseComparison(expectedValue); ok {
+			if err := compareNumber(op, threshold, actualValue); err != nil {
+				result.Passed = false
+				result.Errors = append(result.Errors, ValidationError{
+					Field:    fmt.Sprintf("Body.%s", field),
+					Expected: expectedValue,
+					Actual:   actualValue,
+					Message:  fmt.Sprintf("Field '%s': %s", field, err.Error()),
+				})
+			}
+			continue
+		}
+
 		if !compareValues(expectedValue, actualValue) {
 			result.Passed = false
-			result.Errors = append(result.Errors, ValidationError{
+			validationError := ValidationError{
 				Field:    fmt.Sprintf("Body.%s", field),
 				Expected: expectedValue,
 				Actual:   actualValue,
 				Message:  fmt.Sprintf("Field '%s': expected %v, got %v", field, expectedValue, actualValue),
-			})
+			}
+			// 对象和数组逐个列出不一致的字段，避免比较两段完整的 JSON
+			if diffs := diffNested(field, expectedValue, actualValue); len(diffs) > 0 {
+				validationError.Diffs = diffs
+				validationError.Message = fmt.Sprintf("Field '%s': %d nested field(s) differ", field, len(diffs))
+			}
+			result.Errors = append(result.Errors, validationError)
 		}
 	}
 }
 
+// validateSuccessField 验证业务成功字段
+func (v *Validator) validateSuccessField(resp *client.Response, result *ValidationResult) {
+	successField := v.expectation.SuccessField
+	if successField == nil || successField.Path == "" {
+		return
+	}
+
+	actualValue := getJSONField(resp.BodyJSON, successField.Path)
+	if !compareValues(successField.Equals, actualValue) {
+		result.Passed = false
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    fmt.Sprintf("SuccessField.%s", successField.Path),
+			Expected: successField.Equals,
+			Actual:   actualValue,
+			Message:  fmt.Sprintf("Business success field '%s': expected %v, got %v", successField.Path, successField.Equals, actualValue),
+		})
+	}
+}
+
 // executeCustomValidators 执行自定义验证器
 func (v *Validator) executeCustomValidators(resp *client.Response, result *ValidationResult) {
 	for _, validator := range v.expectation.Validators {
 		if err := v.executeValidator(validator, resp); err != nil {
+			// 警告级别的验证器只记录警告，不影响验证结果
+			if strings.EqualFold(validator.Severity, severityWarning) {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %s", validator.Field, err.Error()))
+				continue
+			}
 			result.Passed = false
 			result.Errors = append(result.Errors, ValidationError{
 				Field:   validator.Field,
@@ -167,10 +571,65 @@ func (v *Validator) executeCustomValidators(resp *client.Response, result *Valid
 	}
 }
 
-// executeValidator 执行单个验证器
+// invalidValidatorError 验证器配置本身有误（如无效的正则、未知的类型），设置 not 时不会被取反为通过
+type invalidValidatorError struct {
+	err error
+}
+
+func (e *invalidValidatorError) Error() string { return e.err.Error() }
+
+func (e *invalidValidatorError) Unwrap() error { return e.err }
+
+// invalidValidatorf 创建验证器配置错误
+func invalidValidatorf(format string, args ...interface{}) error {
+	return &invalidValidatorError{err: fmt.Errorf(format, args...)}
+}
+
+// executeValidator 执行单个验证器，设置 not 时取反验证结果
 func (v *Validator) executeValidator(validator config.Validator, resp *client.Response) error {
-	// 获取字段值
-	fieldValue := getJSONField(resp.BodyJSON, validator.Field)
+	err := v.evaluateValidator(validator, resp)
+	if !validator.Not {
+		return err
+	}
+
+	var invalid *invalidValidatorError
+	if errors.As(err, &invalid) {
+		return err
+	}
+	if err == nil {
+		return fmt.Errorf("expected not (%s), but it passed", describeValidator(validator))
+	}
+	return nil
+}
+
+// evaluateValidator 按验证器类型执行验证，不考虑 not
+func (v *Validator) evaluateValidator(validator config.Validator, resp *client.Response) error {
+	// 压缩率验证基于整个响应体，不依赖字段
+	if strings.ToLower(validator.Type) == "compression_ratio" {
+		return validateCompressionRatio(validator, resp)
+	}
+
+	// 摘要验证默认基于原始响应体，指定字段时基于字段值
+	if t := strings.ToLower(validator.Type); t == "sha256" || t == "md5" {
+		return validateDigest(validator, resp)
+	}
+
+	// 存在性验证需要区分字段缺失和字段值为 null
+	if t := strings.ToLower(validator.Type); t == "exists" || t == "not_exists" {
+		return validateExistence(t == "exists", validator.Field, resp)
+	}
+
+	// 获取字段值，以 $ 开头的字段按 JSONPath 表达式查询
+	var fieldValue interface{}
+	if isJSONPath(validator.Field) {
+		value, err := queryJSONPath(resp.BodyJSON, validator.Field)
+		if err != nil {
+			return invalidValidatorf("invalid JSONPath: %w", err)
+		}
+		fieldValue = value
+	} else {
+		fieldValue = getJSONField(resp.BodyJSON, validator.Field)
+	}
 
 	// 确定期望值（支持value和expect两种写法）
 	expectedValue := validator.Value
@@ -183,18 +642,35 @@ func (v *Validator) executeValidator(validator config.Validator, resp *client.Re
 		if !compareValues(expectedValue, fieldValue) {
 			return fmt.Errorf("expected %v, got %v", expectedValue, fieldValue)
 		}
-	case "contains":
+	case "not_equals", "ne":
+		if compareValues(expectedValue, fieldValue) {
+			return fmt.Errorf("expected not equal to %v, but got %v", expectedValue, fieldValue)
+		}
+	case "contains", "icontains":
 		fieldStr := fmt.Sprintf("%v", fieldValue)
 		expectedStr := fmt.Sprintf("%v", expectedValue)
-		if !strings.Contains(fieldStr, expectedStr) {
-			return fmt.Errorf("expected to contain '%s', got '%s'", expectedStr, fieldStr)
+		ignoreCase := validator.IgnoreCase || strings.ToLower(validator.Type) == "icontains"
+		if !containsText(fieldStr, expectedStr, ignoreCase, validator.Trim) {
+			return fmt.Errorf("expected to contain '%s'%s, got '%s'", expectedStr, describeContainsOptions(ignoreCase, validator.Trim), fieldStr)
+		}
+	case "starts_with":
+		fieldStr := fmt.Sprintf("%v", fieldValue)
+		prefix := fmt.Sprintf("%v", expectedValue)
+		if !strings.HasPrefix(normalizeText(fieldStr, validator.IgnoreCase, validator.Trim), normalizeText(prefix, validator.IgnoreCase, validator.Trim)) {
+			return fmt.Errorf("expected to start with '%s'%s, got '%s'", prefix, describeContainsOptions(validator.IgnoreCase, validator.Trim), fieldStr)
+		}
+	case "ends_with":
+		fieldStr := fmt.Sprintf("%v", fieldValue)
+		suffix := fmt.Sprintf("%v", expectedValue)
+		if !strings.HasSuffix(normalizeText(fieldStr, validator.IgnoreCase, validator.Trim), normalizeText(suffix, validator.IgnoreCase, validator.Trim)) {
+			return fmt.Errorf("expected to end with '%s'%s, got '%s'", suffix, describeContainsOptions(validator.IgnoreCase, validator.Trim), fieldStr)
 		}
 	case "regex", "regexp":
 		fieldStr := fmt.Sprintf("%v", fieldValue)
 		pattern := fmt.Sprintf("%v", expectedValue)
 		matched, err := regexp.MatchString(pattern, fieldStr)
 		if err != nil {
-			return fmt.Errorf("invalid regex pattern: %w", err)
+			return invalidValidatorf("invalid regex pattern: %w", err)
 		}
 		if !matched {
 			return fmt.Errorf("value '%s' does not match pattern '%s'", fieldStr, pattern)
@@ -204,43 +680,334 @@ func (v *Validator) executeValidator(validator config.Validator, resp *client.Re
 			return fmt.Errorf("field should not be empty")
 		}
 	case "type":
-		expectedType := fmt.Sprintf("%v", expectedValue)
-		if fieldValue == nil {
-			return fmt.Errorf("expected type %s, got nil", expectedType)
+		return validateType(fmt.Sprintf("%v", expectedValue), fieldValue)
+	case "count_where":
+		return v.validateCountWhere(validator, fieldValue, expectedValue)
+	case "each":
+		return v.validateEach(fieldValue, expectedValue)
+	case "gt", "gte", "lt", "lte":
+		threshold, ok := toFloat64(expectedValue)
+		if !ok {
+			return invalidValidatorf("invalid expected number: %v", expectedValue)
 		}
+		return compareNumber(numericValidatorOperators[strings.ToLower(validator.Type)], threshold, fieldValue)
+	case "range":
+		return validateRange(expectedValue, fieldValue)
+	case "length", "min_length", "max_length":
+		return validateLength(strings.ToLower(validator.Type), expectedValue, fieldValue)
+	case "in", "not_in":
+		return validateMembership(strings.ToLower(validator.Type) == "in", expectedValue, fieldValue)
+	default:
+		return invalidValidatorf("unknown validator type: %s", validator.Type)
+	}
+
+	return nil
+}
+
+// containsText 判断 s 是否包含 substr，可选忽略大小写和首尾空白
+func containsText(s, substr string, ignoreCase, trim bool) bool {
+	return strings.Contains(normalizeText(s, ignoreCase, trim), normalizeText(substr, ignoreCase, trim))
+}
+
+// normalizeText 按验证选项去除首尾空白并转为小写
+func normalizeText(s string, ignoreCase, trim bool) string {
+	if trim {
+		s = strings.TrimSpace(s)
+	}
+	if ignoreCase {
+		s = strings.ToLower(s)
+	}
+	return s
+}
 
-		actualTypeObj := reflect.TypeOf(fieldValue)
-		actualType := actualTypeObj.String()
-		actualKind := actualTypeObj.Kind().String()
+// describeContainsOptions 生成 contains、starts_with 和 ends_with 验证选项的说明，用于错误信息
+func describeContainsOptions(ignoreCase, trim bool) string {
+	options := make([]string, 0, 2)
+	if ignoreCase {
+		options = append(options, "ignoring case")
+	}
+	if trim {
+		options = append(options, "trimmed")
+	}
+	if len(options) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(options, ", ") + ")"
+}
 
-		// 归一化类型字符串，去除空格
-		normalizedActualType := strings.ReplaceAll(actualType, " ", "")
-		normalizedExpectedType := strings.ReplaceAll(expectedType, " ", "")
+// jsonTypeNames type 验证器支持的 JSON 类型名称
+var jsonTypeNames = map[string]bool{
+	"string":  true,
+	"number":  true,
+	"integer": true,
+	"boolean": true,
+	"array":   true,
+	"object":  true,
+	"null":    true,
+}
 
-		// 支持多种匹配方式：
-		// 1. 完整类型名匹配（去除空格后）
-		// 2. Kind 匹配（如 "slice", "map", "string" 等）
-		// 3. 包含匹配（兼容性）
-		if normalizedActualType == normalizedExpectedType ||
-			strings.EqualFold(actualKind, expectedType) ||
-			strings.Contains(normalizedActualType, normalizedExpectedType) {
+// validateType 验证字段的类型，优先按 JSON 类型名称匹配，integer 要求数值为整数
+// 兼容 Go 类型名（如 float64、[]interface {}）和 Kind（如 slice、map）的写法，但必须完全一致
+func validateType(expectedType string, fieldValue interface{}) error {
+	if jsonTypeNames[expectedType] {
+		if matchesSingleType(expectedType, fieldValue) {
 			return nil
 		}
+		return fmt.Errorf("expected type %s, got %s", expectedType, jsonTypeOf(fieldValue))
+	}
 
-		return fmt.Errorf("expected type %s, got %s", expectedType, actualType)
-	default:
-		return fmt.Errorf("unknown validator type: %s", validator.Type)
+	if fieldValue == nil {
+		return fmt.Errorf("expected type %s, got nil", expectedType)
+	}
+	actualTypeObj := reflect.TypeOf(fieldValue)
+	actualType := actualTypeObj.String()
+	if strings.ReplaceAll(actualType, " ", "") == strings.ReplaceAll(expectedType, " ", "") ||
+		strings.EqualFold(actualTypeObj.Kind().String(), expectedType) {
+		return nil
+	}
+	return fmt.Errorf("expected type %s, got %s", expectedType, actualType)
+}
+
+// validateCountWhere 统计数组中满足子条件的元素数量并与期望数量比较
+func (v *Validator) validateCountWhere(validator config.Validator, fieldValue, expectedValue interface{}) error {
+	if validator.Where == nil {
+		return invalidValidatorf("count_where requires a 'where' condition")
+	}
+	expectedCount, ok := toFloat64(expectedValue)
+	if !ok {
+		return invalidValidatorf("invalid expected count: %v", expectedValue)
+	}
+
+	items, ok := fieldValue.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected array, got %T", fieldValue)
+	}
+
+	count := 0
+	for _, item := range items {
+		if v.executeValidator(*validator.Where, elementResponse(item, validator.Where.Field)) == nil {
+			count++
+		}
 	}
 
+	if float64(count) != expectedCount {
+		return fmt.Errorf("expected %v items where %s, got %d", expectedCount, describeValidator(*validator.Where), count)
+	}
+	return nil
+}
+
+// validateEach 对数组的每个元素执行 Value 中配置的子验证器，子验证器的字段路径以元素为根
+// 报告所有不满足条件的元素下标
+func (v *Validator) validateEach(fieldValue, expectedValue interface{}) error {
+	rules, err := parseNestedValidators(expectedValue)
+	if err != nil {
+		return err
+	}
+
+	items, ok := fieldValue.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected array, got %T", fieldValue)
+	}
+
+	failures := make([]string, 0)
+	failedItems := 0
+	for i, item := range items {
+		itemFailed := false
+		for _, rule := range rules {
+			err := v.executeValidator(rule, elementResponse(item, rule.Field))
+			if err == nil {
+				continue
+			}
+			itemFailed = true
+			if rule.Field == "" {
+				failures = append(failures, fmt.Sprintf("[%d]: %s", i, err.Error()))
+			} else {
+				failures = append(failures, fmt.Sprintf("[%d].%s: %s", i, rule.Field, err.Error()))
+			}
+		}
+		if itemFailed {
+			failedItems++
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%d of %d items failed: %s", failedItems, len(items), strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// parseNestedValidators 将 Value 中的验证器列表（YAML/JSON 解析得到的对象数组）转换为验证器配置
+func parseNestedValidators(value interface{}) ([]config.Validator, error) {
+	list, ok := value.([]interface{})
+	if !ok || len(list) == 0 {
+		return nil, invalidValidatorf("each validator requires a list of validators in 'value'")
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return nil, invalidValidatorf("invalid nested validators: %w", err)
+	}
+	var rules []config.Validator
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, invalidValidatorf("invalid nested validators: %w", err)
+	}
+	for i, rule := range rules {
+		if rule.Type == "" {
+			return nil, invalidValidatorf("nested validator %d is missing 'type'", i)
+		}
+	}
+	return rules, nil
+}
+
+// elementResponse 以数组元素为根构造响应，供子验证器使用
+// 子验证器未指定字段时，直接对元素本身进行验证
+func elementResponse(item interface{}, field string) *client.Response {
+	if field == "" {
+		return &client.Response{BodyJSON: map[string]interface{}{"": item}}
+	}
+	itemMap, _ := item.(map[string]interface{})
+	return &client.Response{BodyJSON: itemMap}
+}
+
+// describeValidator 生成验证器的可读描述，如 "status equals active"
+func describeValidator(validator config.Validator) string {
+	expectedValue := validator.Value
+	if expectedValue == nil {
+		expectedValue = validator.Expect
+	}
+	if validator.Field == "" {
+		return fmt.Sprintf("%s %v", validator.Type, expectedValue)
+	}
+	return fmt.Sprintf("%s %s %v", validator.Field, validator.Type, expectedValue)
+}
+
+// validateCompressionRatio 验证压缩后（传输）字节数与解压后字节数之比不超过阈值
+func validateCompressionRatio(validator config.Validator, resp *client.Response) error {
+	expectedValue := validator.Value
+	if expectedValue == nil {
+		expectedValue = validator.Expect
+	}
+	threshold, ok := toFloat64(expectedValue)
+	if !ok {
+		return invalidValidatorf("invalid compression ratio threshold: %v", expectedValue)
+	}
+
+	decompressed := int64(len(resp.Body))
+	if decompressed == 0 {
+		return fmt.Errorf("response body is empty, cannot compute compression ratio")
+	}
+
+	ratio := float64(resp.WireSize) / float64(decompressed)
+	if ratio > threshold {
+		return fmt.Errorf("compression ratio %.3f (wire %d bytes / decompressed %d bytes) exceeds threshold %v",
+			ratio, resp.WireSize, decompressed, threshold)
+	}
 	return nil
 }
 
-// getJSONField 获取JSON字段值（支持嵌套路径，如 "data.user.id"）
+// validateDigest 计算响应体（或指定字段）的摘要并与期望的十六进制摘要比较
+func validateDigest(validator config.Validator, resp *client.Response) error {
+	expectedValue := validator.Value
+	if expectedValue == nil {
+		expectedValue = validator.Expect
+	}
+	expected := strings.ToLower(strings.TrimSpace(fmt.Sprintf("%v", expectedValue)))
+	if expectedValue == nil || expected == "" {
+		return invalidValidatorf("%s validator requires an expected hex digest", validator.Type)
+	}
+
+	data := resp.Body
+	if validator.Field != "" {
+		fieldValue := getJSONField(resp.BodyJSON, validator.Field)
+		if fieldValue == nil {
+			return fmt.Errorf("field '%s' not found", validator.Field)
+		}
+		// 字符串字段直接对内容计算摘要，其它类型对其 JSON 序列化结果计算
+		if str, ok := fieldValue.(string); ok {
+			data = []byte(str)
+		} else {
+			encoded, err := json.Marshal(fieldValue)
+			if err != nil {
+				return fmt.Errorf("failed to encode field '%s': %w", validator.Field, err)
+			}
+			data = encoded
+		}
+	}
+
+	var actual string
+	if strings.ToLower(validator.Type) == "md5" {
+		sum := md5.Sum(data)
+		actual = hex.EncodeToString(sum[:])
+	} else {
+		sum := sha256.Sum256(data)
+		actual = hex.EncodeToString(sum[:])
+	}
+
+	if actual != expected {
+		return fmt.Errorf("expected %s digest %s, got %s", strings.ToLower(validator.Type), expected, actual)
+	}
+	return nil
+}
+
+// validateExistence 验证字段存在（值为 false、0、空字符串或 null 也算存在）或不存在
+func validateExistence(shouldExist bool, field string, resp *client.Response) error {
+	if field == "" {
+		return invalidValidatorf("existence validator requires a field")
+	}
+	value, exists, err := lookupJSONField(resp.BodyJSON, field)
+	if err != nil {
+		return invalidValidatorf("invalid JSONPath: %w", err)
+	}
+	if shouldExist && !exists {
+		return fmt.Errorf("field '%s' does not exist", field)
+	}
+	if !shouldExist && exists {
+		return fmt.Errorf("field '%s' should not exist, got %v", field, value)
+	}
+	return nil
+}
+
+// lookupJSONField 获取JSON字段值，并返回字段是否存在（值为 null 的字段视为存在）
+func lookupJSONField(data map[string]interface{}, path string) (interface{}, bool, error) {
+	if data == nil {
+		return nil, false, nil
+	}
+
+	if isJSONPath(path) {
+		matches, definite, err := matchJSONPath(data, path)
+		if err != nil || len(matches) == 0 {
+			return nil, false, err
+		}
+		if definite {
+			return matches[0], true, nil
+		}
+		return matches, true, nil
+	}
+
+	var current interface{} = data
+	for _, part := range strings.Split(path, ".") {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false, nil
+		}
+		current, ok = obj[part]
+		if !ok {
+			return nil, false, nil
+		}
+	}
+	return current, true, nil
+}
+
+// getJSONField 获取JSON字段值（支持嵌套路径，如 "data.user.id"，以及 $ 开头的 JSONPath 表达式）
 func getJSONField(data map[string]interface{}, path string) interface{} {
 	if data == nil {
 		return nil
 	}
 
+	if isJSONPath(path) {
+		value, _ := queryJSONPath(data, path)
+		return value
+	}
+
 	parts := strings.Split(path, ".")
 	var current interface{} = data
 
@@ -256,6 +1023,180 @@ func getJSONField(data map[string]interface{}, path string) interface{} {
 	return current
 }
 
+// comparisonOperators 支持的数值比较运算符（较长的运算符需排在前面）
+var comparisonOperators = []string{">=", "<=", ">", "<"}
+
+// parseComparison 解析形如 ">5"、"<=100" 的比较表达式
+func parseComparison(expected interface{}) (string, float64, bool) {
+	str, ok := expected.(string)
+	if !ok {
+		return "", 0, false
+	}
+	str = strings.TrimSpace(str)
+
+	for _, op := range comparisonOperators {
+		if strings.HasPrefix(str, op) {
+			threshold, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimPrefix(str, op)), 64)
+			if err != nil {
+				return "", 0, false
+			}
+			return op, threshold, true
+		}
+	}
+	return "", 0, false
+}
+
+// validateLength 验证数组或字符串的长度：length 要求相等，min_length/max_length 分别为下限和上限（包含）
+// 字符串按字符（而不是字节）计算长度
+func validateLength(validatorType string, expectedValue, fieldValue interface{}) error {
+	expected, ok := toFloat64(expectedValue)
+	if !ok || expected < 0 || expected != math.Trunc(expected) {
+		return invalidValidatorf("invalid expected length: %v", expectedValue)
+	}
+	expectedLength := int(expected)
+
+	if fieldValue == nil {
+		return fmt.Errorf("field not found, expected array or string")
+	}
+	var actual int
+	switch v := fieldValue.(type) {
+	case string:
+		actual = utf8.RuneCountInString(v)
+	default:
+		rv := reflect.ValueOf(fieldValue)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return fmt.Errorf("expected array or string to check length, got %T", fieldValue)
+		}
+		actual = rv.Len()
+	}
+
+	switch validatorType {
+	case "min_length":
+		if actual < expectedLength {
+			return fmt.Errorf("expected length >= %d, got %d", expectedLength, actual)
+		}
+	case "max_length":
+		if actual > expectedLength {
+			return fmt.Errorf("expected length <= %d, got %d", expectedLength, actual)
+		}
+	default:
+		if actual != expectedLength {
+			return fmt.Errorf("expected length %d, got %d", expectedLength, actual)
+		}
+	}
+	return nil
+}
+
+// validateRange 验证字段数值是否在 [min, max] 闭区间内
+func validateRange(expectedValue, fieldValue interface{}) error {
+	bounds, ok := expectedValue.([]interface{})
+	if !ok || len(bounds) != 2 {
+		return invalidValidatorf("range validator requires a [min, max] list, got %v", expectedValue)
+	}
+	lower, lowerOK := toFloat64(bounds[0])
+	upper, upperOK := toFloat64(bounds[1])
+	if !lowerOK || !upperOK {
+		return invalidValidatorf("range bounds must be numeric, got %v", expectedValue)
+	}
+	if lower > upper {
+		return invalidValidatorf("invalid range: min %v is greater than max %v", bounds[0], bounds[1])
+	}
+
+	actual, ok := toFloat64(fieldValue)
+	if !ok {
+		return fmt.Errorf("expected numeric value in range [%v, %v], got non-numeric %v", bounds[0], bounds[1], fieldValue)
+	}
+	if actual < lower || actual > upper {
+		return fmt.Errorf("expected value in range [%v, %v], got %v", bounds[0], bounds[1], fieldValue)
+	}
+	return nil
+}
+
+// validateMembership 验证字段值是否属于（或不属于）给定的值集合
+func validateMembership(shouldContain bool, expectedValue, fieldValue interface{}) error {
+	allowed, ok := expectedValue.([]interface{})
+	if !ok {
+		return invalidValidatorf("in/not_in validator requires a list of values, got %v", expectedValue)
+	}
+
+	found := false
+	for _, item := range allowed {
+		if compareValues(item, fieldValue) {
+			found = true
+			break
+		}
+	}
+
+	if shouldContain && !found {
+		return fmt.Errorf("expected one of %v, got %v", allowed, fieldValue)
+	}
+	if !shouldContain && found {
+		return fmt.Errorf("expected none of %v, got %v", allowed, fieldValue)
+	}
+	return nil
+}
+
+// numericValidatorOperators 数值比较验证器类型对应的比较运算符
+var numericValidatorOperators = map[string]string{
+	"gt":  ">",
+	"gte": ">=",
+	"lt":  "<",
+	"lte": "<=",
+}
+
+// compareNumber 按运算符比较实际值与阈值
+func compareNumber(op string, threshold float64, actual interface{}) error {
+	actualNum, ok := toFloat64(actual)
+	if !ok {
+		return fmt.Errorf("expected numeric value %s %v, got non-numeric %v", op, threshold, actual)
+	}
+
+	var passed bool
+	switch op {
+	case ">":
+		passed = actualNum > threshold
+	case ">=":
+		passed = actualNum >= threshold
+	case "<":
+		passed = actualNum < threshold
+	case "<=":
+		passed = actualNum <= threshold
+	default:
+		return invalidValidatorf("unknown comparison operator: %s", op)
+	}
+
+	if !passed {
+		return fmt.Errorf("expected %s %v, got %v", op, threshold, actual)
+	}
+	return nil
+}
+
+// toFloat64 将数值或数字字符串转换为 float64
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
 // compareValues 比较两个值是否相等
 func compareValues(expected, actual interface{}) bool {
 	if expected == nil && actual == nil {
@@ -7,7 +7,13 @@ import (
 	"api_auto_test/pkg/client"
 	"api_auto_test/pkg/config"
 	"api_auto_test/pkg/validator"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
 var _ = Describe("Validator", func() {
@@ -96,6 +102,59 @@ var _ = Describe("Validator", func() {
 		})
 	})
 
+	Describe("验证响应体长度", func() {
+		int64Ptr := func(n int64) *int64 { return &n }
+
+		BeforeEach(func() {
+			resp = &client.Response{
+				StatusCode: 200,
+				Headers:    http.Header{},
+				Body:       []byte("0123456789"),
+			}
+		})
+
+		Context("当没有Content-Length响应头时", func() {
+			It("应该使用实际响应体长度", func() {
+				expectation := config.ResponseExpectation{
+					ContentLength: &config.ContentLengthExpectation{Equals: int64Ptr(10)},
+				}
+				v = validator.NewValidator(expectation)
+				result := v.Validate(resp)
+
+				Expect(result.Passed).To(BeTrue())
+			})
+		})
+
+		Context("当长度超出范围时", func() {
+			It("应该验证失败", func() {
+				expectation := config.ResponseExpectation{
+					ContentLength: &config.ContentLengthExpectation{Min: int64Ptr(20), Max: int64Ptr(100)},
+				}
+				v = validator.NewValidator(expectation)
+				result := v.Validate(resp)
+
+				Expect(result.Passed).To(BeFalse())
+				Expect(result.Errors).To(HaveLen(1))
+				Expect(result.Errors[0].Message).To(ContainSubstring("Expected content length >= 20, got 10"))
+			})
+		})
+
+		Context("当Content-Length响应头与实际长度不一致时", func() {
+			It("应该报告响应体被截断", func() {
+				resp.Headers.Set("Content-Length", "2048")
+				expectation := config.ResponseExpectation{
+					ContentLength: &config.ContentLengthExpectation{Min: int64Ptr(1024)},
+				}
+				v = validator.NewValidator(expectation)
+				result := v.Validate(resp)
+
+				Expect(result.Passed).To(BeFalse())
+				Expect(result.Errors).To(HaveLen(1))
+				Expect(result.Errors[0].Message).To(Equal("Content-Length header is 2048 but actual body length is 10"))
+			})
+		})
+	})
+
 	Describe("验证Body字段", func() {
 		BeforeEach(func() {
 			resp = &client.Response{
@@ -152,6 +211,147 @@ var _ = Describe("Validator", func() {
 
 				Expect(result.Passed).To(BeFalse())
 				Expect(result.Errors).To(HaveLen(1))
+				Expect(result.Errors[0].Diffs).To(BeEmpty())
+			})
+		})
+
+		Context("当嵌套对象不匹配时", func() {
+			It("应该逐个列出不一致的字段", func() {
+				expectation := config.ResponseExpectation{
+					Body: map[string]interface{}{
+						"data": map[string]interface{}{"id": "123", "name": "other", "role": "admin"},
+					},
+				}
+				v = validator.NewValidator(expectation)
+				result := v.Validate(resp)
+
+				Expect(result.Passed).To(BeFalse())
+				Expect(result.Errors[0].Message).To(Equal("Field 'data': 2 nested field(s) differ"))
+				Expect(result.Errors[0].Diffs).To(Equal([]validator.FieldDiff{
+					{Path: "data.name", Expected: "other", Actual: "test"},
+					{Path: "data.role", Expected: "admin", Actual: nil},
+				}))
+			})
+		})
+	})
+
+	Describe("验证Body字段数值比较", func() {
+		BeforeEach(func() {
+			resp = &client.Response{
+				StatusCode: 200,
+				Headers:    http.Header{},
+				Body:       []byte(`{"data":{"count":10,"price":99.5,"name":"test"}}`),
+				BodyJSON: map[string]interface{}{
+					"data": map[string]interface{}{
+						"count": float64(10),
+						"price": 99.5,
+						"name":  "test",
+					},
+				},
+			}
+		})
+
+		DescribeTable("比较运算符",
+			func(field string, expected string, passed bool) {
+				expectation := config.ResponseExpectation{
+					Body: map[string]interface{}{
+						field: expected,
+					},
+				}
+				v = validator.NewValidator(expectation)
+				result := v.Validate(resp)
+
+				Expect(result.Passed).To(Equal(passed))
+			},
+			Entry("> 通过", "data.count", ">5", true),
+			Entry("> 失败", "data.count", ">10", false),
+			Entry(">= 通过", "data.count", ">=10", true),
+			Entry(">= 失败", "data.count", ">=11", false),
+			Entry("< 通过", "data.price", "<100", true),
+			Entry("< 失败", "data.price", "<99.5", false),
+			Entry("<= 通过", "data.price", "<=99.5", true),
+			Entry("<= 失败", "data.price", "<=50", false),
+		)
+
+		Context("当字段不是数值时", func() {
+			It("应该验证失败并给出提示", func() {
+				expectation := config.ResponseExpectation{
+					Body: map[string]interface{}{
+						"data.name": ">5",
+					},
+				}
+				v = validator.NewValidator(expectation)
+				result := v.Validate(resp)
+
+				Expect(result.Passed).To(BeFalse())
+				Expect(result.Errors[0].Message).To(ContainSubstring("non-numeric"))
+			})
+		})
+
+		Context("当期望值不是比较表达式时", func() {
+			It("应该按相等比较", func() {
+				expectation := config.ResponseExpectation{
+					Body: map[string]interface{}{
+						"data.name": "test",
+					},
+				}
+				v = validator.NewValidator(expectation)
+				result := v.Validate(resp)
+
+				Expect(result.Passed).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("验证业务成功字段", func() {
+		BeforeEach(func() {
+			resp = &client.Response{
+				StatusCode: 200,
+				Headers:    http.Header{},
+				Body:       []byte(`{"code":1001,"message":"参数错误"}`),
+				BodyJSON: map[string]interface{}{
+					"code":    float64(1001),
+					"message": "参数错误",
+				},
+			}
+		})
+
+		Context("当业务字段不匹配时", func() {
+			It("应该验证失败", func() {
+				expectation := config.ResponseExpectation{
+					StatusCode:   200,
+					SuccessField: &config.SuccessField{Path: "code", Equals: 0},
+				}
+				v = validator.NewValidator(expectation)
+				result := v.Validate(resp)
+
+				Expect(result.Passed).To(BeFalse())
+				Expect(result.Errors).To(HaveLen(1))
+				Expect(result.Errors[0].Field).To(Equal("SuccessField.code"))
+			})
+		})
+
+		Context("当业务字段匹配时", func() {
+			It("应该验证通过", func() {
+				expectation := config.ResponseExpectation{
+					SuccessField: &config.SuccessField{Path: "code", Equals: 1001},
+				}
+				v = validator.NewValidator(expectation)
+				result := v.Validate(resp)
+
+				Expect(result.Passed).To(BeTrue())
+			})
+		})
+
+		Context("当 path 为空时", func() {
+			It("应该跳过业务字段判定", func() {
+				expectation := config.ResponseExpectation{
+					SuccessField: &config.SuccessField{},
+				}
+				v = validator.NewValidator(expectation)
+				result := v.Validate(resp)
+
+				Expect(result.Passed).To(BeTrue())
 			})
 		})
 	})
@@ -223,6 +423,33 @@ var _ = Describe("Validator", func() {
 			})
 		})
 
+		Context("not_equals验证器", func() {
+			It("字段值不等于期望值时应该通过", func() {
+				expectation := config.ResponseExpectation{
+					Validators: []config.Validator{
+						{Type: "not_equals", Field: "count", Value: float64(0)},
+					},
+				}
+				v = validator.NewValidator(expectation)
+				result := v.Validate(resp)
+
+				Expect(result.Passed).To(BeTrue())
+			})
+
+			It("字段值等于期望值时应该失败", func() {
+				expectation := config.ResponseExpectation{
+					Validators: []config.Validator{
+						{Type: "ne", Field: "count", Value: float64(10)},
+					},
+				}
+				v = validator.NewValidator(expectation)
+				result := v.Validate(resp)
+
+				Expect(result.Passed).To(BeFalse())
+				Expect(result.Errors[0].Message).To(Equal("expected not equal to 10, but got 10"))
+			})
+		})
+
 		Context("regex验证器", func() {
 			It("应该正确验证正则表达式", func() {
 				expectation := config.ResponseExpectation{
@@ -259,6 +486,37 @@ var _ = Describe("Validator", func() {
 			})
 		})
 
+		Context("compression_ratio验证器", func() {
+			It("压缩率低于阈值时应该验证通过", func() {
+				resp.Body = make([]byte, 1000)
+				resp.WireSize = 200
+				expectation := config.ResponseExpectation{
+					Validators: []config.Validator{
+						{Type: "compression_ratio", Value: 0.5},
+					},
+				}
+				v = validator.NewValidator(expectation)
+				result := v.Validate(resp)
+
+				Expect(result.Passed).To(BeTrue())
+			})
+
+			It("压缩率超过阈值时应该报告两个大小和压缩率", func() {
+				resp.Body = make([]byte, 1000)
+				resp.WireSize = 1000
+				expectation := config.ResponseExpectation{
+					Validators: []config.Validator{
+						{Type: "compression_ratio", Value: 0.5},
+					},
+				}
+				v = validator.NewValidator(expectation)
+				result := v.Validate(resp)
+
+				Expect(result.Passed).To(BeFalse())
+				Expect(result.Errors[0].Message).To(ContainSubstring("compression ratio 1.000 (wire 1000 bytes / decompressed 1000 bytes)"))
+			})
+		})
+
 		Context("not_empty验证器", func() {
 			It("应该正确验证非空", func() {
 				expectation := config.ResponseExpectation{
@@ -276,4 +534,1117 @@ var _ = Describe("Validator", func() {
 			})
 		})
 	})
+
+	Describe("HEAD请求", func() {
+		BeforeEach(func() {
+			headers := http.Header{}
+			headers.Set("X-Health", "ok")
+			resp = &client.Response{
+				Method:     "HEAD",
+				StatusCode: 200,
+				Headers:    headers,
+			}
+		})
+
+		Context("当只配置状态码和响应头断言时", func() {
+			It("应该验证通过", func() {
+				expectation := config.ResponseExpectation{
+					StatusCode: 200,
+					Headers: map[string]string{
+						"X-Health": "ok",
+					},
+				}
+				v = validator.NewValidator(expectation)
+				result := v.Validate(resp)
+
+				Expect(result.Passed).To(BeTrue())
+				Expect(result.Warnings).To(BeEmpty())
+			})
+		})
+
+		Context("当配置了Body断言时", func() {
+			It("应该跳过Body断言并给出提示", func() {
+				expectation := config.ResponseExpectation{
+					StatusCode:   200,
+					BodyContains: []string{"ok"},
+				}
+				v = validator.NewValidator(expectation)
+				result := v.Validate(resp)
+
+				Expect(result.Passed).To(BeTrue())
+				Expect(result.Warnings).To(ContainElement(ContainSubstring("HEAD has no body")))
+			})
+		})
+	})
+})
+
+var _ = Describe("count_where验证器", func() {
+	var resp *client.Response
+
+	BeforeEach(func() {
+		resp = &client.Response{
+			StatusCode: 200,
+			Headers:    http.Header{},
+			BodyJSON: map[string]interface{}{
+				"data": map[string]interface{}{
+					"items": []interface{}{
+						map[string]interface{}{"id": float64(1), "status": "active"},
+						map[string]interface{}{"id": float64(2), "status": "closed"},
+						map[string]interface{}{"id": float64(3), "status": "active"},
+					},
+					"scores": []interface{}{float64(90), float64(60), float64(95)},
+				},
+			},
+		}
+	})
+
+	validate := func(rule config.Validator) *validator.ValidationResult {
+		expectation := config.ResponseExpectation{Validators: []config.Validator{rule}}
+		return validator.NewValidator(expectation).Validate(resp)
+	}
+
+	It("满足条件的元素数量匹配时应该验证通过", func() {
+		result := validate(config.Validator{
+			Type:  "count_where",
+			Field: "data.items",
+			Where: &config.Validator{Type: "equals", Field: "status", Value: "active"},
+			Value: 2,
+		})
+		Expect(result.Passed).To(BeTrue())
+	})
+
+	It("数量不匹配时应该报告实际数量和条件", func() {
+		result := validate(config.Validator{
+			Type:  "count_where",
+			Field: "data.items",
+			Where: &config.Validator{Type: "equals", Field: "status", Value: "closed"},
+			Value: 2,
+		})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(Equal("expected 2 items where status equals closed, got 1"))
+	})
+
+	It("子条件未指定字段时应该直接验证元素本身", func() {
+		result := validate(config.Validator{
+			Type:  "count_where",
+			Field: "data.scores",
+			Where: &config.Validator{Type: "regex", Value: "^9"},
+			Value: 2,
+		})
+		Expect(result.Passed).To(BeTrue())
+	})
+
+	It("字段不是数组时应该验证失败", func() {
+		result := validate(config.Validator{
+			Type:  "count_where",
+			Field: "data",
+			Where: &config.Validator{Type: "equals", Field: "status", Value: "active"},
+			Value: 1,
+		})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(ContainSubstring("expected array"))
+	})
+})
+
+var _ = Describe("数值比较验证器", func() {
+	resp := &client.Response{
+		StatusCode: 200,
+		Headers:    http.Header{},
+		BodyJSON: map[string]interface{}{
+			"data": map[string]interface{}{
+				"count": float64(5),
+				"price": "19.9",
+				"name":  "widget",
+			},
+		},
+	}
+
+	validate := func(rule config.Validator) *validator.ValidationResult {
+		expectation := config.ResponseExpectation{Validators: []config.Validator{rule}}
+		return validator.NewValidator(expectation).Validate(resp)
+	}
+
+	DescribeTable("比较结果",
+		func(validatorType, field string, value interface{}, shouldPass bool) {
+			result := validate(config.Validator{Type: validatorType, Field: field, Value: value})
+			Expect(result.Passed).To(Equal(shouldPass))
+		},
+		Entry("gt 通过", "gt", "data.count", 4, true),
+		Entry("gt 边界失败", "gt", "data.count", 5, false),
+		Entry("gte 边界通过", "gte", "data.count", 5, true),
+		Entry("lt 通过", "lt", "data.count", 10, true),
+		Entry("lte 边界通过", "lte", "data.count", 5, true),
+		Entry("lte 失败", "lte", "data.count", 4.5, false),
+		Entry("字符串数值", "gte", "data.price", "19.9", true),
+	)
+
+	It("比较失败时应该给出清晰的错误信息", func() {
+		result := validate(config.Validator{Type: "gt", Field: "data.count", Value: 10})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(Equal("expected > 10, got 5"))
+	})
+
+	It("字段值不是数值时应该验证失败而不是panic", func() {
+		result := validate(config.Validator{Type: "lt", Field: "data.name", Value: 10})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(ContainSubstring("non-numeric"))
+	})
+
+	It("字段不存在时应该验证失败", func() {
+		result := validate(config.Validator{Type: "gte", Field: "data.missing", Value: 1})
+		Expect(result.Passed).To(BeFalse())
+	})
+})
+
+var _ = Describe("range验证器", func() {
+	resp := &client.Response{
+		StatusCode: 200,
+		Headers:    http.Header{},
+		BodyJSON: map[string]interface{}{
+			"data": map[string]interface{}{"page_size": float64(20), "name": "widget"},
+		},
+	}
+
+	validate := func(value interface{}) *validator.ValidationResult {
+		rule := config.Validator{Type: "range", Field: "data.page_size", Value: value}
+		expectation := config.ResponseExpectation{Validators: []config.Validator{rule}}
+		return validator.NewValidator(expectation).Validate(resp)
+	}
+
+	It("值在区间内（含边界）时应该验证通过", func() {
+		Expect(validate([]interface{}{1, 100}).Passed).To(BeTrue())
+		Expect(validate([]interface{}{20, 20}).Passed).To(BeTrue())
+	})
+
+	It("值超出区间时应该报告边界和实际值", func() {
+		result := validate([]interface{}{1, 10})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(Equal("expected value in range [1, 10], got 20"))
+	})
+
+	DescribeTable("配置无效时应该报告错误",
+		func(value interface{}, message string) {
+			result := validate(value)
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.Errors[0].Message).To(ContainSubstring(message))
+		},
+		Entry("不是列表", 10, "requires a [min, max] list"),
+		Entry("元素数量不为2", []interface{}{1, 2, 3}, "requires a [min, max] list"),
+		Entry("边界不是数值", []interface{}{"a", 10}, "must be numeric"),
+		Entry("最小值大于最大值", []interface{}{10, 1}, "invalid range"),
+	)
+})
+
+var _ = Describe("length验证器", func() {
+	resp := &client.Response{
+		StatusCode: 200,
+		Headers:    http.Header{},
+		BodyJSON: map[string]interface{}{
+			"data": map[string]interface{}{
+				"items": []interface{}{"a", "b", "c"},
+				"name":  "北京市",
+				"count": float64(3),
+			},
+		},
+	}
+
+	validate := func(validatorType, field string, value interface{}) *validator.ValidationResult {
+		rule := config.Validator{Type: validatorType, Field: field, Value: value}
+		expectation := config.ResponseExpectation{Validators: []config.Validator{rule}}
+		return validator.NewValidator(expectation).Validate(resp)
+	}
+
+	DescribeTable("长度满足条件时应该验证通过",
+		func(validatorType, field string, value interface{}) {
+			Expect(validate(validatorType, field, value).Passed).To(BeTrue())
+		},
+		Entry("数组长度相等", "length", "data.items", 3),
+		Entry("字符串按字符计算长度", "length", "data.name", 3),
+		Entry("不小于最小长度", "min_length", "data.items", 3),
+		Entry("不大于最大长度", "max_length", "data.items", 5),
+		Entry("JSONPath 查询结果", "length", "$.data.items[*]", 3),
+	)
+
+	DescribeTable("长度不满足条件时应该报告期望值和实际值",
+		func(validatorType string, value interface{}, message string) {
+			result := validate(validatorType, "data.items", value)
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.Errors[0].Message).To(Equal(message))
+		},
+		Entry("长度不相等", "length", 2, "expected length 2, got 3"),
+		Entry("小于最小长度", "min_length", 4, "expected length >= 4, got 3"),
+		Entry("大于最大长度", "max_length", 1, "expected length <= 1, got 3"),
+	)
+
+	DescribeTable("字段或配置无效时应该报告错误",
+		func(field string, value interface{}, message string) {
+			result := validate("length", field, value)
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.Errors[0].Message).To(ContainSubstring(message))
+		},
+		Entry("字段不是数组或字符串", "data.count", 3, "expected array or string to check length, got float64"),
+		Entry("字段不存在", "data.missing", 3, "field not found"),
+		Entry("期望长度不是整数", "data.items", 1.5, "invalid expected length"),
+		Entry("期望长度为负数", "data.items", -1, "invalid expected length"),
+	)
+})
+
+var _ = Describe("each验证器", func() {
+	resp := &client.Response{
+		StatusCode: 200,
+		Headers:    http.Header{},
+		BodyJSON: map[string]interface{}{
+			"data": map[string]interface{}{
+				"items": []interface{}{
+					map[string]interface{}{"id": float64(1), "name": "a"},
+					map[string]interface{}{"id": float64(0), "name": ""},
+					map[string]interface{}{"id": float64(3), "name": "c"},
+				},
+				"tags":  []interface{}{"x", "y"},
+				"total": float64(3),
+			},
+		},
+	}
+
+	validate := func(field string, value interface{}) *validator.ValidationResult {
+		rule := config.Validator{Type: "each", Field: field, Value: value}
+		expectation := config.ResponseExpectation{Validators: []config.Validator{rule}}
+		return validator.NewValidator(expectation).Validate(resp)
+	}
+
+	It("所有元素满足子验证器时应该验证通过", func() {
+		result := validate("data.items", []interface{}{
+			map[string]interface{}{"type": "gt", "field": "id", "value": -1},
+			map[string]interface{}{"type": "contains", "field": "name", "value": ""},
+		})
+		Expect(result.Passed).To(BeTrue())
+	})
+
+	It("子验证器未指定字段时应该验证元素本身", func() {
+		result := validate("data.tags", []interface{}{
+			map[string]interface{}{"type": "regex", "value": "^[a-z]$"},
+		})
+		Expect(result.Passed).To(BeTrue())
+	})
+
+	It("应该报告不满足条件的元素下标", func() {
+		result := validate("data.items", []interface{}{
+			map[string]interface{}{"type": "gt", "field": "id", "value": 0},
+			map[string]interface{}{"type": "not_empty", "field": "name"},
+		})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(HavePrefix("1 of 3 items failed: [1].id: "))
+		Expect(result.Errors[0].Message).To(ContainSubstring("; [1].name: field should not be empty"))
+	})
+
+	DescribeTable("字段或配置无效时应该报告错误",
+		func(field string, value interface{}, message string) {
+			result := validate(field, value)
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.Errors[0].Message).To(ContainSubstring(message))
+		},
+		Entry("字段不是数组", "data.total", []interface{}{map[string]interface{}{"type": "not_empty"}}, "expected array, got float64"),
+		Entry("value 不是验证器列表", "data.items", "gt", "requires a list of validators"),
+		Entry("子验证器缺少类型", "data.items", []interface{}{map[string]interface{}{"field": "id"}}, "missing 'type'"),
+	)
+})
+
+var _ = Describe("exists/not_exists验证器", func() {
+	resp := &client.Response{
+		StatusCode: 200,
+		Headers:    http.Header{},
+		BodyJSON: map[string]interface{}{
+			"data": map[string]interface{}{
+				"token":   "abc",
+				"enabled": false,
+				"count":   float64(0),
+				"remark":  "",
+				"parent":  nil,
+				"items":   []interface{}{map[string]interface{}{"id": float64(1)}},
+			},
+		},
+	}
+
+	validate := func(validatorType, field string) *validator.ValidationResult {
+		rule := config.Validator{Type: validatorType, Field: field}
+		expectation := config.ResponseExpectation{Validators: []config.Validator{rule}}
+		return validator.NewValidator(expectation).Validate(resp)
+	}
+
+	DescribeTable("字段存在时（包括零值和 null）exists 应该通过、not_exists 应该失败",
+		func(field string) {
+			Expect(validate("exists", field).Passed).To(BeTrue())
+			Expect(validate("not_exists", field).Passed).To(BeFalse())
+		},
+		Entry("字符串", "data.token"),
+		Entry("false", "data.enabled"),
+		Entry("0", "data.count"),
+		Entry("空字符串", "data.remark"),
+		Entry("null", "data.parent"),
+		Entry("JSONPath", "$.data.items[0].id"),
+		Entry("值为 null 的 JSONPath", "$.data.parent"),
+	)
+
+	DescribeTable("字段缺失时 exists 应该失败、not_exists 应该通过",
+		func(field string) {
+			Expect(validate("exists", field).Passed).To(BeFalse())
+			Expect(validate("not_exists", field).Passed).To(BeTrue())
+		},
+		Entry("缺失的键", "data.refresh_token"),
+		Entry("父字段不是对象", "data.token.value"),
+		Entry("JSONPath 无匹配", "$.data.items[5].id"),
+	)
+
+	It("应该在错误信息中说明字段", func() {
+		Expect(validate("exists", "data.refresh_token").Errors[0].Message).To(Equal("field 'data.refresh_token' does not exist"))
+		Expect(validate("not_exists", "data.token").Errors[0].Message).To(Equal("field 'data.token' should not exist, got abc"))
+	})
+
+	It("缺少字段时即使取反也应该失败", func() {
+		for _, validatorType := range []string{"exists", "not_exists"} {
+			rule := config.Validator{Type: validatorType, Not: true}
+			expectation := config.ResponseExpectation{Validators: []config.Validator{rule}}
+			result := validator.NewValidator(expectation).Validate(resp)
+			Expect(result.Passed).To(BeFalse(), validatorType)
+			Expect(result.Errors[0].Message).To(ContainSubstring("existence validator requires a field"))
+		}
+	})
+})
+
+var _ = Describe("contains验证选项", func() {
+	resp := &client.Response{
+		StatusCode: 200,
+		Headers:    http.Header{},
+		BodyJSON:   map[string]interface{}{"message": "  Order CREATED successfully  "},
+	}
+
+	validate := func(rule config.Validator) *validator.ValidationResult {
+		rule.Field = "message"
+		expectation := config.ResponseExpectation{Validators: []config.Validator{rule}}
+		return validator.NewValidator(expectation).Validate(resp)
+	}
+
+	It("默认应该区分大小写", func() {
+		result := validate(config.Validator{Type: "contains", Value: "created"})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(Equal("expected to contain 'created', got '  Order CREATED successfully  '"))
+	})
+
+	It("ignore_case 时应该忽略大小写", func() {
+		Expect(validate(config.Validator{Type: "contains", Value: "created", IgnoreCase: true}).Passed).To(BeTrue())
+	})
+
+	It("icontains 应该等同于 ignore_case", func() {
+		Expect(validate(config.Validator{Type: "icontains", Value: "order created"}).Passed).To(BeTrue())
+	})
+
+	It("trim 时应该去除首尾空白后比较", func() {
+		Expect(validate(config.Validator{Type: "contains", Value: " Order CREATED successfully  \n"}).Passed).To(BeFalse())
+		Expect(validate(config.Validator{Type: "contains", Value: " Order CREATED successfully  \n", Trim: true}).Passed).To(BeTrue())
+	})
+
+	It("失败信息中应该说明启用的选项", func() {
+		result := validate(config.Validator{Type: "icontains", Value: "failed", Trim: true})
+		Expect(result.Errors[0].Message).To(ContainSubstring("expected to contain 'failed' (ignoring case, trimmed)"))
+	})
+})
+
+var _ = Describe("starts_with/ends_with验证器", func() {
+	resp := &client.Response{
+		StatusCode: 200,
+		Headers:    http.Header{},
+		BodyJSON: map[string]interface{}{
+			"url":   "https://example.com/avatar.PNG",
+			"order": " ORD-2024-001 ",
+		},
+	}
+
+	validate := func(rule config.Validator) *validator.ValidationResult {
+		expectation := config.ResponseExpectation{Validators: []config.Validator{rule}}
+		return validator.NewValidator(expectation).Validate(resp)
+	}
+
+	It("前缀或后缀匹配时应该通过", func() {
+		Expect(validate(config.Validator{Type: "starts_with", Field: "url", Value: "https://"}).Passed).To(BeTrue())
+		Expect(validate(config.Validator{Type: "ends_with", Field: "url", Value: ".PNG"}).Passed).To(BeTrue())
+	})
+
+	It("不匹配时应该在错误信息中给出前缀或后缀和实际值", func() {
+		result := validate(config.Validator{Type: "starts_with", Field: "url", Value: "http://"})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(Equal("expected to start with 'http://', got 'https://example.com/avatar.PNG'"))
+
+		result = validate(config.Validator{Type: "ends_with", Field: "url", Value: ".png"})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(Equal("expected to end with '.png', got 'https://example.com/avatar.PNG'"))
+	})
+
+	It("应该支持 ignore_case 和 trim", func() {
+		Expect(validate(config.Validator{Type: "ends_with", Field: "url", Value: ".png", IgnoreCase: true}).Passed).To(BeTrue())
+		Expect(validate(config.Validator{Type: "starts_with", Field: "order", Value: "ord-"}).Passed).To(BeFalse())
+		Expect(validate(config.Validator{Type: "starts_with", Field: "order", Value: "ord-", IgnoreCase: true, Trim: true}).Passed).To(BeTrue())
+		Expect(validate(config.Validator{Type: "ends_with", Field: "order", Value: "001", Trim: true}).Passed).To(BeTrue())
+	})
+})
+
+var _ = Describe("type验证器", func() {
+	resp := &client.Response{
+		StatusCode: 200,
+		Headers:    http.Header{},
+		BodyJSON: map[string]interface{}{
+			"name":     "tom",
+			"count":    float64(3),
+			"price":    9.5,
+			"enabled":  true,
+			"tags":     []interface{}{"a"},
+			"profile":  map[string]interface{}{"age": float64(18)},
+			"parent":   nil,
+			"unsigned": uint(5),
+		},
+	}
+
+	validate := func(field, expectedType string) *validator.ValidationResult {
+		expectation := config.ResponseExpectation{Validators: []config.Validator{{Type: "type", Field: field, Value: expectedType}}}
+		return validator.NewValidator(expectation).Validate(resp)
+	}
+
+	DescribeTable("JSON 类型名称匹配时应该通过",
+		func(field, expectedType string) {
+			Expect(validate(field, expectedType).Passed).To(BeTrue())
+		},
+		Entry("string", "name", "string"),
+		Entry("整数是 number", "count", "number"),
+		Entry("整数是 integer", "count", "integer"),
+		Entry("小数是 number", "price", "number"),
+		Entry("boolean", "enabled", "boolean"),
+		Entry("array", "tags", "array"),
+		Entry("object", "profile", "object"),
+		Entry("null", "parent", "null"),
+		Entry("兼容 Go 类型名", "count", "float64"),
+		Entry("兼容 Kind", "tags", "slice"),
+	)
+
+	DescribeTable("类型不匹配时应该失败",
+		func(field, expectedType, message string) {
+			result := validate(field, expectedType)
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.Errors[0].Message).To(Equal(message))
+		},
+		Entry("小数不是 integer", "price", "integer", "expected type integer, got number"),
+		Entry("null 不是 object", "parent", "object", "expected type object, got null"),
+		Entry("字符串不是 number", "name", "number", "expected type number, got string"),
+		// 以下写法之前因为包含匹配而错误地通过
+		Entry("int 不匹配 uint", "unsigned", "int", "expected type int, got uint"),
+		Entry("interface 不匹配数组", "tags", "interface", "expected type interface, got []interface {}"),
+		Entry("str 不匹配 string", "name", "str", "expected type str, got string"),
+		Entry("float 不匹配 float64", "price", "float", "expected type float, got float64"),
+	)
+})
+
+var _ = Describe("not取反验证器", func() {
+	resp := &client.Response{
+		StatusCode: 200,
+		Headers:    http.Header{},
+		BodyJSON: map[string]interface{}{
+			"msg":   "order created",
+			"items": []interface{}{map[string]interface{}{"status": "ok"}, map[string]interface{}{"status": "error"}},
+		},
+	}
+
+	validate := func(rule config.Validator) *validator.ValidationResult {
+		expectation := config.ResponseExpectation{Validators: []config.Validator{rule}}
+		return validator.NewValidator(expectation).Validate(resp)
+	}
+
+	It("原验证不通过时应该通过", func() {
+		Expect(validate(config.Validator{Type: "contains", Field: "msg", Value: "error", Not: true}).Passed).To(BeTrue())
+	})
+
+	It("原验证通过时应该失败并说明断言被取反", func() {
+		result := validate(config.Validator{Type: "contains", Field: "msg", Value: "created", Not: true})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(Equal("expected not (msg contains created), but it passed"))
+	})
+
+	It("应该可以用于 each 的子验证器", func() {
+		result := validate(config.Validator{
+			Type:  "each",
+			Field: "items",
+			Value: []interface{}{map[string]interface{}{"type": "equals", "field": "status", "value": "error", "not": true}},
+		})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(ContainSubstring("1 of 2 items failed: [1].status: expected not (status equals error), but it passed"))
+	})
+
+	It("验证器配置错误时不应该被取反为通过", func() {
+		result := validate(config.Validator{Type: "regex", Field: "msg", Value: "(", Not: true})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(ContainSubstring("invalid regex pattern"))
+
+		result = validate(config.Validator{Type: "unknown", Field: "msg", Not: true})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(Equal("unknown validator type: unknown"))
+	})
+})
+
+var _ = Describe("响应头验证器", func() {
+	resp := &client.Response{
+		StatusCode: 200,
+		Headers: http.Header{
+			"Set-Cookie":    []string{"session=abc; Path=/", "token=xyz; Path=/; HttpOnly"},
+			"Cache-Control": []string{"public, max-age=3600"},
+		},
+	}
+
+	validate := func(rules ...config.Validator) *validator.ValidationResult {
+		expectation := config.ResponseExpectation{HeaderValidators: rules}
+		return validator.NewValidator(expectation).Validate(resp)
+	}
+
+	DescribeTable("任一值满足条件时应该验证通过",
+		func(rule config.Validator) {
+			Expect(validate(rule).Passed).To(BeTrue())
+		},
+		Entry("contains 匹配第二个值", config.Validator{Field: "Set-Cookie", Type: "contains", Value: "HttpOnly"}),
+		Entry("icontains", config.Validator{Field: "set-cookie", Type: "icontains", Value: "httponly"}),
+		Entry("regex", config.Validator{Field: "Cache-Control", Type: "regex", Value: `max-age=\d+`}),
+		Entry("equals", config.Validator{Field: "Set-Cookie", Type: "equals", Value: "session=abc; Path=/"}),
+		Entry("equals 忽略大小写", config.Validator{Field: "Cache-Control", Type: "equals", Value: "PUBLIC, MAX-AGE=3600", IgnoreCase: true}),
+	)
+
+	It("没有值满足条件时应该报告所有实际值", func() {
+		result := validate(config.Validator{Field: "Set-Cookie", Type: "contains", Value: "Secure"})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Field).To(Equal("Header[Set-Cookie]"))
+		Expect(result.Errors[0].Message).To(Equal(
+			"expected a Set-Cookie header value to contain 'Secure', got [session=abc; Path=/; token=xyz; Path=/; HttpOnly]"))
+	})
+
+	It("响应头缺失时应该验证失败", func() {
+		result := validate(config.Validator{Field: "ETag", Type: "regex", Value: ".+"})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(ContainSubstring("header ETag is missing"))
+	})
+
+	It("警告级别只记录警告", func() {
+		result := validate(config.Validator{Field: "ETag", Type: "regex", Value: ".+", Severity: "warning"})
+		Expect(result.Passed).To(BeTrue())
+		Expect(result.Warnings).To(HaveLen(1))
+	})
+
+	It("exists 和 not_exists 只检查响应头是否出现", func() {
+		Expect(validate(config.Validator{Field: "cache-control", Type: "exists"}).Passed).To(BeTrue())
+		Expect(validate(config.Validator{Field: "ETag", Type: "not_exists"}).Passed).To(BeTrue())
+
+		result := validate(
+			config.Validator{Field: "ETag", Type: "exists"},
+			config.Validator{Field: "Cache-Control", Type: "not_exists"},
+		)
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(Equal("header ETag is missing"))
+		Expect(result.Errors[1].Message).To(Equal("expected header Cache-Control to be absent, got [public, max-age=3600]"))
+	})
+
+	It("不支持的类型应该报告错误", func() {
+		result := validate(config.Validator{Field: "ETag", Type: "gt", Value: 1})
+		Expect(result.Errors[0].Message).To(ContainSubstring("unsupported header validator type: gt"))
+	})
+})
+
+var _ = Describe("状态码范围和列表", func() {
+	validate := func(statusCode int, expectation config.ResponseExpectation) *validator.ValidationResult {
+		resp := &client.Response{StatusCode: statusCode, Headers: http.Header{}}
+		return validator.NewValidator(expectation).Validate(resp)
+	}
+
+	DescribeTable("状态码匹配",
+		func(statusCode int, expectation config.ResponseExpectation, shouldPass bool) {
+			Expect(validate(statusCode, expectation).Passed).To(Equal(shouldPass))
+		},
+		Entry("列表命中", 201, config.ResponseExpectation{StatusCodeIn: []int{200, 201}}, true),
+		Entry("列表未命中", 204, config.ResponseExpectation{StatusCodeIn: []int{200, 201}}, false),
+		Entry("范围命中", 204, config.ResponseExpectation{StatusCodeRange: "200-299"}, true),
+		Entry("范围边界", 299, config.ResponseExpectation{StatusCodeRange: "200-299"}, true),
+		Entry("范围未命中", 301, config.ResponseExpectation{StatusCodeRange: "200-299"}, false),
+		Entry("2xx 写法", 202, config.ResponseExpectation{StatusCodeRange: "2xx"}, true),
+		Entry("精确状态码与列表取或", 200, config.ResponseExpectation{StatusCode: 200, StatusCodeIn: []int{404}}, true),
+		Entry("列表与范围取或", 404, config.ResponseExpectation{StatusCodeIn: []int{404}, StatusCodeRange: "2xx"}, true),
+	)
+
+	It("不匹配时应该列出所有允许的形式", func() {
+		result := validate(500, config.ResponseExpectation{StatusCode: 200, StatusCodeIn: []int{201, 202}, StatusCodeRange: "300-399"})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(Equal("Expected status code 200 or one of [201 202] or in range 300-399, got 500"))
+	})
+
+	It("只配置精确状态码时应该保持原有行为", func() {
+		result := validate(404, config.ResponseExpectation{StatusCode: 200})
+		Expect(result.Errors[0].Message).To(Equal("Expected status code 200, got 404"))
+	})
+
+	It("范围格式无效时应该报告错误", func() {
+		result := validate(200, config.ResponseExpectation{StatusCodeRange: "2oo-299"})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Field).To(Equal("StatusCodeRange"))
+	})
+})
+
+var _ = Describe("in/not_in验证器", func() {
+	resp := &client.Response{
+		StatusCode: 200,
+		Headers:    http.Header{},
+		BodyJSON: map[string]interface{}{
+			"data": map[string]interface{}{"status": "pending", "level": float64(2)},
+		},
+	}
+
+	validate := func(rule config.Validator) *validator.ValidationResult {
+		expectation := config.ResponseExpectation{Validators: []config.Validator{rule}}
+		return validator.NewValidator(expectation).Validate(resp)
+	}
+
+	DescribeTable("成员判断",
+		func(validatorType, field string, value interface{}, shouldPass bool) {
+			result := validate(config.Validator{Type: validatorType, Field: field, Value: value})
+			Expect(result.Passed).To(Equal(shouldPass))
+		},
+		Entry("in 命中", "in", "data.status", []interface{}{"active", "pending", "closed"}, true),
+		Entry("in 未命中", "in", "data.status", []interface{}{"active", "closed"}, false),
+		Entry("in 数值", "in", "data.level", []interface{}{1, 2, 3}, true),
+		Entry("not_in 未命中", "not_in", "data.status", []interface{}{"deleted"}, true),
+		Entry("not_in 命中", "not_in", "data.level", []interface{}{2}, false),
+	)
+
+	It("失败时应该列出允许的集合和实际值", func() {
+		result := validate(config.Validator{Type: "in", Field: "data.status", Value: []interface{}{"active", "closed"}})
+		Expect(result.Errors[0].Message).To(Equal("expected one of [active closed], got pending"))
+	})
+
+	It("期望值不是列表时应该报告配置错误", func() {
+		result := validate(config.Validator{Type: "in", Field: "data.status", Value: "active"})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(ContainSubstring("requires a list"))
+	})
+})
+
+var _ = Describe("摘要验证器", func() {
+	// sha256("hello") / md5("hello")
+	const (
+		helloSHA256 = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+		helloMD5    = "5d41402abc4b2a76b9719d911017c592"
+	)
+
+	validate := func(resp *client.Response, rule config.Validator) *validator.ValidationResult {
+		expectation := config.ResponseExpectation{Validators: []config.Validator{rule}}
+		return validator.NewValidator(expectation).Validate(resp)
+	}
+
+	rawResponse := &client.Response{StatusCode: 200, Headers: http.Header{}, Body: []byte("hello")}
+
+	It("原始响应体的 sha256 摘要匹配时应该验证通过", func() {
+		result := validate(rawResponse, config.Validator{Type: "sha256", Value: helloSHA256})
+		Expect(result.Passed).To(BeTrue())
+	})
+
+	It("应该忽略期望摘要的大小写", func() {
+		result := validate(rawResponse, config.Validator{Type: "md5", Value: strings.ToUpper(helloMD5)})
+		Expect(result.Passed).To(BeTrue())
+	})
+
+	It("摘要不匹配时应该报告计算出的摘要", func() {
+		result := validate(rawResponse, config.Validator{Type: "md5", Value: "0123456789abcdef0123456789abcdef"})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(ContainSubstring(helloMD5))
+	})
+
+	It("指定字段时应该对字段值计算摘要", func() {
+		resp := &client.Response{
+			StatusCode: 200,
+			Headers:    http.Header{},
+			Body:       []byte(`{"data":{"content":"hello"}}`),
+			BodyJSON: map[string]interface{}{
+				"data": map[string]interface{}{"content": "hello"},
+			},
+		}
+		result := validate(resp, config.Validator{Type: "sha256", Field: "data.content", Value: helloSHA256})
+		Expect(result.Passed).To(BeTrue())
+	})
+
+	It("字段不存在时应该验证失败", func() {
+		result := validate(rawResponse, config.Validator{Type: "sha256", Field: "data.content", Value: helloSHA256})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(ContainSubstring("not found"))
+	})
+})
+
+var _ = Describe("JSONPath字段", func() {
+	resp := &client.Response{
+		StatusCode: 200,
+		Headers:    http.Header{},
+		BodyJSON: map[string]interface{}{
+			"data": map[string]interface{}{
+				"user": map[string]interface{}{"id": float64(7)},
+				"items": []interface{}{
+					map[string]interface{}{"id": float64(1), "name": "apple", "price": float64(5), "note": "a==b"},
+					map[string]interface{}{"id": float64(2), "name": "banana", "price": float64(12), "note": "x>=y"},
+					map[string]interface{}{"id": float64(3), "name": "cherry", "price": float64(20), "note": "plain"},
+				},
+			},
+		},
+	}
+
+	validate := func(rule config.Validator) *validator.ValidationResult {
+		expectation := config.ResponseExpectation{Validators: []config.Validator{rule}}
+		return validator.NewValidator(expectation).Validate(resp)
+	}
+
+	DescribeTable("应该按 JSONPath 查询字段",
+		func(field string, expected interface{}) {
+			result := validate(config.Validator{Type: "equals", Field: field, Value: expected})
+			Expect(result.Passed).To(BeTrue(), "%v", result.Errors)
+		},
+		Entry("数组下标", "$.data.items[0].id", 1),
+		Entry("负数下标", "$.data.items[-1].name", "cherry"),
+		Entry("带引号的键名", "$['data']['user']['id']", 7),
+		Entry("通配符", "$.data.items[*].name", []interface{}{"apple", "banana", "cherry"}),
+		Entry("递归查找", "$..user.id", []interface{}{7}),
+		Entry("数值过滤", "$.data.items[?(@.price > 10)].id", []interface{}{2, 3}),
+		Entry("字符串过滤", "$.data.items[?(@.name == 'banana')].price", []interface{}{12}),
+		Entry("引号内的运算符", "$.data.items[?(@.note != 'a==b')].id", []interface{}{2, 3}),
+		Entry("引号内的比较运算符", `$.data.items[?(@.note == "x>=y")].name`, []interface{}{"banana"}),
+	)
+
+	It("没有匹配时字段值应该为空", func() {
+		result := validate(config.Validator{Type: "not_empty", Field: "$.data.items[?(@.price > 100)]"})
+		Expect(result.Passed).To(BeFalse())
+	})
+
+	It("JSONPath 语法错误时应该报告验证错误", func() {
+		result := validate(config.Validator{Type: "equals", Field: "$.data.items[abc]", Value: 1})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(ContainSubstring("invalid JSONPath"))
+	})
+
+	It("普通点号路径应该保持原有行为", func() {
+		result := validate(config.Validator{Type: "equals", Field: "data.user.id", Value: 7})
+		Expect(result.Passed).To(BeTrue())
+	})
+})
+
+var _ = Describe("响应时间告警", func() {
+	validate := func(duration, threshold time.Duration) *validator.ValidationResult {
+		resp := &client.Response{StatusCode: 200, Headers: http.Header{}, Duration: duration}
+		expectation := config.ResponseExpectation{StatusCode: 200, WarnResponseTime: threshold}
+		return validator.NewValidator(expectation).Validate(resp)
+	}
+
+	It("超过告警阈值时应该记录警告但不判定失败", func() {
+		result := validate(800*time.Millisecond, 500*time.Millisecond)
+		Expect(result.Passed).To(BeTrue())
+		Expect(result.Warnings).To(ConsistOf("response time 800ms exceeded warning threshold 500ms"))
+	})
+
+	It("未超过告警阈值时不应该记录警告", func() {
+		result := validate(200*time.Millisecond, 500*time.Millisecond)
+		Expect(result.Warnings).To(BeEmpty())
+	})
+
+	It("未配置告警阈值时不应该记录警告", func() {
+		result := validate(10*time.Second, 0)
+		Expect(result.Warnings).To(BeEmpty())
+	})
+})
+
+var _ = Describe("验证警告", func() {
+	resp := &client.Response{
+		StatusCode: 200,
+		Headers:    http.Header{},
+		BodyJSON: map[string]interface{}{
+			"data": map[string]interface{}{"id": float64(1), "legacy_id": "u-1"},
+		},
+	}
+
+	It("警告级别的验证器失败时应该只记录警告", func() {
+		expectation := config.ResponseExpectation{
+			Validators: []config.Validator{
+				{Type: "equals", Field: "data.id", Value: 2, Severity: "warning"},
+			},
+		}
+		result := validator.NewValidator(expectation).Validate(resp)
+		Expect(result.Passed).To(BeTrue())
+		Expect(result.Errors).To(BeEmpty())
+		Expect(result.Warnings).To(ConsistOf("data.id: expected 2, got 1"))
+	})
+
+	It("未设置严重级别的验证器失败时应该判定失败", func() {
+		expectation := config.ResponseExpectation{
+			Validators: []config.Validator{{Type: "equals", Field: "data.id", Value: 2}},
+		}
+		result := validator.NewValidator(expectation).Validate(resp)
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Warnings).To(BeEmpty())
+	})
+
+	It("响应中包含已废弃字段时应该记录警告", func() {
+		expectation := config.ResponseExpectation{DeprecatedFields: []string{"data.legacy_id", "data.old_name"}}
+		result := validator.NewValidator(expectation).Validate(resp)
+		Expect(result.Passed).To(BeTrue())
+		Expect(result.Warnings).To(ConsistOf("deprecated field 'data.legacy_id' is present in response"))
+	})
+})
+
+var _ = Describe("JSON Schema验证", func() {
+	const userSchema = `{
+		"type": "object",
+		"required": ["code", "data"],
+		"properties": {
+			"code": {"type": "integer"},
+			"data": {
+				"type": "object",
+				"required": ["id", "name"],
+				"additionalProperties": false,
+				"properties": {
+					"id": {"type": "integer", "minimum": 1},
+					"name": {"type": "string", "minLength": 1},
+					"tags": {"type": "array", "items": {"$ref": "#/definitions/tag"}}
+				}
+			}
+		},
+		"definitions": {
+			"tag": {"type": "string", "enum": ["vip", "new"]}
+		}
+	}`
+
+	validate := func(schema string, body string) *validator.ValidationResult {
+		resp := &client.Response{StatusCode: 200, Headers: http.Header{}, Body: []byte(body)}
+		var parsed map[string]interface{}
+		if json.Unmarshal([]byte(body), &parsed) == nil {
+			resp.BodyJSON = parsed
+		}
+		return validator.NewValidator(config.ResponseExpectation{JSONSchema: schema}).Validate(resp)
+	}
+
+	It("响应符合 Schema 时应该验证通过", func() {
+		result := validate(userSchema, `{"code": 0, "data": {"id": 1, "name": "tom", "tags": ["vip"]}}`)
+		Expect(result.Passed).To(BeTrue(), "%v", result.Errors)
+	})
+
+	It("每个违反项应该以 JSON Pointer 作为字段报告", func() {
+		result := validate(userSchema, `{"code": "0", "data": {"id": 0, "extra": true, "tags": ["old"]}}`)
+		Expect(result.Passed).To(BeFalse())
+
+		fields := make([]string, 0)
+		for _, err := range result.Errors {
+			fields = append(fields, err.Field)
+		}
+		Expect(fields).To(ConsistOf("/code", "/data/name", "/data/extra", "/data/id", "/data/tags/0"))
+	})
+
+	It("应该支持顶层数组", func() {
+		result := validate(`{"type": "array", "minItems": 2}`, `[1]`)
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Field).To(Equal("/"))
+		Expect(result.Errors[0].Message).To(Equal("expected at least 2 items, got 1"))
+	})
+
+	It("应该支持从文件加载 Schema", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "user.schema.json")
+		Expect(os.WriteFile(path, []byte(userSchema), 0644)).To(Succeed())
+		result := validate(path, `{"code": 0, "data": {"id": 1, "name": "tom"}}`)
+		Expect(result.Passed).To(BeTrue(), "%v", result.Errors)
+	})
+
+	It("响应体不是 JSON 时应该只报告一个错误", func() {
+		result := validate(userSchema, `<html>error</html>`)
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors).To(HaveLen(1))
+		Expect(result.Errors[0].Field).To(Equal("Body"))
+	})
+
+	It("Schema 无法加载时应该报告错误", func() {
+		result := validate("missing.schema.json", `{}`)
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Field).To(Equal("JSONSchema"))
+	})
+})
+
+var _ = Describe("body_is_json", func() {
+	validate := func(body string) *validator.ValidationResult {
+		resp := &client.Response{StatusCode: 200, Headers: http.Header{}, Body: []byte(body)}
+		return validator.NewValidator(config.ResponseExpectation{BodyIsJSON: true}).Validate(resp)
+	}
+
+	It("合法的 JSON 应该验证通过", func() {
+		Expect(validate(`{"id":1}`).Passed).To(BeTrue())
+		Expect(validate("\ufeff [1, 2]").Passed).To(BeTrue())
+	})
+
+	It("空响应体应该验证通过", func() {
+		Expect(validate("").Passed).To(BeTrue())
+	})
+
+	It("截断的 JSON 应该验证失败并报告解析错误", func() {
+		result := validate(`{"data":{"id":1`)
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Field).To(Equal("Body"))
+		Expect(result.Errors[0].Message).To(Equal("Response body is not valid JSON: unexpected end of JSON input"))
+	})
+
+	It("未开启时不检查响应体", func() {
+		resp := &client.Response{StatusCode: 200, Headers: http.Header{}, Body: []byte("<html>")}
+		Expect(validator.NewValidator(config.ResponseExpectation{}).Validate(resp).Passed).To(BeTrue())
+	})
+})
+
+var _ = Describe("body_golden", func() {
+	var goldenFile string
+
+	BeforeEach(func() {
+		goldenFile = filepath.Join(GinkgoT().TempDir(), "user.golden.json")
+		golden := `{"data": {"id": 1, "name": "tom", "roles": ["admin"], "created_at": "2024-01-01"}}`
+		Expect(os.WriteFile(goldenFile, []byte(golden), 0644)).To(Succeed())
+	})
+
+	validate := func(body string, ignoreFields ...string) *validator.ValidationResult {
+		resp := &client.Response{StatusCode: 200, Headers: http.Header{}, Body: []byte(body)}
+		expectation := config.ResponseExpectation{BodyGolden: goldenFile, IgnoreFields: ignoreFields}
+		return validator.NewValidator(expectation).Validate(resp)
+	}
+
+	It("忽略指定字段后一致时应该验证通过", func() {
+		body := `{"data": {"id": 7, "name": "tom", "roles": ["admin"], "created_at": "2025-06-30"}}`
+		Expect(validate(body, "data.id", "data.created_at").Passed).To(BeTrue())
+	})
+
+	It("不一致时应该报告每个字段的差异", func() {
+		body := `{"data": {"id": 7, "name": "jerry", "roles": ["admin", "dev"], "created_at": "2025-06-30", "extra": true}}`
+		result := validate(body, "data.id", "data.created_at")
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors).To(HaveLen(1))
+		Expect(result.Errors[0].Field).To(Equal("BodyGolden"))
+		Expect(result.Errors[0].Message).To(Equal(fmt.Sprintf("Response body differs from golden file '%s': 3 field(s) differ", goldenFile)))
+		Expect(result.Errors[0].Diffs).To(Equal([]validator.FieldDiff{
+			{Path: "data.extra", Expected: nil, Actual: true},
+			{Path: "data.name", Expected: "tom", Actual: "jerry"},
+			{Path: "data.roles", Expected: []interface{}{"admin"}, Actual: []interface{}{"admin", "dev"}},
+		}))
+	})
+
+	It("golden 文件不存在时应该验证失败", func() {
+		resp := &client.Response{StatusCode: 200, Headers: http.Header{}, Body: []byte(`{}`)}
+		expectation := config.ResponseExpectation{BodyGolden: filepath.Join(GinkgoT().TempDir(), "missing.json")}
+		result := validator.NewValidator(expectation).Validate(resp)
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(ContainSubstring("failed to read golden file"))
+	})
+
+	It("响应体不是 JSON 时应该验证失败", func() {
+		result := validate("<html>")
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Field).To(Equal("Body"))
+	})
+
+	It("UpdateGolden 应该写入格式化的响应体", func() {
+		resp := &client.Response{Body: []byte(`[{"url":"https://x.test/?a=1&b=<2>"}]`)}
+		Expect(validator.UpdateGolden(goldenFile, resp)).To(Succeed())
+		data, err := os.ReadFile(goldenFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal("[\n  {\n    \"url\": \"https://x.test/?a=1&b=<2>\"\n  }\n]\n"))
+		Expect(validate(`[{"url":"https://x.test/?a=1&b=<2>"}]`).Passed).To(BeTrue())
+	})
+})
+
+var _ = Describe("原始响应体验证", func() {
+	var resp *client.Response
+
+	BeforeEach(func() {
+		resp = &client.Response{
+			StatusCode: 200,
+			Headers:    http.Header{"Content-Type": []string{"application/xml"}},
+			Body:       []byte(`<user><id>42</id><name>alice</name></user>`),
+		}
+	})
+
+	It("body_equals 完全相等时应该验证通过", func() {
+		expectation := config.ResponseExpectation{BodyEquals: `<user><id>42</id><name>alice</name></user>`}
+		Expect(validator.NewValidator(expectation).Validate(resp).Passed).To(BeTrue())
+	})
+
+	It("body_equals 不相等时应该验证失败", func() {
+		expectation := config.ResponseExpectation{BodyEquals: "OK"}
+		result := validator.NewValidator(expectation).Validate(resp)
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Field).To(Equal("Body"))
+	})
+
+	It("body_regex 匹配时应该验证通过", func() {
+		expectation := config.ResponseExpectation{BodyRegex: `<id>\d+</id>`}
+		Expect(validator.NewValidator(expectation).Validate(resp).Passed).To(BeTrue())
+	})
+
+	It("body_regex 不匹配时应该验证失败", func() {
+		expectation := config.ResponseExpectation{BodyRegex: `^OK$`}
+		result := validator.NewValidator(expectation).Validate(resp)
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Message).To(ContainSubstring("does not match pattern"))
+	})
+
+	It("正则表达式无效时应该报告错误", func() {
+		expectation := config.ResponseExpectation{BodyRegex: `(`}
+		result := validator.NewValidator(expectation).Validate(resp)
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Errors[0].Field).To(Equal("BodyRegex"))
+	})
+})
+
+var _ = Describe("DiffValues", func() {
+	It("相同的值应该没有差异", func() {
+		a := map[string]interface{}{"id": float64(1), "tags": []interface{}{"a", "b"}}
+		b := map[string]interface{}{"id": float64(1), "tags": []interface{}{"a", "b"}}
+		Expect(validator.DiffValues(a, b, nil)).To(BeEmpty())
+	})
+
+	It("应该报告嵌套字段和数组元素的差异", func() {
+		a := map[string]interface{}{
+			"data": map[string]interface{}{
+				"name":  "test",
+				"items": []interface{}{map[string]interface{}{"id": float64(1)}},
+			},
+		}
+		b := map[string]interface{}{
+			"data": map[string]interface{}{
+				"name":  "changed",
+				"items": []interface{}{map[string]interface{}{"id": float64(2)}},
+			},
+		}
+		diffs := validator.DiffValues(a, b, nil)
+		paths := []string{}
+		for _, d := range diffs {
+			paths = append(paths, d.Path)
+		}
+		Expect(paths).To(Equal([]string{"data.items[0].id", "data.name"}))
+	})
+
+	It("应该报告只在一侧存在的字段", func() {
+		a := map[string]interface{}{"id": float64(1)}
+		b := map[string]interface{}{"id": float64(1), "extra": true}
+		diffs := validator.DiffValues(a, b, nil)
+		Expect(diffs).To(HaveLen(1))
+		Expect(diffs[0].Path).To(Equal("extra"))
+		Expect(diffs[0].Expected).To(BeNil())
+		Expect(diffs[0].Actual).To(Equal(true))
+	})
+
+	It("应该忽略配置的字段及其子字段", func() {
+		a := map[string]interface{}{
+			"id":   float64(1),
+			"meta": map[string]interface{}{"ts": float64(100), "trace": "x"},
+		}
+		b := map[string]interface{}{
+			"id":   float64(1),
+			"meta": map[string]interface{}{"ts": float64(200), "trace": "y"},
+		}
+		Expect(validator.DiffValues(a, b, []string{"meta"})).To(BeEmpty())
+		Expect(validator.DiffValues(a, b, []string{"meta.ts"})).To(HaveLen(1))
+	})
 })